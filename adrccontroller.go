@@ -0,0 +1,148 @@
+package pid
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// ADRCController implements a linear active disturbance rejection controller, with an extended state observer
+// that estimates the output, its derivative and the total disturbance of the process, and a PD controller on the
+// estimated states that cancels the estimated disturbance, as defined in Gao, Scaling and bandwidth-parameterization
+// based controller tuning, 2003 (https://doi.org/10.1109/ACC.2003.1242516).
+//
+// The process is modeled as a chain of integrators of the configured order, y^(n) = b0 u + f, where f lumps the
+// unknown dynamics and disturbances of the process. The observer is discretized in the current estimator form of
+// Miklosovic, Radke and Gao, Discrete implementation and generalization of the extended state observer, 2006
+// (https://doi.org/10.1109/ACC.2006.1656474), with all poles at exp(-ωo dt) for the observer bandwidth ωo, and the
+// controller places all closed-loop poles at -ωc for the controller bandwidth ωc.
+//
+// The observer is driven by the saturated control signal, so that the disturbance estimate does not wind up
+// during saturation. The observer starts at rest, so the controller should be engaged close to zero actual signal
+// or with a low observer bandwidth to avoid a large initial transient.
+type ADRCController struct {
+	// Config for the ADRCController.
+	Config ADRCControllerConfig
+	// State of the ADRCController.
+	State ADRCControllerState
+}
+
+// ADRCControllerConfig contains config parameters for an ADRCController.
+type ADRCControllerConfig struct {
+	// Order is the order n of the process model, 1 or 2.
+	Order int
+	// InputGain is the estimate b0 of the gain from the control signal to the n-th derivative of the actual
+	// signal, which for a first-order process K / (T s + 1) is K / T.
+	InputGain float64
+	// ObserverBandwidth is the observer bandwidth ωo (rad/s), typically 3 to 10 times the controller bandwidth.
+	ObserverBandwidth float64
+	// ControllerBandwidth is the controller bandwidth ωc (rad/s).
+	ControllerBandwidth float64
+	// MaxOutput is the max output from the controller.
+	MaxOutput float64
+	// MinOutput is the min output from the controller.
+	MinOutput float64
+}
+
+// ADRCControllerState holds mutable state for an ADRCController.
+type ADRCControllerState struct {
+	// ControlError is the difference between reference and current value.
+	ControlError float64
+	// EstimatedSignal is the observer estimate of the actual signal.
+	EstimatedSignal float64
+	// EstimatedSignalDerivative is the observer estimate of the time-derivative of the actual signal, which is
+	// only estimated for second-order processes.
+	EstimatedSignalDerivative float64
+	// EstimatedDisturbance is the observer estimate of the total disturbance f.
+	EstimatedDisturbance float64
+	// ControlSignal is the current control signal output of the controller.
+	ControlSignal float64
+	// UnsaturatedControlSignal is the control signal before saturation.
+	UnsaturatedControlSignal float64
+}
+
+// ADRCControllerInput holds the input parameters to an ADRCController.
+type ADRCControllerInput struct {
+	// ReferenceSignal is the reference value for the signal to control.
+	ReferenceSignal float64
+	// ActualSignal is the actual value of the signal to control.
+	ActualSignal float64
+	// FeedForwardSignal is the contribution of the feed-forward control loop in the controller output.
+	FeedForwardSignal float64
+	// SamplingInterval is the time interval elapsed since the previous call of the controller Update method.
+	SamplingInterval time.Duration
+}
+
+// Validate returns an error when the order is not 1 or 2, when the input gain is zero, NaN or infinite, when a
+// bandwidth is not positive and finite, or when MinOutput is larger than MaxOutput.
+func (c ADRCControllerConfig) Validate() error {
+	if err := validateFinite(
+		finiteParameter[float64]{"input gain", c.InputGain},
+		finiteParameter[float64]{"observer bandwidth", c.ObserverBandwidth},
+		finiteParameter[float64]{"controller bandwidth", c.ControllerBandwidth},
+	); err != nil {
+		return err
+	}
+	switch {
+	case c.Order != 1 && c.Order != 2:
+		return fmt.Errorf("pid: invalid config: order %d is not 1 or 2", c.Order)
+	case c.InputGain == 0:
+		return fmt.Errorf("pid: invalid config: zero input gain")
+	case c.ObserverBandwidth <= 0:
+		return fmt.Errorf("pid: invalid config: non-positive observer bandwidth %v", c.ObserverBandwidth)
+	case c.ControllerBandwidth <= 0:
+		return fmt.Errorf("pid: invalid config: non-positive controller bandwidth %v", c.ControllerBandwidth)
+	case math.IsNaN(c.MinOutput) || math.IsNaN(c.MaxOutput):
+		return fmt.Errorf("pid: invalid config: NaN output limit")
+	case c.MinOutput > c.MaxOutput:
+		return fmt.Errorf("pid: invalid config: min output %v larger than max output %v", c.MinOutput, c.MaxOutput)
+	}
+	return nil
+}
+
+// Reset the controller state.
+func (c *ADRCController) Reset() {
+	c.State = ADRCControllerState{}
+}
+
+// Update the controller state.
+//
+// The observer requires a positive sampling interval, and updates without one are ignored.
+func (c *ADRCController) Update(input ADRCControllerInput) {
+	if isNaNOrInf(input.ReferenceSignal) || isNaNOrInf(input.ActualSignal) || input.SamplingInterval <= 0 {
+		return
+	}
+	h := input.SamplingInterval.Seconds()
+	b0, wc := c.Config.InputGain, c.Config.ControllerBandwidth
+	beta := math.Exp(-c.Config.ObserverBandwidth * h)
+	u := c.State.ControlSignal
+	z1, z2, z3 := c.State.EstimatedSignal, c.State.EstimatedSignalDerivative, c.State.EstimatedDisturbance
+	y := input.ActualSignal
+	var u0, disturbance float64
+	// The observer predicts with the control signal of the previous update, and corrects with the actual signal.
+	switch c.Config.Order {
+	case 1:
+		z1 += h * (z3 + b0*u)
+		e := y - z1
+		z1 += (1 - beta*beta) * e
+		z3 += (1 - beta) * (1 - beta) / h * e
+		c.State.EstimatedSignal, c.State.EstimatedDisturbance = z1, z3
+		u0, disturbance = wc*(input.ReferenceSignal-z1), z3
+	case 2:
+		z1, z2 = z1+h*z2+h*h/2*(z3+b0*u), z2+h*(z3+b0*u)
+		e := y - z1
+		z1 += (1 - beta*beta*beta) * e
+		z2 += 1.5 / h * (1 - beta) * (1 - beta) * (1 + beta) * e
+		z3 += (1 - beta) * (1 - beta) * (1 - beta) / (h * h) * e
+		c.State.EstimatedSignal, c.State.EstimatedSignalDerivative, c.State.EstimatedDisturbance = z1, z2, z3
+		u0, disturbance = wc*wc*(input.ReferenceSignal-z1)-2*wc*z2, z3
+	default:
+		return
+	}
+	c.State.UnsaturatedControlSignal = (u0-disturbance)/b0 + input.FeedForwardSignal
+	c.State.ControlSignal = clamp(c.State.UnsaturatedControlSignal, c.Config.MinOutput, c.Config.MaxOutput)
+	c.State.EstimatedSignal = clampFinite(c.State.EstimatedSignal)
+	c.State.EstimatedSignalDerivative = clampFinite(c.State.EstimatedSignalDerivative)
+	c.State.EstimatedDisturbance = clampFinite(c.State.EstimatedDisturbance)
+	c.State.ControlError = clampFinite(input.ReferenceSignal - input.ActualSignal)
+}
@@ -0,0 +1,205 @@
+package pid
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"go.einride.tech/pid/model"
+	"gotest.tools/v3/assert"
+)
+
+// runADRCController runs the controller in closed loop with the process, with a load disturbance at the process
+// input from the given time.
+func runADRCController(
+	t *testing.T,
+	c *ADRCController,
+	process model.Model,
+	duration time.Duration,
+	disturbance float64,
+	disturbanceTime time.Duration,
+) []float64 {
+	t.Helper()
+	plant, err := model.NewSimulation(process)
+	assert.NilError(t, err)
+	const dt = 10 * time.Millisecond
+	var actual []float64
+	for now := time.Duration(0); now < duration; now += dt {
+		actual = append(actual, plant.Output())
+		c.Update(ADRCControllerInput{ReferenceSignal: 1, ActualSignal: plant.Output(), SamplingInterval: dt})
+		d := 0.0
+		if now >= disturbanceTime {
+			d = disturbance
+		}
+		plant.Update(c.State.ControlSignal+d, dt)
+	}
+	return actual
+}
+
+func TestADRCController_Update_FirstOrder(t *testing.T) {
+	// Given an ADRC controller of a first-order process with a input gain K / T = 2
+	c := &ADRCController{Config: ADRCControllerConfig{
+		Order:               1,
+		InputGain:           2,
+		ObserverBandwidth:   20,
+		ControllerBandwidth: 2,
+		MinOutput:           -10,
+		MaxOutput:           10,
+	}}
+	assert.NilError(t, c.Config.Validate())
+	process := model.FOPDT{Gain: 2, TimeConstant: time.Second}
+	// When controlling the process to a reference step, with a load disturbance after 5 s
+	actual := runADRCController(t, c, process, 10*time.Second, 0.5, 5*time.Second)
+	// Then the loop should respond as a first-order system with the controller bandwidth
+	assert.Assert(t, math.Abs(actual[50]-0.632) < 0.05, actual[50])
+	assert.Assert(t, math.Abs(actual[499]-1) < 1e-3, actual[499])
+	// And the disturbance should be rejected without steady-state error
+	assert.Assert(t, math.Abs(actual[len(actual)-1]-1) < 1e-3, actual[len(actual)-1])
+	assert.Assert(t, math.Abs(c.State.ControlSignal-0) < 1e-3, c.State.ControlSignal)
+	// And the estimated disturbance should balance the control signal in steady state
+	assert.Assert(t, math.Abs(c.State.EstimatedDisturbance+2*c.State.ControlSignal) < 1e-3, c.State)
+	assert.Assert(t, math.Abs(c.State.EstimatedSignal-1) < 1e-3, c.State)
+}
+
+func TestADRCController_Update_SecondOrder(t *testing.T) {
+	// Given an ADRC controller of a second-order process, with a input gain that is off by 50%
+	c := &ADRCController{Config: ADRCControllerConfig{
+		Order:               2,
+		InputGain:           1.5,
+		ObserverBandwidth:   30,
+		ControllerBandwidth: 3,
+		MinOutput:           -10,
+		MaxOutput:           10,
+	}}
+	process := model.TransferFunction{Numerator: []float64{1}, Denominator: []float64{1, 1, 0}}
+	// When controlling the process to a reference step, with a load disturbance after 5 s
+	actual := runADRCController(t, c, process, 10*time.Second, -0.5, 5*time.Second)
+	// Then the actual signal should settle without overshoot
+	var maxActual float64
+	for _, y := range actual[:500] {
+		maxActual = math.Max(maxActual, y)
+	}
+	assert.Assert(t, maxActual < 1.05, maxActual)
+	assert.Assert(t, math.Abs(actual[499]-1) < 1e-2, actual[499])
+	// And the disturbance should be rejected
+	assert.Assert(t, math.Abs(actual[len(actual)-1]-1) < 1e-2, actual[len(actual)-1])
+	assert.Assert(t, math.Abs(c.State.ControlSignal-0.5) < 1e-2, c.State.ControlSignal)
+	assert.Assert(t, math.Abs(c.State.EstimatedSignalDerivative) < 1e-2, c.State)
+}
+
+func TestADRCController_Update_Saturation(t *testing.T) {
+	// Given an ADRC controller with tight output limits
+	c := &ADRCController{Config: ADRCControllerConfig{
+		Order:               1,
+		InputGain:           2,
+		ObserverBandwidth:   20,
+		ControllerBandwidth: 5,
+		MinOutput:           -0.6,
+		MaxOutput:           0.6,
+	}}
+	// When controlling the process through saturation
+	actual := runADRCController(t, c, model.FOPDT{Gain: 2, TimeConstant: time.Second}, 10*time.Second, 0, 0)
+	// Then the actual signal should settle without overshoot, since the observer tracks the saturated control
+	var maxActual float64
+	for _, y := range actual {
+		maxActual = math.Max(maxActual, y)
+	}
+	assert.Assert(t, maxActual < 1.001, maxActual)
+	assert.Assert(t, math.Abs(actual[len(actual)-1]-1) < 1e-3, actual[len(actual)-1])
+}
+
+func TestADRCController_Update_FeedForward(t *testing.T) {
+	// Given an ADRC controller at rest
+	c := &ADRCController{Config: ADRCControllerConfig{
+		Order:               1,
+		InputGain:           2,
+		ObserverBandwidth:   20,
+		ControllerBandwidth: 2,
+		MinOutput:           -10,
+		MaxOutput:           10,
+	}}
+	// When updating with a feed forward signal
+	c.Update(ADRCControllerInput{FeedForwardSignal: 3, SamplingInterval: 10 * time.Millisecond})
+	// Then the feed forward signal should be added to the control signal
+	assert.Equal(t, 3.0, c.State.ControlSignal)
+	assert.Equal(t, 3.0, c.State.UnsaturatedControlSignal)
+}
+
+func TestADRCController_Update_InvalidInput(t *testing.T) {
+	// Given an ADRC controller with state
+	c := &ADRCController{Config: ADRCControllerConfig{
+		Order:               2,
+		InputGain:           1,
+		ObserverBandwidth:   20,
+		ControllerBandwidth: 2,
+		MinOutput:           -10,
+		MaxOutput:           10,
+	}}
+	c.Update(ADRCControllerInput{ReferenceSignal: 1, SamplingInterval: 10 * time.Millisecond})
+	c.Update(ADRCControllerInput{ReferenceSignal: 1, ActualSignal: 0.1, SamplingInterval: 10 * time.Millisecond})
+	expected := c.State
+	// When updating with invalid inputs
+	c.Update(ADRCControllerInput{ReferenceSignal: math.NaN(), SamplingInterval: 10 * time.Millisecond})
+	c.Update(ADRCControllerInput{ReferenceSignal: 1, ActualSignal: math.Inf(1), SamplingInterval: time.Second})
+	c.Update(ADRCControllerInput{ReferenceSignal: 1})
+	// Then the state should be unchanged
+	assert.Equal(t, expected, c.State)
+	// When resetting the controller
+	c.Reset()
+	// Then the state should be zero
+	assert.Equal(t, ADRCControllerState{}, c.State)
+}
+
+func TestADRCControllerConfig_Validate(t *testing.T) {
+	valid := ADRCControllerConfig{
+		Order:               2,
+		InputGain:           1,
+		ObserverBandwidth:   20,
+		ControllerBandwidth: 2,
+		MinOutput:           math.Inf(-1),
+		MaxOutput:           math.Inf(1),
+	}
+	assert.NilError(t, valid.Validate())
+	for _, tt := range []struct {
+		name     string
+		modify   func(*ADRCControllerConfig)
+		expected string
+	}{
+		{
+			name:     "order",
+			modify:   func(c *ADRCControllerConfig) { c.Order = 3 },
+			expected: "order 3 is not 1 or 2",
+		},
+		{
+			name:     "zero input gain",
+			modify:   func(c *ADRCControllerConfig) { c.InputGain = 0 },
+			expected: "zero input gain",
+		},
+		{
+			name:     "NaN input gain",
+			modify:   func(c *ADRCControllerConfig) { c.InputGain = math.NaN() },
+			expected: "input gain",
+		},
+		{
+			name:     "observer bandwidth",
+			modify:   func(c *ADRCControllerConfig) { c.ObserverBandwidth = 0 },
+			expected: "non-positive observer bandwidth 0",
+		},
+		{
+			name:     "controller bandwidth",
+			modify:   func(c *ADRCControllerConfig) { c.ControllerBandwidth = -1 },
+			expected: "non-positive controller bandwidth -1",
+		},
+		{
+			name:     "output limits",
+			modify:   func(c *ADRCControllerConfig) { c.MinOutput, c.MaxOutput = 1, -1 },
+			expected: "min output 1 larger than max output -1",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			config := valid
+			tt.modify(&config)
+			assert.ErrorContains(t, config.Validate(), "pid: invalid config: "+tt.expected)
+		})
+	}
+}
@@ -1,7 +1,6 @@
 package pid
 
 import (
-	"math"
 	"time"
 )
 
@@ -14,93 +13,131 @@ import (
 //
 // The ControlError, ControlErrorIntegrand, ControlErrorIntegral and ControlErrorDerivative are prevented
 // from reaching +/- inf by clamping them to [-math.MaxFloat64, math.MaxFloat64].
-type AntiWindupController struct {
+type AntiWindupController = AntiWindupControllerOf[float64]
+
+// AntiWindupControllerConfig contains config parameters for a AntiWindupController.
+type AntiWindupControllerConfig = AntiWindupControllerConfigOf[float64]
+
+// AntiWindupControllerState holds mutable state for a AntiWindupController.
+type AntiWindupControllerState = AntiWindupControllerStateOf[float64]
+
+// AntiWindupControllerInput holds the input parameters to an AntiWindupController.
+type AntiWindupControllerInput = AntiWindupControllerInputOf[float64]
+
+// AntiWindupControllerOf implements an AntiWindupController generic over the floating-point type T.
+//
+// The ControlError, ControlErrorIntegrand, ControlErrorIntegral and ControlErrorDerivative are prevented
+// from reaching +/- inf by clamping them to the largest finite value of T.
+type AntiWindupControllerOf[T Float] struct {
 	// Config for the AntiWindupController.
-	Config AntiWindupControllerConfig
+	Config AntiWindupControllerConfigOf[T]
 	// State of the AntiWindupController.
-	State AntiWindupControllerState
+	State AntiWindupControllerStateOf[T]
 }
 
-// AntiWindupControllerConfig contains config parameters for a AntiWindupController.
-type AntiWindupControllerConfig struct {
+// AntiWindupControllerConfigOf contains config parameters for an AntiWindupControllerOf.
+type AntiWindupControllerConfigOf[T Float] struct {
 	// ProportionalGain is the P part gain.
-	ProportionalGain float64
+	ProportionalGain T
 	// IntegralGain is the I part gain.
-	IntegralGain float64
+	IntegralGain T
 	// DerivativeGain is the D part gain.
-	DerivativeGain float64
+	DerivativeGain T
 	// AntiWindUpGain is the anti-windup tracking gain.
-	AntiWindUpGain float64
+	AntiWindUpGain T
 	// IntegralDischargeTimeConstant is the time constant to discharge the integral state of the PID controller (s)
-	IntegralDischargeTimeConstant float64
+	IntegralDischargeTimeConstant T
 	// LowPassTimeConstant is the D part low-pass filter time constant => cut-off frequency 1/LowPassTimeConstant.
 	LowPassTimeConstant time.Duration
 	// MaxOutput is the max output from the PID.
-	MaxOutput float64
+	MaxOutput T
 	// MinOutput is the min output from the PID.
-	MinOutput float64
+	MinOutput T
 }
 
-// AntiWindupControllerState holds mutable state for a AntiWindupController.
-type AntiWindupControllerState struct {
+// AntiWindupControllerStateOf holds mutable state for an AntiWindupControllerOf.
+type AntiWindupControllerStateOf[T Float] struct {
 	// ControlError is the difference between reference and current value.
-	ControlError float64
+	ControlError T
 	// ControlErrorIntegrand is the control error integrand, which includes the anti-windup correction.
-	ControlErrorIntegrand float64
+	ControlErrorIntegrand T
 	// ControlErrorIntegral is the control error integrand integrated over time.
-	ControlErrorIntegral float64
+	ControlErrorIntegral T
 	// ControlErrorDerivative is the low-pass filtered time-derivative of the control error.
-	ControlErrorDerivative float64
+	ControlErrorDerivative T
 	// ControlSignal is the current control signal output of the controller.
-	ControlSignal float64
+	ControlSignal T
 	// UnsaturatedControlSignal is the control signal before saturation.
-	UnsaturatedControlSignal float64
+	UnsaturatedControlSignal T
 }
 
-// AntiWindupControllerInput holds the input parameters to an AntiWindupController.
-type AntiWindupControllerInput struct {
+// AntiWindupControllerInputOf holds the input parameters to an AntiWindupControllerOf.
+type AntiWindupControllerInputOf[T Float] struct {
 	// ReferenceSignal is the reference value for the signal to control.
-	ReferenceSignal float64
+	ReferenceSignal T
 	// ActualSignal is the actual value of the signal to control.
-	ActualSignal float64
+	ActualSignal T
 	// FeedForwardSignal is the contribution of the feed-forward control loop in the controller output.
-	FeedForwardSignal float64
+	FeedForwardSignal T
 	// SamplingInterval is the time interval elapsed since the previous call of the controller Update method.
 	SamplingInterval time.Duration
 }
 
 // Reset the controller state.
-func (c *AntiWindupController) Reset() {
-	c.State = AntiWindupControllerState{}
+func (c *AntiWindupControllerOf[T]) Reset() {
+	c.State = AntiWindupControllerStateOf[T]{}
 }
 
 // Update the controller state.
-func (c *AntiWindupController) Update(input AntiWindupControllerInput) {
-	if math.IsNaN(input.ReferenceSignal) || math.IsNaN(input.ActualSignal) ||
-		math.IsInf(input.ReferenceSignal, 0) || math.IsInf(input.ActualSignal, 0) {
+func (c *AntiWindupControllerOf[T]) Update(input AntiWindupControllerInputOf[T]) {
+	if isNaNOrInf(input.ReferenceSignal) || isNaNOrInf(input.ActualSignal) {
 		return
 	}
 
+	dt := T(input.SamplingInterval.Seconds())
+	lowPassTimeConstant := T(c.Config.LowPassTimeConstant.Seconds())
 	e := input.ReferenceSignal - input.ActualSignal
-	controlErrorIntegral := c.State.ControlErrorIntegrand*input.SamplingInterval.Seconds() + c.State.ControlErrorIntegral
-	controlErrorDerivative := ((1/c.Config.LowPassTimeConstant.Seconds())*(e-c.State.ControlError) +
-		c.State.ControlErrorDerivative) / (input.SamplingInterval.Seconds()/c.Config.LowPassTimeConstant.Seconds() + 1)
+	controlErrorIntegral := c.State.ControlErrorIntegrand*dt + c.State.ControlErrorIntegral
+	controlErrorDerivative := ((1/lowPassTimeConstant)*(e-c.State.ControlError) +
+		c.State.ControlErrorDerivative) / (dt/lowPassTimeConstant + 1)
 	c.State.UnsaturatedControlSignal = e*c.Config.ProportionalGain + c.Config.IntegralGain*controlErrorIntegral +
 		c.Config.DerivativeGain*controlErrorDerivative + input.FeedForwardSignal
-	c.State.ControlSignal = math.Max(c.Config.MinOutput, math.Min(c.Config.MaxOutput, c.State.UnsaturatedControlSignal))
+	c.State.ControlSignal = clamp(c.State.UnsaturatedControlSignal, c.Config.MinOutput, c.Config.MaxOutput)
 	c.State.ControlErrorIntegrand = e + c.Config.AntiWindUpGain*(c.State.ControlSignal-c.State.UnsaturatedControlSignal)
-	c.State.ControlErrorIntegrand = math.Max(-math.MaxFloat64, math.Min(math.MaxFloat64, c.State.ControlErrorIntegrand))
-	c.State.ControlErrorIntegral = math.Max(-math.MaxFloat64, math.Min(math.MaxFloat64, controlErrorIntegral))
-	c.State.ControlErrorDerivative = math.Max(-math.MaxFloat64, math.Min(math.MaxFloat64, controlErrorDerivative))
-	c.State.ControlError = math.Max(-math.MaxFloat64, math.Min(math.MaxFloat64, e))
+	c.State.ControlErrorIntegrand = clampFinite(c.State.ControlErrorIntegrand)
+	c.State.ControlErrorIntegral = clampFinite(controlErrorIntegral)
+	c.State.ControlErrorDerivative = clampFinite(controlErrorDerivative)
+	c.State.ControlError = clampFinite(e)
 }
 
 // DischargeIntegral provides the ability to discharge the controller integral state
 // over a configurable period of time.
-func (c *AntiWindupController) DischargeIntegral(dt time.Duration) {
+func (c *AntiWindupControllerOf[T]) DischargeIntegral(dt time.Duration) {
 	c.State.ControlErrorIntegrand = 0.0
-	c.State.ControlErrorIntegral = math.Max(
-		0,
-		math.Min(1-dt.Seconds()/c.Config.IntegralDischargeTimeConstant, 1.0),
+	c.State.ControlErrorIntegral = clamp(
+		1-T(dt.Seconds())/c.Config.IntegralDischargeTimeConstant, 0, 1.0,
 	) * c.State.ControlErrorIntegral
 }
+
+// BumplessTransfer swaps the controller config without a bump in the control signal.
+//
+// The integral state is adjusted so that the P, I and D terms of the new config sum to the same control signal as
+// those of the old config at the current control error and derivative. When the new IntegralGain is zero, the
+// integral cannot absorb the difference and the config is swapped without adjustment.
+func (c *AntiWindupControllerOf[T]) BumplessTransfer(config AntiWindupControllerConfigOf[T]) {
+	c.State.ControlErrorIntegral = bumplessIntegral(
+		c.Config.ProportionalGain, c.Config.IntegralGain, c.Config.DerivativeGain,
+		config.ProportionalGain, config.IntegralGain, config.DerivativeGain,
+		c.State.ControlError, c.State.ControlErrorIntegral, c.State.ControlErrorDerivative,
+	)
+	c.Config = config
+}
+
+// bumplessIntegral returns the integral state for which the PID terms with the new gains equal the PID terms
+// with the old gains.
+func bumplessIntegral[T Float](kp, ki, kd, newKp, newKi, newKd, e, integral, derivative T) T {
+	if newKi == 0 {
+		return integral
+	}
+	return clampFinite((ki*integral + (kp-newKp)*e + (kd-newKd)*derivative) / newKi)
+}
@@ -275,3 +275,51 @@ func TestAntiWindupController_OffloadIntegralTerm(t *testing.T) {
 	// Then
 	assert.Equal(t, c.State, expected)
 }
+
+func TestAntiWindupController_BumplessTransfer(t *testing.T) {
+	// Given a PID controller with a charged integral
+	c := &AntiWindupController{
+		Config: AntiWindupControllerConfig{
+			LowPassTimeConstant: 1 * time.Second,
+			ProportionalGain:    1,
+			IntegralGain:        0.5,
+			DerivativeGain:      0.1,
+			MinOutput:           -100,
+			MaxOutput:           100,
+		},
+	}
+	for i := range 100 {
+		c.Update(AntiWindupControllerInput{
+			ReferenceSignal:  1,
+			ActualSignal:     float64(i) / 200,
+			SamplingInterval: dtTest,
+		})
+	}
+	terms := func() float64 {
+		return c.Config.ProportionalGain*c.State.ControlError + c.Config.IntegralGain*c.State.ControlErrorIntegral +
+			c.Config.DerivativeGain*c.State.ControlErrorDerivative
+	}
+	expected := terms()
+	// When swapping to more aggressive gains with bumpless transfer
+	config := c.Config
+	config.ProportionalGain = 3
+	config.IntegralGain = 2
+	config.DerivativeGain = 0.5
+	c.BumplessTransfer(config)
+	// Then the new config should be in effect
+	assert.Equal(t, config, c.Config)
+	// And the PID terms should sum to the same control signal
+	assert.Assert(t, math.Abs(expected-terms()) < 1e-12)
+}
+
+func TestAntiWindupController_BumplessTransfer_ZeroIntegralGain(t *testing.T) {
+	// Given a PI controller with a charged integral
+	c := &AntiWindupController{
+		Config: AntiWindupControllerConfig{ProportionalGain: 1, IntegralGain: 1},
+		State:  AntiWindupControllerState{ControlError: 1, ControlErrorIntegral: 2},
+	}
+	// When swapping to a P controller
+	c.BumplessTransfer(AntiWindupControllerConfig{ProportionalGain: 2})
+	// Then the integral should be left as is
+	assert.Equal(t, 2.0, c.State.ControlErrorIntegral)
+}
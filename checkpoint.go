@@ -0,0 +1,206 @@
+package pid
+
+import (
+	"encoding"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"math"
+	"os"
+	"path/filepath"
+)
+
+// checkpointVersion is the version of the binary state checkpoint format.
+//
+// The format is fixed-layout and little-endian:
+//
+//	[0]      format version
+//	[1]      state kind
+//	[2:2+8n] n IEEE 754 float64 state fields, in struct declaration order
+//	[-4:]    CRC-32 (IEEE) checksum of all preceding bytes
+//
+// State fields are encoded as float64 regardless of T, so that checkpoints are interchangeable between
+// instantiations.
+const checkpointVersion = 1
+
+// checkpointKind identifies the state type stored in a binary checkpoint.
+type checkpointKind uint8
+
+const (
+	checkpointKindControllerState checkpointKind = iota + 1
+	checkpointKindAntiWindupControllerState
+	checkpointKindTrackingControllerState
+)
+
+const (
+	checkpointHeaderSize   = 2
+	checkpointChecksumSize = 4
+)
+
+func marshalCheckpoint(kind checkpointKind, fields ...float64) []byte {
+	data := make([]byte, checkpointHeaderSize, checkpointHeaderSize+8*len(fields)+checkpointChecksumSize)
+	data[0] = checkpointVersion
+	data[1] = byte(kind)
+	for _, field := range fields {
+		data = binary.LittleEndian.AppendUint64(data, math.Float64bits(field))
+	}
+	return binary.LittleEndian.AppendUint32(data, crc32.ChecksumIEEE(data))
+}
+
+func unmarshalCheckpoint(data []byte, kind checkpointKind, fields []float64) error {
+	if expected := checkpointHeaderSize + 8*len(fields) + checkpointChecksumSize; len(data) != expected {
+		return fmt.Errorf("pid: unmarshal checkpoint: invalid length %d, expected %d", len(data), expected)
+	}
+	payload, checksum := data[:len(data)-checkpointChecksumSize], data[len(data)-checkpointChecksumSize:]
+	if crc32.ChecksumIEEE(payload) != binary.LittleEndian.Uint32(checksum) {
+		return fmt.Errorf("pid: unmarshal checkpoint: checksum mismatch")
+	}
+	if payload[0] != checkpointVersion {
+		return fmt.Errorf("pid: unmarshal checkpoint: unsupported version %d", payload[0])
+	}
+	if checkpointKind(payload[1]) != kind {
+		return fmt.Errorf("pid: unmarshal checkpoint: unexpected state kind %d, expected %d", payload[1], kind)
+	}
+	payload = payload[checkpointHeaderSize:]
+	for i := range fields {
+		fields[i] = math.Float64frombits(binary.LittleEndian.Uint64(payload[8*i:]))
+	}
+	return nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (s ControllerStateOf[T]) MarshalBinary() ([]byte, error) {
+	return marshalCheckpoint(
+		checkpointKindControllerState,
+		float64(s.ControlError),
+		float64(s.ControlErrorIntegral),
+		float64(s.ControlErrorDerivative),
+		float64(s.ControlSignal),
+	), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (s *ControllerStateOf[T]) UnmarshalBinary(data []byte) error {
+	var fields [4]float64
+	if err := unmarshalCheckpoint(data, checkpointKindControllerState, fields[:]); err != nil {
+		return err
+	}
+	*s = ControllerStateOf[T]{
+		ControlError:           T(fields[0]),
+		ControlErrorIntegral:   T(fields[1]),
+		ControlErrorDerivative: T(fields[2]),
+		ControlSignal:          T(fields[3]),
+	}
+	return nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (s AntiWindupControllerStateOf[T]) MarshalBinary() ([]byte, error) {
+	return marshalCheckpoint(
+		checkpointKindAntiWindupControllerState,
+		float64(s.ControlError),
+		float64(s.ControlErrorIntegrand),
+		float64(s.ControlErrorIntegral),
+		float64(s.ControlErrorDerivative),
+		float64(s.ControlSignal),
+		float64(s.UnsaturatedControlSignal),
+	), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (s *AntiWindupControllerStateOf[T]) UnmarshalBinary(data []byte) error {
+	var fields [6]float64
+	if err := unmarshalCheckpoint(data, checkpointKindAntiWindupControllerState, fields[:]); err != nil {
+		return err
+	}
+	*s = AntiWindupControllerStateOf[T]{
+		ControlError:             T(fields[0]),
+		ControlErrorIntegrand:    T(fields[1]),
+		ControlErrorIntegral:     T(fields[2]),
+		ControlErrorDerivative:   T(fields[3]),
+		ControlSignal:            T(fields[4]),
+		UnsaturatedControlSignal: T(fields[5]),
+	}
+	return nil
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler.
+func (s TrackingControllerStateOf[T]) MarshalBinary() ([]byte, error) {
+	return marshalCheckpoint(
+		checkpointKindTrackingControllerState,
+		float64(s.ControlError),
+		float64(s.ControlErrorIntegrand),
+		float64(s.ControlErrorIntegral),
+		float64(s.ControlErrorDerivative),
+		float64(s.ControlSignal),
+		float64(s.UnsaturatedControlSignal),
+	), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler.
+func (s *TrackingControllerStateOf[T]) UnmarshalBinary(data []byte) error {
+	var fields [6]float64
+	if err := unmarshalCheckpoint(data, checkpointKindTrackingControllerState, fields[:]); err != nil {
+		return err
+	}
+	*s = TrackingControllerStateOf[T]{
+		ControlError:             T(fields[0]),
+		ControlErrorIntegrand:    T(fields[1]),
+		ControlErrorIntegral:     T(fields[2]),
+		ControlErrorDerivative:   T(fields[3]),
+		ControlSignal:            T(fields[4]),
+		UnsaturatedControlSignal: T(fields[5]),
+	}
+	return nil
+}
+
+// WriteCheckpointFile atomically writes a binary checkpoint of the provided state to the named file.
+//
+// The checkpoint is first written and synced to a temporary file in the same directory, which is then renamed
+// to the named file, so that a crash during the write never leaves a partially written checkpoint behind.
+func WriteCheckpointFile(name string, state encoding.BinaryMarshaler) (err error) {
+	data, err := state.MarshalBinary()
+	if err != nil {
+		return fmt.Errorf("pid: write checkpoint file %s: %w", name, err)
+	}
+	f, err := os.CreateTemp(filepath.Dir(name), "."+filepath.Base(name)+".tmp*")
+	if err != nil {
+		return fmt.Errorf("pid: write checkpoint file %s: %w", name, err)
+	}
+	defer func() {
+		if err != nil {
+			_ = f.Close()
+			_ = os.Remove(f.Name())
+		}
+	}()
+	if _, err := f.Write(data); err != nil {
+		return fmt.Errorf("pid: write checkpoint file %s: %w", name, err)
+	}
+	if err := f.Sync(); err != nil {
+		return fmt.Errorf("pid: write checkpoint file %s: %w", name, err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("pid: write checkpoint file %s: %w", name, err)
+	}
+	if err := os.Rename(f.Name(), name); err != nil {
+		return fmt.Errorf("pid: write checkpoint file %s: %w", name, err)
+	}
+	// Sync the parent directory to persist the rename. Not all platforms support this, so errors are ignored.
+	if dir, err := os.Open(filepath.Dir(name)); err == nil {
+		_ = dir.Sync()
+		_ = dir.Close()
+	}
+	return nil
+}
+
+// ReadCheckpointFile reads a binary checkpoint from the named file into the provided state.
+func ReadCheckpointFile(name string, state encoding.BinaryUnmarshaler) error {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return fmt.Errorf("pid: read checkpoint file %s: %w", name, err)
+	}
+	if err := state.UnmarshalBinary(data); err != nil {
+		return fmt.Errorf("pid: read checkpoint file %s: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+package pid
+
+import (
+	"encoding"
+	"encoding/binary"
+	"hash/crc32"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestState_BinaryRoundTrip(t *testing.T) {
+	for _, tt := range []struct {
+		name  string
+		state encoding.BinaryMarshaler
+		into  encoding.BinaryUnmarshaler
+	}{
+		{
+			name: "ControllerState",
+			state: ControllerState{
+				ControlError:           1,
+				ControlErrorIntegral:   2,
+				ControlErrorDerivative: -3,
+				ControlSignal:          4.5,
+			},
+			into: &ControllerState{},
+		},
+		{
+			name: "AntiWindupControllerState",
+			state: AntiWindupControllerState{
+				ControlError:             1,
+				ControlErrorIntegrand:    2,
+				ControlErrorIntegral:     -3,
+				ControlErrorDerivative:   4,
+				ControlSignal:            10,
+				UnsaturatedControlSignal: 12.5,
+			},
+			into: &AntiWindupControllerState{},
+		},
+		{
+			name: "TrackingControllerState",
+			state: TrackingControllerState{
+				ControlError:             1,
+				ControlErrorIntegrand:    2,
+				ControlErrorIntegral:     -3,
+				ControlErrorDerivative:   4,
+				ControlSignal:            10,
+				UnsaturatedControlSignal: 12.5,
+			},
+			into: &TrackingControllerState{},
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := tt.state.MarshalBinary()
+			assert.NilError(t, err)
+			assert.NilError(t, tt.into.UnmarshalBinary(data))
+			switch into := tt.into.(type) {
+			case *ControllerState:
+				assert.Equal(t, tt.state, *into)
+			case *AntiWindupControllerState:
+				assert.Equal(t, tt.state, *into)
+			case *TrackingControllerState:
+				assert.Equal(t, tt.state, *into)
+			}
+		})
+	}
+}
+
+func TestState_UnmarshalBinaryErrors(t *testing.T) {
+	data, err := AntiWindupControllerState{ControlSignal: 1}.MarshalBinary()
+	assert.NilError(t, err)
+	t.Run("checksum", func(t *testing.T) {
+		corrupted := append([]byte(nil), data...)
+		corrupted[10] ^= 0xff
+		var s AntiWindupControllerState
+		assert.ErrorContains(t, s.UnmarshalBinary(corrupted), "checksum mismatch")
+	})
+	t.Run("length", func(t *testing.T) {
+		var s AntiWindupControllerState
+		assert.ErrorContains(t, s.UnmarshalBinary(data[:len(data)-1]), "invalid length")
+	})
+	t.Run("kind", func(t *testing.T) {
+		var s TrackingControllerState
+		assert.ErrorContains(t, s.UnmarshalBinary(data), "unexpected state kind")
+	})
+	t.Run("version", func(t *testing.T) {
+		future := marshalCheckpoint(checkpointKindAntiWindupControllerState, make([]float64, 6)...)
+		future[0] = checkpointVersion + 1
+		payload := future[:len(future)-checkpointChecksumSize]
+		future = binary.LittleEndian.AppendUint32(payload, crc32.ChecksumIEEE(payload))
+		var s AntiWindupControllerState
+		assert.ErrorContains(t, s.UnmarshalBinary(future), "unsupported version")
+	})
+	t.Run("unmodified on error", func(t *testing.T) {
+		s := AntiWindupControllerState{ControlSignal: 42}
+		assert.Assert(t, s.UnmarshalBinary(nil) != nil)
+		assert.Equal(t, AntiWindupControllerState{ControlSignal: 42}, s)
+	})
+}
+
+func TestCheckpointFile(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "controller.checkpoint")
+	expected := TrackingControllerState{ControlErrorIntegral: 3.5, ControlSignal: 1}
+	// When writing a checkpoint twice
+	assert.NilError(t, WriteCheckpointFile(name, TrackingControllerState{}))
+	assert.NilError(t, WriteCheckpointFile(name, expected))
+	// Then the latest checkpoint should be loaded
+	var actual TrackingControllerState
+	assert.NilError(t, ReadCheckpointFile(name, &actual))
+	assert.Equal(t, expected, actual)
+	// And no temporary files should be left behind
+	entries, err := os.ReadDir(filepath.Dir(name))
+	assert.NilError(t, err)
+	assert.Equal(t, 1, len(entries))
+}
+
+func TestCheckpointFile_NotExist(t *testing.T) {
+	var s ControllerState
+	err := ReadCheckpointFile(filepath.Join(t.TempDir(), "missing"), &s)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
@@ -0,0 +1,153 @@
+package pid
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock provides the time base for running controllers.
+type Clock interface {
+	// Now returns the current time.
+	Now() time.Time
+	// After waits for the duration to elapse and then sends the current time on the returned channel.
+	After(d time.Duration) <-chan time.Time
+}
+
+// SystemClock is a Clock backed by the system clock.
+type SystemClock struct{}
+
+var _ Clock = SystemClock{}
+
+// Now implements Clock.
+func (SystemClock) Now() time.Time {
+	return time.Now()
+}
+
+// After implements Clock.
+func (SystemClock) After(d time.Duration) <-chan time.Time {
+	return time.After(d)
+}
+
+// FakeClock is a deterministic Clock for tests that only advances when told to.
+//
+// The zero value is not usable, create a FakeClock with NewFakeClock.
+type FakeClock struct {
+	mu      sync.Mutex
+	cond    *sync.Cond
+	now     time.Time
+	waiters []fakeClockWaiter
+}
+
+type fakeClockWaiter struct {
+	deadline time.Time
+	c        chan time.Time
+}
+
+var _ Clock = &FakeClock{}
+
+// NewFakeClock creates a new FakeClock set to the provided time.
+func NewFakeClock(now time.Time) *FakeClock {
+	c := &FakeClock{now: now}
+	c.cond = sync.NewCond(&c.mu)
+	return c
+}
+
+// Now implements Clock.
+func (c *FakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// After implements Clock.
+//
+// The returned channel receives the time when the clock has been advanced by at least d.
+func (c *FakeClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ch := make(chan time.Time, 1)
+	if d <= 0 {
+		ch <- c.now
+		return ch
+	}
+	c.waiters = append(c.waiters, fakeClockWaiter{deadline: c.now.Add(d), c: ch})
+	c.cond.Broadcast()
+	return ch
+}
+
+// Advance the clock by d, firing all waiters that become due.
+func (c *FakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.set(c.now.Add(d))
+}
+
+// Set the clock to t, firing all waiters that become due.
+func (c *FakeClock) Set(t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.set(t)
+}
+
+func (c *FakeClock) set(t time.Time) {
+	c.now = t
+	remaining := c.waiters[:0]
+	for _, w := range c.waiters {
+		if w.deadline.After(t) {
+			remaining = append(remaining, w)
+			continue
+		}
+		w.c <- t
+	}
+	clear(c.waiters[len(remaining):])
+	c.waiters = remaining
+	c.cond.Broadcast()
+}
+
+// Waiters returns the number of pending calls to After that have not yet fired.
+func (c *FakeClock) Waiters() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.waiters)
+}
+
+// BlockUntilWaiters blocks until at least n calls to After are pending.
+//
+// This is used to synchronize tests with goroutines, such as a running Loop, that wait on the clock.
+func (c *FakeClock) BlockUntilWaiters(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for len(c.waiters) < n {
+		c.cond.Wait()
+	}
+}
+
+// Stopwatch measures the sampling interval between controller updates on a Clock.
+type Stopwatch struct {
+	// Clock is the time base of the Stopwatch. Defaults to SystemClock when nil.
+	Clock Clock
+
+	previous time.Time
+	started  bool
+}
+
+// Lap returns the time elapsed since the previous call to Lap, and false on the first call when no interval has
+// been measured yet.
+func (s *Stopwatch) Lap() (time.Duration, bool) {
+	clock := s.Clock
+	if clock == nil {
+		clock = SystemClock{}
+	}
+	now := clock.Now()
+	previous, started := s.previous, s.started
+	s.previous, s.started = now, true
+	if !started {
+		return 0, false
+	}
+	return now.Sub(previous), true
+}
+
+// Reset the Stopwatch, so that the next call to Lap starts a new measurement.
+func (s *Stopwatch) Reset() {
+	s.previous, s.started = time.Time{}, false
+}
@@ -0,0 +1,62 @@
+package pid
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestFakeClock_After(t *testing.T) {
+	// Given a fake clock with a pending waiter
+	start := time.Unix(1000, 0)
+	c := NewFakeClock(start)
+	after := c.After(10 * time.Millisecond)
+	assert.Equal(t, 1, c.Waiters())
+	// When advancing the clock less than the wait duration
+	c.Advance(9 * time.Millisecond)
+	// Then the waiter should not fire
+	select {
+	case <-after:
+		t.Fatal("waiter fired early")
+	default:
+	}
+	// When advancing the clock past the wait duration
+	c.Advance(2 * time.Millisecond)
+	// Then the waiter should fire with the current time
+	assert.Equal(t, start.Add(11*time.Millisecond), <-after)
+	assert.Equal(t, 0, c.Waiters())
+	// And non-positive durations should fire immediately
+	assert.Equal(t, c.Now(), <-c.After(0))
+}
+
+func TestFakeClock_BlockUntilWaiters(t *testing.T) {
+	c := NewFakeClock(time.Unix(0, 0))
+	done := make(chan time.Time)
+	go func() {
+		done <- <-c.After(time.Second)
+	}()
+	c.BlockUntilWaiters(1)
+	c.Set(time.Unix(2, 0))
+	assert.Equal(t, time.Unix(2, 0), <-done)
+}
+
+func TestStopwatch_Lap(t *testing.T) {
+	// Given a stopwatch on a fake clock at the zero time
+	c := NewFakeClock(time.Time{})
+	s := Stopwatch{Clock: c}
+	// Then the first lap should not measure an interval
+	_, ok := s.Lap()
+	assert.Assert(t, !ok)
+	// And subsequent laps should measure the exact variable intervals
+	for _, expected := range []time.Duration{10 * time.Millisecond, 13 * time.Millisecond, time.Second} {
+		c.Advance(expected)
+		actual, ok := s.Lap()
+		assert.Assert(t, ok)
+		assert.Equal(t, expected, actual)
+	}
+	// And resetting should start a new measurement
+	s.Reset()
+	_, ok = s.Lap()
+	assert.Assert(t, !ok)
+}
@@ -0,0 +1,82 @@
+// Command pidreport generates a self-contained HTML performance report of a recorded control loop trace.
+//
+// The trace is read from a CSV file in the format written by pidsim, and the controller config and an optional
+// process model are read from a JSON or YAML config file in the format read by pidsim. Frequency-domain margins
+// are included in the report when the config has a plant.
+//
+// Usage:
+//
+//	pidreport -i trace.csv -config loop.yaml [-o report.html] [-title "Flow loop"]
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"go.einride.tech/pid/internal/loopconfig"
+	"go.einride.tech/pid/report"
+	"go.einride.tech/pid/sim"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout, os.Stderr); err != nil {
+		fmt.Fprintln(os.Stderr, "pidreport:", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdout, stderr io.Writer) error {
+	flags := flag.NewFlagSet("pidreport", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	input := flags.String("i", "", "trace CSV file")
+	configFile := flags.String("config", "", "loop config file (JSON or YAML)")
+	output := flags.String("o", "", "report HTML output file (default stdout)")
+	title := flags.String("title", "", "report title (default the trace file name)")
+	band := flags.Float64("band", 0.02, "settling band, relative to the step size")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *input == "" || *configFile == "" {
+		return fmt.Errorf("missing -i or -config")
+	}
+	cfg, err := loopconfig.Read(*configFile)
+	if err != nil {
+		return err
+	}
+	f, err := os.Open(*input)
+	if err != nil {
+		return err
+	}
+	trace, err := sim.ReadCSV(f)
+	_ = f.Close()
+	if err != nil {
+		return err
+	}
+	reportConfig := report.Config{
+		Title:        *title,
+		Controller:   cfg.Controller.AntiWindupControllerConfig(),
+		SettlingBand: *band,
+	}
+	if reportConfig.Title == "" {
+		reportConfig.Title = *input
+	}
+	if cfg.Plant.FOPDT != nil || cfg.Plant.TransferFunction != nil {
+		if reportConfig.Model, err = cfg.Plant.Model(); err != nil {
+			return err
+		}
+	}
+	if *output == "" {
+		return report.Write(stdout, trace, reportConfig)
+	}
+	out, err := os.Create(*output)
+	if err != nil {
+		return err
+	}
+	if err := report.Write(out, trace, reportConfig); err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"go.einride.tech/pid"
+	"go.einride.tech/pid/model"
+	"go.einride.tech/pid/sim"
+	"gotest.tools/v3/assert"
+)
+
+const config = `
+controller:
+  proportionalGain: 1.5
+  integralGain: 0.5
+  antiWindUpGain: 0.5
+  lowPassTimeConstant: 100ms
+  maxOutput: 10
+  minOutput: -10
+plant:
+  fopdt: {gain: 2, timeConstant: 3s, deadTime: 500ms}
+`
+
+func TestRun(t *testing.T) {
+	// Given a recorded trace and a loop config
+	dir := t.TempDir()
+	trace, err := sim.Simulate(sim.Config{
+		Controller: sim.NewAntiWindupController(pid.AntiWindupControllerConfig{
+			ProportionalGain:    1.5,
+			IntegralGain:        0.5,
+			LowPassTimeConstant: 100 * time.Millisecond,
+			MaxOutput:           10,
+			MinOutput:           -10,
+		}),
+		Plant:            model.FOPDT{Gain: 2, TimeConstant: 3 * time.Second, DeadTime: 500 * time.Millisecond},
+		SamplingInterval: 50 * time.Millisecond,
+		Duration:         30 * time.Second,
+		Reference:        []sim.Step{{Time: time.Second, Value: 1}},
+	})
+	assert.NilError(t, err)
+	var csv bytes.Buffer
+	assert.NilError(t, trace.WriteCSV(&csv))
+	tracePath := filepath.Join(dir, "trace.csv")
+	assert.NilError(t, os.WriteFile(tracePath, csv.Bytes(), 0o600))
+	configPath := filepath.Join(dir, "loop.yaml")
+	assert.NilError(t, os.WriteFile(configPath, []byte(config), 0o600))
+	output := filepath.Join(dir, "report.html")
+	// When generating a report
+	var stdout, stderr bytes.Buffer
+	assert.NilError(t, run(
+		[]string{"-i", tracePath, "-config", configPath, "-o", output, "-title", "Flow loop"}, &stdout, &stderr,
+	))
+	// Then the report should be written with margins from the plant model
+	data, err := os.ReadFile(output)
+	assert.NilError(t, err)
+	assert.Assert(t, strings.Contains(string(data), "<title>Flow loop</title>"))
+	assert.Assert(t, strings.Contains(string(data), "Robustness margins"))
+}
+
+func TestRun_MissingFlags(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	assert.ErrorContains(t, run(nil, &stdout, &stderr), "missing -i or -config")
+}
@@ -0,0 +1,137 @@
+// Command pidsim runs a closed-loop simulation of a PID controller and a process model.
+//
+// The controller, the process model and the simulated scenario are read from a JSON or YAML config file. The
+// simulated trace is written as CSV and optionally plotted as SVG, and a summary of step-response metrics and
+// integral indices is written to stderr.
+//
+// Usage:
+//
+//	pidsim -config loop.yaml [-o trace.csv] [-svg trace.svg] [-band 0.02]
+//
+// Example config:
+//
+//	controller:
+//	  proportionalGain: 1.5
+//	  integralGain: 0.5
+//	  antiWindUpGain: 0.5
+//	  lowPassTimeConstant: 100ms
+//	  maxOutput: 10
+//	  minOutput: -10
+//	plant:
+//	  fopdt: {gain: 2, timeConstant: 3s, deadTime: 500ms}
+//	samplingInterval: 50ms
+//	duration: 60s
+//	reference: [{time: 1s, value: 1}]
+//	disturbance: [{time: 30s, value: -0.25}]
+//	measurementNoise: 0.01
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"go.einride.tech/pid/internal/loopconfig"
+	"go.einride.tech/pid/plot"
+	"go.einride.tech/pid/sim"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout, os.Stderr); err != nil {
+		fmt.Fprintln(os.Stderr, "pidsim:", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdout, stderr io.Writer) error {
+	flags := flag.NewFlagSet("pidsim", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	configFile := flags.String("config", "", "simulation config file (JSON or YAML)")
+	output := flags.String("o", "", "trace CSV output file (default stdout)")
+	svg := flags.String("svg", "", "trace SVG plot output file")
+	band := flags.Float64("band", 0.02, "settling band, relative to the step size")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *configFile == "" {
+		return fmt.Errorf("missing -config")
+	}
+	cfg, err := loopconfig.Read(*configFile)
+	if err != nil {
+		return err
+	}
+	simConfig, err := cfg.SimConfig()
+	if err != nil {
+		return err
+	}
+	trace, err := sim.Simulate(simConfig)
+	if err != nil {
+		return err
+	}
+	if *output == "" {
+		if err := trace.WriteCSV(stdout); err != nil {
+			return err
+		}
+	} else {
+		f, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		if err := trace.WriteCSV(f); err != nil {
+			_ = f.Close()
+			return err
+		}
+		if err := f.Close(); err != nil {
+			return err
+		}
+	}
+	if *svg != "" {
+		if err := writeSVG(*svg, trace, cfg, *band); err != nil {
+			return err
+		}
+	}
+	return writeSummary(stderr, trace, *band)
+}
+
+func writeSVG(name string, trace sim.Trace, cfg loopconfig.Config, band float64) error {
+	options := plot.TraceOptions{Title: filepath.Base(name), SettlingBand: band}
+	if cfg.Controller.Type != "pid" {
+		options.MinOutput, options.MaxOutput = cfg.Controller.MinOutput, cfg.Controller.MaxOutput
+	}
+	f, err := os.Create(name)
+	if err != nil {
+		return err
+	}
+	if err := plot.TraceFigure(trace, options).WriteSVG(f); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func writeSummary(w io.Writer, trace sim.Trace, band float64) error {
+	for _, response := range trace.StepResponses(band) {
+		settlingTime := "not settled"
+		if response.Settled {
+			settlingTime = response.SettlingTime.String()
+		}
+		if _, err := fmt.Fprintf(
+			w,
+			"step at %v: %g -> %g: rise time %v, overshoot %.1f%%, settling time %s, steady-state error %.3g\n",
+			response.Time,
+			response.From,
+			response.To,
+			response.RiseTime,
+			100*response.Overshoot,
+			settlingTime,
+			response.SteadyStateError,
+		); err != nil {
+			return err
+		}
+	}
+	indices := trace.IntegralIndices()
+	_, err := fmt.Fprintf(w, "IAE %.4g, ISE %.4g, ITAE %.4g\n", indices.IAE, indices.ISE, indices.ITAE)
+	return err
+}
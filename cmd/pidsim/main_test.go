@@ -0,0 +1,125 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"go.einride.tech/pid/sim"
+	"gotest.tools/v3/assert"
+)
+
+const yamlConfig = `
+controller:
+  proportionalGain: 1.5
+  integralGain: 0.5
+  antiWindUpGain: 0.5
+  lowPassTimeConstant: 100ms
+  maxOutput: 10
+  minOutput: -10
+plant:
+  fopdt: {gain: 2, timeConstant: 3s, deadTime: 500ms}
+samplingInterval: 50ms
+duration: 30s
+reference: [{time: 1s, value: 1}]
+`
+
+const jsonConfig = `{
+  "controller": {"type": "pid", "proportionalGain": 1, "integralGain": 0.5},
+  "plant": {"transferFunction": {"numerator": [2], "denominator": [3, 1], "deadTime": "500ms"}},
+  "samplingInterval": "50ms",
+  "duration": "30s",
+  "reference": [{"time": "1s", "value": 1}]
+}`
+
+func TestRun(t *testing.T) {
+	for _, tt := range []struct {
+		name   string
+		file   string
+		config string
+	}{
+		{name: "yaml", file: "loop.yaml", config: yamlConfig},
+		{name: "json", file: "loop.json", config: jsonConfig},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given a config file
+			name := filepath.Join(t.TempDir(), tt.file)
+			assert.NilError(t, os.WriteFile(name, []byte(tt.config), 0o600))
+			var stdout, stderr bytes.Buffer
+			// When running the simulator
+			assert.NilError(t, run([]string{"-config", name}, &stdout, &stderr))
+			// Then the trace should be written as CSV
+			trace, err := sim.ReadCSV(&stdout)
+			assert.NilError(t, err)
+			assert.Equal(t, 601, len(trace.Samples))
+			// And the summary should include the step response metrics
+			assert.Assert(t, strings.Contains(stderr.String(), "step at 1s: 0 -> 1: rise time"), stderr.String())
+			assert.Assert(t, strings.Contains(stderr.String(), "IAE"), stderr.String())
+		})
+	}
+}
+
+func TestRun_OutputFile(t *testing.T) {
+	dir := t.TempDir()
+	name := filepath.Join(dir, "loop.yaml")
+	assert.NilError(t, os.WriteFile(name, []byte(yamlConfig), 0o600))
+	output := filepath.Join(dir, "trace.csv")
+	svg := filepath.Join(dir, "trace.svg")
+	var stdout, stderr bytes.Buffer
+	assert.NilError(t, run([]string{"-config", name, "-o", output, "-svg", svg}, &stdout, &stderr))
+	assert.Equal(t, 0, stdout.Len())
+	data, err := os.ReadFile(svg)
+	assert.NilError(t, err)
+	assert.Assert(t, strings.HasPrefix(string(data), "<svg"))
+	f, err := os.Open(output)
+	assert.NilError(t, err)
+	defer f.Close()
+	trace, err := sim.ReadCSV(f)
+	assert.NilError(t, err)
+	assert.Equal(t, 601, len(trace.Samples))
+}
+
+func TestRun_InvalidConfig(t *testing.T) {
+	for _, tt := range []struct {
+		name          string
+		file          string
+		config        string
+		expectedError string
+	}{
+		{
+			name:          "unknown field",
+			file:          "loop.yaml",
+			config:        "controller: {proportinalGain: 1}",
+			expectedError: "proportinalGain",
+		},
+		{
+			name:          "invalid duration",
+			file:          "loop.json",
+			config:        `{"samplingInterval": "fast"}`,
+			expectedError: "invalid duration",
+		},
+		{
+			name:          "missing plant",
+			file:          "loop.yaml",
+			config:        "samplingInterval: 10ms",
+			expectedError: "one of fopdt or transferFunction required",
+		},
+		{
+			name:          "unknown controller type",
+			file:          "loop.yaml",
+			config:        "controller: {type: lqr}",
+			expectedError: `unknown controller type "lqr"`,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			name := filepath.Join(t.TempDir(), tt.file)
+			assert.NilError(t, os.WriteFile(name, []byte(tt.config), 0o600))
+			var stdout, stderr bytes.Buffer
+			assert.ErrorContains(t, run([]string{"-config", name}, &stdout, &stderr), tt.expectedError)
+		})
+	}
+	var stdout, stderr bytes.Buffer
+	assert.ErrorContains(t, run(nil, &stdout, &stderr), "missing -config")
+}
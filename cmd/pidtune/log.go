@@ -0,0 +1,71 @@
+package main
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// processLog holds logged process input and output data at a fixed sampling interval.
+type processLog struct {
+	input            []float64
+	output           []float64
+	samplingInterval time.Duration
+}
+
+// readLog reads the named columns of a CSV log with a header row.
+//
+// The sampling interval is the median time between samples, since logs typically have some jitter.
+func readLog(r io.Reader, timeColumn, inputColumn, outputColumn string) (processLog, error) {
+	cr := csv.NewReader(r)
+	header, err := cr.Read()
+	if err != nil {
+		return processLog{}, fmt.Errorf("header: %w", err)
+	}
+	columns := make([]int, 3)
+	for i, name := range []string{timeColumn, inputColumn, outputColumn} {
+		columns[i] = slices.Index(header, name)
+		if columns[i] < 0 {
+			return processLog{}, fmt.Errorf("missing column %q", name)
+		}
+	}
+	var result processLog
+	var times []float64
+	for line := 2; ; line++ {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return processLog{}, err
+		}
+		var values [3]float64
+		for i, column := range columns {
+			if values[i], err = strconv.ParseFloat(record[column], 64); err != nil {
+				return processLog{}, fmt.Errorf("line %d: column %s: %w", line, header[column], err)
+			}
+		}
+		times = append(times, values[0])
+		result.input = append(result.input, values[1])
+		result.output = append(result.output, values[2])
+	}
+	if len(times) < 2 {
+		return processLog{}, fmt.Errorf("too few samples %d", len(times))
+	}
+	intervals := make([]float64, 0, len(times)-1)
+	for i := 1; i < len(times); i++ {
+		intervals = append(intervals, times[i]-times[i-1])
+	}
+	slices.Sort(intervals)
+	median := intervals[len(intervals)/2]
+	if median <= 0 || math.IsNaN(median) {
+		return processLog{}, fmt.Errorf("non-increasing time")
+	}
+	result.samplingInterval = time.Duration(math.Round(median * float64(time.Second)))
+	return result, nil
+}
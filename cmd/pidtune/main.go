@@ -0,0 +1,89 @@
+// Command pidtune proposes PID controller configs from logged process data.
+//
+// The input CSV must have a header row, a time column in seconds, and columns for the process input (the control
+// signal) and the process output (the actual signal). A FOPDT process model is identified from the data, and for
+// each selected tuning rule a pid.ControllerConfig and a pid.AntiWindupControllerConfig are proposed, along with
+// their predicted robustness margins and step-response metrics on the identified model.
+//
+// Usage:
+//
+//	pidtune -i log.csv [-input control] [-output actual] [-rules simc-pi,amigo-pid] [-min 0 -max 100] [-json]
+//
+// The data should start in steady state and contain at least one step-like change of the process input, such as
+// an open-loop bump test.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strings"
+	"time"
+
+	"go.einride.tech/pid/tuning"
+)
+
+func main() {
+	if err := run(os.Args[1:], os.Stdout, os.Stderr); err != nil {
+		fmt.Fprintln(os.Stderr, "pidtune:", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdout, stderr io.Writer) error {
+	flags := flag.NewFlagSet("pidtune", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	inputFile := flags.String("i", "", "input CSV file")
+	timeColumn := flags.String("time", "time", "time column (s)")
+	inputColumn := flags.String("input", "control", "process input column")
+	outputColumn := flags.String("output", "actual", "process output column")
+	ruleNames := flags.String("rules", "", "comma-separated tuning rules (default all)")
+	maxDeadTime := flags.Duration("max-dead-time", 0, "max dead time of the model (default a quarter of the log)")
+	minOutput := flags.Float64("min", math.Inf(-1), "min controller output")
+	maxOutput := flags.Float64("max", math.Inf(1), "max controller output")
+	band := flags.Float64("band", 0.02, "settling band, relative to the step size")
+	jsonOutput := flags.Bool("json", false, "write proposals as JSON")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if *inputFile == "" {
+		return fmt.Errorf("missing -i")
+	}
+	rules := tuning.Rules()
+	if *ruleNames != "" {
+		rules = rules[:0]
+		for _, name := range strings.Split(*ruleNames, ",") {
+			rule, err := tuning.ParseRule(strings.TrimSpace(name))
+			if err != nil {
+				return err
+			}
+			rules = append(rules, rule)
+		}
+	}
+	f, err := os.Open(*inputFile)
+	if err != nil {
+		return err
+	}
+	data, err := readLog(f, *timeColumn, *inputColumn, *outputColumn)
+	_ = f.Close()
+	if err != nil {
+		return fmt.Errorf("read %s: %w", *inputFile, err)
+	}
+	if *maxDeadTime == 0 {
+		*maxDeadTime = time.Duration(len(data.input)) * data.samplingInterval / 4
+	}
+	fit, err := tuning.IdentifyFOPDT(data.input, data.output, data.samplingInterval, *maxDeadTime)
+	if err != nil {
+		return err
+	}
+	proposals, err := propose(fit, rules, data.samplingInterval, *minOutput, *maxOutput, *band)
+	if err != nil {
+		return err
+	}
+	if *jsonOutput {
+		return writeJSON(stdout, fit, proposals)
+	}
+	return writeText(stdout, fit, proposals)
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"go.einride.tech/pid/model"
+	"go.einride.tech/pid/tuning"
+	"gotest.tools/v3/assert"
+)
+
+// writeBumpTest writes a log of an open-loop bump test of a FOPDT process.
+func writeBumpTest(t *testing.T) string {
+	t.Helper()
+	s, err := model.NewSimulation(model.FOPDT{Gain: 2, TimeConstant: 5 * time.Second, DeadTime: time.Second})
+	assert.NilError(t, err)
+	var b strings.Builder
+	b.WriteString("t,valve,flow\n")
+	for k := range 600 {
+		u := 40.0
+		if k >= 50 {
+			u = 45
+		}
+		_, _ = fmt.Fprintf(&b, "%g,%g,%g\n", float64(k)/10, u, 10+s.Output())
+		s.Update(u-40, 100*time.Millisecond)
+	}
+	name := filepath.Join(t.TempDir(), "log.csv")
+	assert.NilError(t, os.WriteFile(name, []byte(b.String()), 0o600))
+	return name
+}
+
+func TestRun_Text(t *testing.T) {
+	// Given a log of a bump test
+	name := writeBumpTest(t)
+	var stdout, stderr bytes.Buffer
+	// When proposing configs
+	assert.NilError(t, run(
+		[]string{"-i", name, "-time", "t", "-input", "valve", "-output", "flow", "-rules", "simc-pi,amigo-pid"},
+		&stdout,
+		&stderr,
+	))
+	// Then the identified model and a proposal per rule and controller should be printed
+	output := stdout.String()
+	assert.Assert(t, strings.Contains(output, "model: FOPDT gain 2, time constant 5s, dead time 1s"), output)
+	assert.Equal(t, 2, strings.Count(output, "simc-pi "), output)
+	assert.Equal(t, 2, strings.Count(output, "amigo-pid "), output)
+	assert.Assert(t, !strings.Contains(output, "ziegler-nichols"), output)
+}
+
+func TestRun_JSON(t *testing.T) {
+	// Given a log of a bump test
+	name := writeBumpTest(t)
+	var stdout, stderr bytes.Buffer
+	// When proposing configs as JSON
+	assert.NilError(t, run(
+		[]string{"-i", name, "-time", "t", "-input", "valve", "-output", "flow", "-min", "0", "-max", "100", "-json"},
+		&stdout,
+		&stderr,
+	))
+	var result struct {
+		Model     identifiedModel `json:"model"`
+		Proposals []proposal      `json:"proposals"`
+	}
+	assert.NilError(t, json.Unmarshal(stdout.Bytes(), &result))
+	// Then the model and proposals should be the expected
+	assert.Equal(t, time.Second, result.Model.DeadTime)
+	assert.Equal(t, 2*len(tuning.Rules()), len(result.Proposals))
+	for _, p := range result.Proposals {
+		if p.AntiWindupControllerConfig != nil {
+			assert.Assert(t, p.AntiWindupControllerConfig.MaxOutput != nil)
+			assert.Equal(t, 100.0, *p.AntiWindupControllerConfig.MaxOutput)
+		} else {
+			assert.Assert(t, p.ControllerConfig != nil)
+		}
+		assert.Assert(t, p.Margins.MaxSensitivity > 1)
+	}
+}
+
+func TestRun_JSONWithoutOutputLimits(t *testing.T) {
+	// Given a log of a bump test
+	name := writeBumpTest(t)
+	var stdout, stderr bytes.Buffer
+	// When proposing configs as JSON without output limits
+	assert.NilError(t, run(
+		[]string{"-i", name, "-time", "t", "-input", "valve", "-output", "flow", "-json"},
+		&stdout,
+		&stderr,
+	))
+	var result struct {
+		Proposals []proposal `json:"proposals"`
+	}
+	assert.NilError(t, json.Unmarshal(stdout.Bytes(), &result))
+	// Then the infinite output limits should be encoded as null
+	assert.Equal(t, 2*len(tuning.Rules()), len(result.Proposals))
+	for _, p := range result.Proposals {
+		if p.AntiWindupControllerConfig != nil {
+			assert.Assert(t, p.AntiWindupControllerConfig.MaxOutput == nil)
+			assert.Assert(t, p.AntiWindupControllerConfig.MinOutput == nil)
+		}
+	}
+	assert.Assert(t, strings.Contains(stdout.String(), `"maxOutput": null`), stdout.String())
+}
+
+func TestSimulationDuration(t *testing.T) {
+	m := model.FOPDT{Gain: 2, TimeConstant: 5 * time.Second, DeadTime: time.Second}
+	for _, tt := range []struct {
+		name     string
+		gains    tuning.Gains
+		expected time.Duration
+	}{
+		{name: "fast integral", gains: tuning.Gains{ProportionalGain: 1, IntegralGain: 1}, expected: 120 * time.Second},
+		{name: "slow integral", gains: tuning.Gains{ProportionalGain: 1, IntegralGain: 0.01}, expected: 1000 * time.Second},
+		{name: "no integral", gains: tuning.Gains{ProportionalGain: 1}, expected: 120 * time.Second},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, simulationDuration(m, tt.gains))
+		})
+	}
+}
+
+func TestRun_Errors(t *testing.T) {
+	name := writeBumpTest(t)
+	var stdout, stderr bytes.Buffer
+	assert.ErrorContains(t, run(nil, &stdout, &stderr), "missing -i")
+	assert.ErrorContains(t, run([]string{"-i", name}, &stdout, &stderr), `missing column "time"`)
+	assert.ErrorContains(
+		t, run([]string{"-i", name, "-time", "t", "-rules", "magic"}, &stdout, &stderr), `unknown rule "magic"`,
+	)
+}
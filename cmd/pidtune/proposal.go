@@ -0,0 +1,237 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"text/tabwriter"
+	"time"
+
+	"go.einride.tech/pid"
+	"go.einride.tech/pid/model"
+	"go.einride.tech/pid/sim"
+	"go.einride.tech/pid/tuning"
+)
+
+// proposal is a proposed controller config with its predicted performance.
+type proposal struct {
+	Rule                       string                      `json:"rule"`
+	Controller                 string                      `json:"controller"`
+	ControllerConfig           *pid.ControllerConfig       `json:"controllerConfig,omitempty"`
+	AntiWindupControllerConfig *antiWindupControllerConfig `json:"antiWindupControllerConfig,omitempty"`
+	Margins                    margins                     `json:"margins"`
+	StepResponse               stepResponse                `json:"stepResponse"`
+}
+
+// antiWindupControllerConfig is the JSON representation of a pid.AntiWindupControllerConfig, with infinite output
+// limits encoded as null, which JSON cannot represent.
+type antiWindupControllerConfig struct {
+	ProportionalGain              float64       `json:"proportionalGain"`
+	IntegralGain                  float64       `json:"integralGain"`
+	DerivativeGain                float64       `json:"derivativeGain"`
+	AntiWindUpGain                float64       `json:"antiWindUpGain"`
+	IntegralDischargeTimeConstant float64       `json:"integralDischargeTimeConstant"`
+	LowPassTimeConstant           time.Duration `json:"lowPassTimeConstant"`
+	MaxOutput                     *float64      `json:"maxOutput"`
+	MinOutput                     *float64      `json:"minOutput"`
+}
+
+func newAntiWindupControllerConfig(c pid.AntiWindupControllerConfig) *antiWindupControllerConfig {
+	return &antiWindupControllerConfig{
+		ProportionalGain:              c.ProportionalGain,
+		IntegralGain:                  c.IntegralGain,
+		DerivativeGain:                c.DerivativeGain,
+		AntiWindUpGain:                c.AntiWindUpGain,
+		IntegralDischargeTimeConstant: c.IntegralDischargeTimeConstant,
+		LowPassTimeConstant:           c.LowPassTimeConstant,
+		MaxOutput:                     finite(c.MaxOutput),
+		MinOutput:                     finite(c.MinOutput),
+	}
+}
+
+// margins are tuning.Margins with infinite margins encoded as null, which JSON cannot represent.
+type margins struct {
+	GainMargin     *float64 `json:"gainMargin"`
+	PhaseMargin    *float64 `json:"phaseMargin"`
+	MaxSensitivity float64  `json:"maxSensitivity"`
+}
+
+type stepResponse struct {
+	RiseTime     time.Duration `json:"riseTime"`
+	Overshoot    float64       `json:"overshoot"`
+	Settled      bool          `json:"settled"`
+	SettlingTime time.Duration `json:"settlingTime"`
+}
+
+// propose controller configs for the identified model with each tuning rule.
+func propose(
+	fit tuning.FOPDTFit,
+	rules []tuning.Rule,
+	samplingInterval time.Duration,
+	minOutput, maxOutput float64,
+	band float64,
+) ([]proposal, error) {
+	var result []proposal
+	for _, rule := range rules {
+		gains, err := tuning.Tune(fit.Model, rule)
+		if err != nil {
+			return nil, err
+		}
+		controllerConfig := gains.ControllerConfig()
+		antiWindupControllerConfig := gains.AntiWindupControllerConfig(minOutput, maxOutput)
+		// The pid.Controller has no derivative filter.
+		unfiltered := gains
+		unfiltered.LowPassTimeConstant = 0
+		for _, p := range []struct {
+			proposal   proposal
+			gains      tuning.Gains
+			controller sim.Controller
+		}{
+			{
+				proposal:   proposal{Controller: "Controller", ControllerConfig: &controllerConfig},
+				gains:      unfiltered,
+				controller: sim.NewController(controllerConfig),
+			},
+			{
+				proposal: proposal{
+					Controller:                 "AntiWindupController",
+					AntiWindupControllerConfig: newAntiWindupControllerConfig(antiWindupControllerConfig),
+				},
+				gains:      gains,
+				controller: sim.NewAntiWindupController(antiWindupControllerConfig),
+			},
+		} {
+			p.proposal.Rule = rule.String()
+			p.proposal.Margins = newMargins(tuning.ComputeMargins(p.gains, fit.Model))
+			// Predict the response to a unit reference step.
+			trace, err := sim.Simulate(sim.Config{
+				Controller:       p.controller,
+				Plant:            fit.Model,
+				SamplingInterval: samplingInterval,
+				Duration:         simulationDuration(fit.Model, gains),
+				Reference:        []sim.Step{{Time: samplingInterval, Value: 1}},
+			})
+			if err != nil {
+				return nil, err
+			}
+			if responses := trace.StepResponses(band); len(responses) > 0 {
+				p.proposal.StepResponse = stepResponse{
+					RiseTime:     responses[0].RiseTime,
+					Overshoot:    responses[0].Overshoot,
+					Settled:      responses[0].Settled,
+					SettlingTime: responses[0].SettlingTime,
+				}
+			}
+			result = append(result, p.proposal)
+		}
+	}
+	return result, nil
+}
+
+// simulationDuration returns the duration of a simulated step response of the model with the gains, long enough
+// to settle a sluggish loop.
+func simulationDuration(m model.FOPDT, gains tuning.Gains) time.Duration {
+	duration := 20 * (m.TimeConstant + m.DeadTime)
+	if gains.IntegralGain > 0 {
+		integralTime := time.Duration(gains.ProportionalGain / gains.IntegralGain * float64(time.Second))
+		duration = max(duration, 10*integralTime)
+	}
+	return duration
+}
+
+func newMargins(m tuning.Margins) margins {
+	return margins{
+		GainMargin:     finite(m.GainMargin),
+		PhaseMargin:    finite(m.PhaseMargin),
+		MaxSensitivity: m.MaxSensitivity,
+	}
+}
+
+// finite returns nil for infinite and NaN values, which JSON cannot represent.
+func finite(x float64) *float64 {
+	if math.IsInf(x, 0) || math.IsNaN(x) {
+		return nil
+	}
+	return &x
+}
+
+// identifiedModel is the JSON representation of an identified model.
+type identifiedModel struct {
+	Gain         float64       `json:"gain"`
+	TimeConstant time.Duration `json:"timeConstant"`
+	DeadTime     time.Duration `json:"deadTime"`
+	Fit          float64       `json:"fit"`
+}
+
+func writeJSON(w io.Writer, fit tuning.FOPDTFit, proposals []proposal) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(struct {
+		Model     identifiedModel `json:"model"`
+		Proposals []proposal      `json:"proposals"`
+	}{
+		Model: identifiedModel{
+			Gain:         fit.Model.Gain,
+			TimeConstant: fit.Model.TimeConstant,
+			DeadTime:     fit.Model.DeadTime,
+			Fit:          fit.Fit,
+		},
+		Proposals: proposals,
+	})
+}
+
+func writeText(w io.Writer, fit tuning.FOPDTFit, proposals []proposal) error {
+	if _, err := fmt.Fprintf(
+		w,
+		"model: FOPDT gain %.4g, time constant %v, dead time %v (fit %.1f%%)\n\n",
+		fit.Model.Gain,
+		fit.Model.TimeConstant.Round(time.Millisecond),
+		fit.Model.DeadTime,
+		fit.Fit,
+	); err != nil {
+		return err
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "RULE\tCONTROLLER\tKP\tKI\tKD\tKAW\tTF\tGM\tPM\tMS\tRISE\tOVERSHOOT\tSETTLING")
+	for _, p := range proposals {
+		var kp, ki, kd float64
+		kaw, tf := "-", "-"
+		if c := p.ControllerConfig; c != nil {
+			kp, ki, kd = c.ProportionalGain, c.IntegralGain, c.DerivativeGain
+		}
+		if c := p.AntiWindupControllerConfig; c != nil {
+			kp, ki, kd = c.ProportionalGain, c.IntegralGain, c.DerivativeGain
+			kaw, tf = fmt.Sprintf("%.4g", c.AntiWindUpGain), c.LowPassTimeConstant.Round(time.Millisecond).String()
+		}
+		settling := "-"
+		if p.StepResponse.Settled {
+			settling = p.StepResponse.SettlingTime.String()
+		}
+		_, _ = fmt.Fprintf(
+			tw,
+			"%s\t%s\t%.4g\t%.4g\t%.4g\t%s\t%s\t%s\t%s\t%.2f\t%v\t%.1f%%\t%s\n",
+			p.Rule,
+			p.Controller,
+			kp,
+			ki,
+			kd,
+			kaw,
+			tf,
+			formatMargin(p.Margins.GainMargin, "%.2f"),
+			formatMargin(p.Margins.PhaseMargin, "%.1f°"),
+			p.Margins.MaxSensitivity,
+			p.StepResponse.RiseTime,
+			100*p.StepResponse.Overshoot,
+			settling,
+		)
+	}
+	return tw.Flush()
+}
+
+func formatMargin(margin *float64, format string) string {
+	if margin == nil {
+		return "inf"
+	}
+	return fmt.Sprintf(format, *margin)
+}
@@ -1,67 +1,77 @@
 package pid
 
 import (
-	"math"
 	"time"
 )
 
 // Controller implements a basic PID controller.
-type Controller struct {
+type Controller = ControllerOf[float64]
+
+// ControllerConfig contains configurable parameters for a Controller.
+type ControllerConfig = ControllerConfigOf[float64]
+
+// ControllerState holds mutable state for a Controller.
+type ControllerState = ControllerStateOf[float64]
+
+// ControllerInput holds the input parameters to a Controller.
+type ControllerInput = ControllerInputOf[float64]
+
+// ControllerOf implements a basic PID controller generic over the floating-point type T.
+type ControllerOf[T Float] struct {
 	// Config for the Controller.
-	Config ControllerConfig
+	Config ControllerConfigOf[T]
 	// State of the Controller.
-	State ControllerState
+	State ControllerStateOf[T]
 }
 
-// ControllerConfig contains configurable parameters for a Controller.
-type ControllerConfig struct {
+// ControllerConfigOf contains configurable parameters for a ControllerOf.
+type ControllerConfigOf[T Float] struct {
 	// ProportionalGain determines ratio of output response to error signal.
-	ProportionalGain float64 `json:"kp"`
+	ProportionalGain T `json:"kp"`
 	// IntegralGain determines previous error's affect on output.
-	IntegralGain float64 `json:"ki"`
+	IntegralGain T `json:"ki"`
 	// DerivativeGain decreases the sensitivity to large reference changes.
-	DerivativeGain float64 `json:"kd"`
+	DerivativeGain T `json:"kd"`
 }
 
-// ControllerState holds mutable state for a Controller.
-type ControllerState struct {
+// ControllerStateOf holds mutable state for a ControllerOf.
+type ControllerStateOf[T Float] struct {
 	// ControlError is the difference between reference and current value.
-	ControlError float64
+	ControlError T
 	// ControlErrorIntegral is the integrated control error over time.
-	ControlErrorIntegral float64
+	ControlErrorIntegral T
 	// ControlErrorDerivative is the rate of change of the control error.
-	ControlErrorDerivative float64
+	ControlErrorDerivative T
 	// ControlSignal is the current control signal output of the controller.
-	ControlSignal float64
+	ControlSignal T
 }
 
-// ControllerInput holds the input parameters to a Controller.
-type ControllerInput struct {
+// ControllerInputOf holds the input parameters to a ControllerOf.
+type ControllerInputOf[T Float] struct {
 	// ReferenceSignal is the reference value for the signal to control.
-	ReferenceSignal float64
+	ReferenceSignal T
 	// ActualSignal is the actual value of the signal to control.
-	ActualSignal float64
+	ActualSignal T
 	// SamplingInterval is the time interval elapsed since the previous call of the controller Update method.
 	SamplingInterval time.Duration
 }
 
 // Update the controller state.
-func (c *Controller) Update(input ControllerInput) {
-	if math.IsNaN(input.ReferenceSignal) || math.IsNaN(input.ActualSignal) ||
-		math.IsInf(input.ReferenceSignal, 0) || math.IsInf(input.ActualSignal, 0) {
+func (c *ControllerOf[T]) Update(input ControllerInputOf[T]) {
+	if isNaNOrInf(input.ReferenceSignal) || isNaNOrInf(input.ActualSignal) {
 		return
 	}
 
 	previousError := c.State.ControlError
 	c.State.ControlError = input.ReferenceSignal - input.ActualSignal
-	c.State.ControlErrorDerivative = (c.State.ControlError - previousError) / input.SamplingInterval.Seconds()
-	c.State.ControlErrorIntegral += c.State.ControlError * input.SamplingInterval.Seconds()
+	c.State.ControlErrorDerivative = (c.State.ControlError - previousError) / T(input.SamplingInterval.Seconds())
+	c.State.ControlErrorIntegral += c.State.ControlError * T(input.SamplingInterval.Seconds())
 	c.State.ControlSignal = c.Config.ProportionalGain*c.State.ControlError +
 		c.Config.IntegralGain*c.State.ControlErrorIntegral +
 		c.Config.DerivativeGain*c.State.ControlErrorDerivative
 }
 
 // Reset the controller state.
-func (c *Controller) Reset() {
-	c.State = ControllerState{}
+func (c *ControllerOf[T]) Reset() {
+	c.State = ControllerStateOf[T]{}
 }
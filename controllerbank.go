@@ -0,0 +1,208 @@
+package pid
+
+import (
+	"fmt"
+	"time"
+)
+
+// ControllerBank holds many AntiWindupControllers in a struct-of-arrays layout, with the config and state fields
+// of all controllers stored in contiguous slices, and updates all of them in one call without allocating.
+//
+// Controller i of the bank behaves exactly like an AntiWindupController with config and state at index i.
+type ControllerBank struct {
+	// Config for the controllers in the bank.
+	Config ControllerBankConfig
+	// State of the controllers in the bank.
+	State ControllerBankState
+}
+
+// ControllerBankConfig contains config parameters for the controllers in a ControllerBank, see
+// AntiWindupControllerConfig.
+type ControllerBankConfig struct {
+	// ProportionalGain is the P part gain, per controller.
+	ProportionalGain []float64
+	// IntegralGain is the I part gain, per controller.
+	IntegralGain []float64
+	// DerivativeGain is the D part gain, per controller.
+	DerivativeGain []float64
+	// AntiWindUpGain is the anti-windup tracking gain, per controller.
+	AntiWindUpGain []float64
+	// IntegralDischargeTimeConstant is the time constant to discharge the integral state of the PID controller (s),
+	// per controller.
+	IntegralDischargeTimeConstant []float64
+	// LowPassTimeConstant is the D part low-pass filter time constant => cut-off frequency 1/LowPassTimeConstant,
+	// per controller.
+	LowPassTimeConstant []time.Duration
+	// MaxOutput is the max output from the PID, per controller.
+	MaxOutput []float64
+	// MinOutput is the min output from the PID, per controller.
+	MinOutput []float64
+}
+
+// ControllerBankState holds mutable state for the controllers in a ControllerBank, see AntiWindupControllerState.
+type ControllerBankState struct {
+	// ControlError is the difference between reference and current value, per controller.
+	ControlError []float64
+	// ControlErrorIntegrand is the control error integrand, which includes the anti-windup correction, per
+	// controller.
+	ControlErrorIntegrand []float64
+	// ControlErrorIntegral is the control error integrand integrated over time, per controller.
+	ControlErrorIntegral []float64
+	// ControlErrorDerivative is the low-pass filtered time-derivative of the control error, per controller.
+	ControlErrorDerivative []float64
+	// ControlSignal is the current control signal output of the controller, per controller.
+	ControlSignal []float64
+	// UnsaturatedControlSignal is the control signal before saturation, per controller.
+	UnsaturatedControlSignal []float64
+}
+
+// ControllerBankInput holds the input parameters to a ControllerBank.
+//
+// The signal slices must have the same length as the bank. FeedForwardSignal may be nil for no feed forward.
+type ControllerBankInput struct {
+	// ReferenceSignal is the reference value for the signal to control, per controller.
+	ReferenceSignal []float64
+	// ActualSignal is the actual value of the signal to control, per controller.
+	ActualSignal []float64
+	// FeedForwardSignal is the contribution of the feed-forward control loop in the controller output, per
+	// controller.
+	FeedForwardSignal []float64
+	// SamplingInterval is the time interval elapsed since the previous call of the Update method, shared by all
+	// controllers.
+	SamplingInterval time.Duration
+}
+
+// NewControllerBank creates a new ControllerBank with one controller per provided config.
+func NewControllerBank(configs ...AntiWindupControllerConfig) *ControllerBank {
+	n := len(configs)
+	b := &ControllerBank{
+		Config: ControllerBankConfig{
+			ProportionalGain:              make([]float64, n),
+			IntegralGain:                  make([]float64, n),
+			DerivativeGain:                make([]float64, n),
+			AntiWindUpGain:                make([]float64, n),
+			IntegralDischargeTimeConstant: make([]float64, n),
+			LowPassTimeConstant:           make([]time.Duration, n),
+			MaxOutput:                     make([]float64, n),
+			MinOutput:                     make([]float64, n),
+		},
+		State: ControllerBankState{
+			ControlError:             make([]float64, n),
+			ControlErrorIntegrand:    make([]float64, n),
+			ControlErrorIntegral:     make([]float64, n),
+			ControlErrorDerivative:   make([]float64, n),
+			ControlSignal:            make([]float64, n),
+			UnsaturatedControlSignal: make([]float64, n),
+		},
+	}
+	for i, config := range configs {
+		b.SetConfig(i, config)
+	}
+	return b
+}
+
+// Len returns the number of controllers in the bank.
+func (b *ControllerBank) Len() int {
+	return len(b.State.ControlSignal)
+}
+
+// SetConfig sets the config of controller i.
+func (b *ControllerBank) SetConfig(i int, config AntiWindupControllerConfig) {
+	b.Config.ProportionalGain[i] = config.ProportionalGain
+	b.Config.IntegralGain[i] = config.IntegralGain
+	b.Config.DerivativeGain[i] = config.DerivativeGain
+	b.Config.AntiWindUpGain[i] = config.AntiWindUpGain
+	b.Config.IntegralDischargeTimeConstant[i] = config.IntegralDischargeTimeConstant
+	b.Config.LowPassTimeConstant[i] = config.LowPassTimeConstant
+	b.Config.MaxOutput[i] = config.MaxOutput
+	b.Config.MinOutput[i] = config.MinOutput
+}
+
+// Controller returns a copy of controller i.
+func (b *ControllerBank) Controller(i int) AntiWindupController {
+	return AntiWindupController{
+		Config: AntiWindupControllerConfig{
+			ProportionalGain:              b.Config.ProportionalGain[i],
+			IntegralGain:                  b.Config.IntegralGain[i],
+			DerivativeGain:                b.Config.DerivativeGain[i],
+			AntiWindUpGain:                b.Config.AntiWindUpGain[i],
+			IntegralDischargeTimeConstant: b.Config.IntegralDischargeTimeConstant[i],
+			LowPassTimeConstant:           b.Config.LowPassTimeConstant[i],
+			MaxOutput:                     b.Config.MaxOutput[i],
+			MinOutput:                     b.Config.MinOutput[i],
+		},
+		State: AntiWindupControllerState{
+			ControlError:             b.State.ControlError[i],
+			ControlErrorIntegrand:    b.State.ControlErrorIntegrand[i],
+			ControlErrorIntegral:     b.State.ControlErrorIntegral[i],
+			ControlErrorDerivative:   b.State.ControlErrorDerivative[i],
+			ControlSignal:            b.State.ControlSignal[i],
+			UnsaturatedControlSignal: b.State.UnsaturatedControlSignal[i],
+		},
+	}
+}
+
+// Reset the state of all controllers.
+func (b *ControllerBank) Reset() {
+	clear(b.State.ControlError)
+	clear(b.State.ControlErrorIntegrand)
+	clear(b.State.ControlErrorIntegral)
+	clear(b.State.ControlErrorDerivative)
+	clear(b.State.ControlSignal)
+	clear(b.State.UnsaturatedControlSignal)
+}
+
+// Update the state of all controllers.
+//
+// Controllers with a NaN or Inf reference or actual signal are not updated. Update panics if the input slices
+// do not match the length of the bank.
+func (b *ControllerBank) Update(input ControllerBankInput) {
+	n := b.Len()
+	if len(input.ReferenceSignal) != n || len(input.ActualSignal) != n ||
+		(input.FeedForwardSignal != nil && len(input.FeedForwardSignal) != n) {
+		panic(fmt.Sprintf(
+			"pid: controller bank update: input lengths %d, %d and %d do not match bank length %d",
+			len(input.ReferenceSignal), len(input.ActualSignal), len(input.FeedForwardSignal), n,
+		))
+	}
+	dt := input.SamplingInterval.Seconds()
+	// Re-slice everything to the bank length, which allows the compiler to eliminate bounds checks in the loop.
+	kp, ki, kd := b.Config.ProportionalGain[:n], b.Config.IntegralGain[:n], b.Config.DerivativeGain[:n]
+	kaw, tf := b.Config.AntiWindUpGain[:n], b.Config.LowPassTimeConstant[:n]
+	maxOutput, minOutput := b.Config.MaxOutput[:n], b.Config.MinOutput[:n]
+	controlError, integrand := b.State.ControlError[:n], b.State.ControlErrorIntegrand[:n]
+	integral, derivative := b.State.ControlErrorIntegral[:n], b.State.ControlErrorDerivative[:n]
+	controlSignal, unsaturated := b.State.ControlSignal[:n], b.State.UnsaturatedControlSignal[:n]
+	reference, actual := input.ReferenceSignal[:n], input.ActualSignal[:n]
+	for i := range n {
+		if isNaNOrInf(reference[i]) || isNaNOrInf(actual[i]) {
+			continue
+		}
+		var feedForward float64
+		if input.FeedForwardSignal != nil {
+			feedForward = input.FeedForwardSignal[i]
+		}
+		lowPassTimeConstant := tf[i].Seconds()
+		e := reference[i] - actual[i]
+		controlErrorIntegral := integrand[i]*dt + integral[i]
+		controlErrorDerivative := ((1/lowPassTimeConstant)*(e-controlError[i]) + derivative[i]) /
+			(dt/lowPassTimeConstant + 1)
+		unsaturated[i] = e*kp[i] + ki[i]*controlErrorIntegral + kd[i]*controlErrorDerivative + feedForward
+		controlSignal[i] = clamp(unsaturated[i], minOutput[i], maxOutput[i])
+		integrand[i] = clampFinite(e + kaw[i]*(controlSignal[i]-unsaturated[i]))
+		integral[i] = clampFinite(controlErrorIntegral)
+		derivative[i] = clampFinite(controlErrorDerivative)
+		controlError[i] = clampFinite(e)
+	}
+}
+
+// DischargeIntegral discharges the integral state of all controllers, see AntiWindupController.DischargeIntegral.
+func (b *ControllerBank) DischargeIntegral(dt time.Duration) {
+	n := b.Len()
+	timeConstant := b.Config.IntegralDischargeTimeConstant[:n]
+	integrand, integral := b.State.ControlErrorIntegrand[:n], b.State.ControlErrorIntegral[:n]
+	for i := range n {
+		integrand[i] = 0
+		integral[i] = clamp(1-dt.Seconds()/timeConstant[i], 0, 1) * integral[i]
+	}
+}
@@ -0,0 +1,159 @@
+package pid
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func controllerBankTestConfigs(n int) []AntiWindupControllerConfig {
+	configs := make([]AntiWindupControllerConfig, n)
+	for i := range configs {
+		configs[i] = AntiWindupControllerConfig{
+			ProportionalGain:              1 + float64(i%7),
+			IntegralGain:                  0.5 + float64(i%3),
+			DerivativeGain:                0.1,
+			AntiWindUpGain:                0.5,
+			IntegralDischargeTimeConstant: 10,
+			LowPassTimeConstant:           time.Duration(1+i%5) * 100 * time.Millisecond,
+			MaxOutput:                     5,
+			MinOutput:                     -5,
+		}
+	}
+	return configs
+}
+
+func TestControllerBank_Update(t *testing.T) {
+	// Given a bank of controllers and the equivalent individual controllers
+	const n = 16
+	configs := controllerBankTestConfigs(n)
+	bank := NewControllerBank(configs...)
+	controllers := make([]AntiWindupController, n)
+	for i := range controllers {
+		controllers[i].Config = configs[i]
+	}
+	input := ControllerBankInput{
+		ReferenceSignal:   make([]float64, n),
+		ActualSignal:      make([]float64, n),
+		FeedForwardSignal: make([]float64, n),
+		SamplingInterval:  dtTest,
+	}
+	for step := range 500 {
+		for i := range n {
+			input.ReferenceSignal[i] = 10 * math.Sin(float64(step+i)/40)
+			input.ActualSignal[i] = math.Cos(float64(step*i) / 60)
+			input.FeedForwardSignal[i] = 0.1 * float64(i)
+		}
+		if step == 100 {
+			// Invalid inputs should be ignored, like for the individual controllers.
+			input.ActualSignal[3] = math.NaN()
+		}
+		// When updating the bank and the individual controllers with the same inputs
+		bank.Update(input)
+		for i := range controllers {
+			controllers[i].Update(AntiWindupControllerInput{
+				ReferenceSignal:   input.ReferenceSignal[i],
+				ActualSignal:      input.ActualSignal[i],
+				FeedForwardSignal: input.FeedForwardSignal[i],
+				SamplingInterval:  input.SamplingInterval,
+			})
+		}
+	}
+	// Then the bank should be identical to the individual controllers
+	for i := range controllers {
+		assert.Equal(t, controllers[i], bank.Controller(i))
+	}
+}
+
+func TestControllerBank_NilFeedForward(t *testing.T) {
+	// Given a bank of P controllers
+	bank := NewControllerBank(
+		AntiWindupControllerConfig{ProportionalGain: 1, LowPassTimeConstant: time.Second, MaxOutput: 10, MinOutput: -10},
+		AntiWindupControllerConfig{ProportionalGain: 2, LowPassTimeConstant: time.Second, MaxOutput: 10, MinOutput: -10},
+	)
+	// When updating without feed forward
+	bank.Update(ControllerBankInput{
+		ReferenceSignal:  []float64{1, 1},
+		ActualSignal:     []float64{0, 0},
+		SamplingInterval: dtTest,
+	})
+	// Then the control signals should be the P parts
+	assert.DeepEqual(t, []float64{1, 2}, bank.State.ControlSignal)
+}
+
+func TestControllerBank_LengthMismatch(t *testing.T) {
+	bank := NewControllerBank(controllerBankTestConfigs(2)...)
+	defer func() {
+		assert.Assert(t, recover() != nil)
+	}()
+	bank.Update(ControllerBankInput{ReferenceSignal: []float64{1}, ActualSignal: []float64{1, 2}})
+}
+
+func TestControllerBank_ResetAndDischargeIntegral(t *testing.T) {
+	// Given a bank with integral state
+	bank := NewControllerBank(controllerBankTestConfigs(2)...)
+	bank.State.ControlErrorIntegral[0] = 4
+	bank.State.ControlErrorIntegral[1] = -2
+	bank.State.ControlSignal[0] = 1
+	// When discharging the integral for a second
+	bank.DischargeIntegral(time.Second)
+	// Then the integrals should be discharged by a tenth
+	assert.Assert(t, math.Abs(bank.State.ControlErrorIntegral[0]-3.6) < deltaTest)
+	assert.Assert(t, math.Abs(bank.State.ControlErrorIntegral[1]+1.8) < deltaTest)
+	// And when resetting, the state should be zero
+	bank.Reset()
+	assert.DeepEqual(t, []float64{0, 0}, bank.State.ControlErrorIntegral)
+	assert.DeepEqual(t, []float64{0, 0}, bank.State.ControlSignal)
+}
+
+func TestControllerBank_UpdateAllocations(t *testing.T) {
+	const n = 64
+	bank := NewControllerBank(controllerBankTestConfigs(n)...)
+	input := ControllerBankInput{
+		ReferenceSignal:  make([]float64, n),
+		ActualSignal:     make([]float64, n),
+		SamplingInterval: dtTest,
+	}
+	assert.Equal(t, 0.0, testing.AllocsPerRun(100, func() { bank.Update(input) }))
+}
+
+func BenchmarkControllerBank_Update(b *testing.B) {
+	const n = 4096
+	bank := NewControllerBank(controllerBankTestConfigs(n)...)
+	input := ControllerBankInput{
+		ReferenceSignal:  make([]float64, n),
+		ActualSignal:     make([]float64, n),
+		SamplingInterval: dtTest,
+	}
+	for i := range n {
+		input.ReferenceSignal[i] = float64(i % 10)
+	}
+	b.ReportAllocs()
+	for b.Loop() {
+		bank.Update(input)
+	}
+}
+
+func BenchmarkAntiWindupController_UpdateLoop(b *testing.B) {
+	const n = 4096
+	configs := controllerBankTestConfigs(n)
+	controllers := make([]AntiWindupController, n)
+	for i := range controllers {
+		controllers[i].Config = configs[i]
+	}
+	reference := make([]float64, n)
+	for i := range n {
+		reference[i] = float64(i % 10)
+	}
+	b.ReportAllocs()
+	for b.Loop() {
+		for i := range controllers {
+			controllers[i].Update(AntiWindupControllerInput{
+				ReferenceSignal:  reference[i],
+				SamplingInterval: dtTest,
+			})
+		}
+	}
+}
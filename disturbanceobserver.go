@@ -0,0 +1,171 @@
+package pid
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"go.einride.tech/pid/model"
+)
+
+// DisturbanceObserver wraps a controller with a disturbance observer, which estimates the lumped disturbance at
+// the input of the process from a nominal model Pn of the process and a Q-filter, and compensates it through the
+// feed forward signal of the controller.
+//
+// The estimate is the difference between the control signal that explains the actual signal by the nominal model
+// and the control signal applied to the process, both filtered by the Q-filter,
+//
+//	d = Q(s) Pn0(s)^-1 y - Q(s) e^(-sL) u,
+//
+// where Pn0 is the nominal model without its dead time L, and the compensation is -d. The Q-filter determines the
+// bandwidth of the disturbance rejection, and must have unit static gain and a relative degree no less than that
+// of the nominal model, which must have stable zeros.
+//
+// The observer shares the saturation model of the wrapped controller. It is driven by the saturated control
+// signal of the controller, or the applied control signal for a TrackingController, so that the estimate does not
+// wind up when the actuator saturates. The compensation is part of the control signal that the controller
+// saturates and tracks with its anti-windup, and is limited to the span of the output limits, beyond which it
+// cannot be applied. The observer starts at rest, at zero actual and control signal.
+type DisturbanceObserver[Input any] struct {
+	// State of the DisturbanceObserver.
+	State DisturbanceObserverState
+
+	inverse, filter *model.Simulation
+	// actualSignal is the latest valid actual signal, and started is true after the first valid actual signal.
+	actualSignal float64
+	started      bool
+	sample       func(Input) (float64, time.Duration)
+	span         func() float64
+	update       func(input Input, compensation float64) float64
+	reset        func()
+}
+
+// DisturbanceObserverState holds mutable state for a DisturbanceObserver.
+type DisturbanceObserverState struct {
+	// EstimatedDisturbance is the estimate of the lumped disturbance at the process input.
+	EstimatedDisturbance float64
+	// Compensation is the compensation added to the feed forward signal of the wrapped controller.
+	Compensation float64
+	// AppliedControlSignal is the control signal applied to the process after the latest update, which drives the
+	// observer.
+	AppliedControlSignal float64
+}
+
+// NewAntiWindupDisturbanceObserver wraps an AntiWindupController with a disturbance observer of the nominal model
+// and Q-filter.
+func NewAntiWindupDisturbanceObserver(
+	c *AntiWindupController, nominalModel, qFilter model.Model,
+) (*DisturbanceObserver[AntiWindupControllerInput], error) {
+	return newDisturbanceObserver(
+		nominalModel,
+		qFilter,
+		func(input AntiWindupControllerInput) (float64, time.Duration) {
+			return input.ActualSignal, input.SamplingInterval
+		},
+		func() float64 {
+			return c.Config.MaxOutput - c.Config.MinOutput
+		},
+		func(input AntiWindupControllerInput, compensation float64) float64 {
+			input.FeedForwardSignal += compensation
+			c.Update(input)
+			return c.State.ControlSignal
+		},
+		c.Reset,
+	)
+}
+
+// NewTrackingDisturbanceObserver wraps a TrackingController with a disturbance observer of the nominal model and
+// Q-filter.
+//
+// The observer is driven by the applied control signal of the input of each update, which should be the control
+// signal applied to the process after the update.
+func NewTrackingDisturbanceObserver(
+	c *TrackingController, nominalModel, qFilter model.Model,
+) (*DisturbanceObserver[TrackingControllerInput], error) {
+	return newDisturbanceObserver(
+		nominalModel,
+		qFilter,
+		func(input TrackingControllerInput) (float64, time.Duration) {
+			return input.ActualSignal, input.SamplingInterval
+		},
+		func() float64 {
+			return c.Config.MaxOutput - c.Config.MinOutput
+		},
+		func(input TrackingControllerInput, compensation float64) float64 {
+			input.FeedForwardSignal += compensation
+			c.Update(input)
+			return input.AppliedControlSignal
+		},
+		c.Reset,
+	)
+}
+
+func newDisturbanceObserver[Input any](
+	nominalModel, qFilter model.Model,
+	sample func(Input) (float64, time.Duration),
+	span func() float64,
+	update func(Input, float64) float64,
+	reset func(),
+) (*DisturbanceObserver[Input], error) {
+	pn, q := nominalModel.TransferFunction(), qFilter.TransferFunction()
+	if err := pn.Validate(); err != nil {
+		return nil, fmt.Errorf("pid: new disturbance observer: nominal model: %w", err)
+	}
+	if gain := q.StaticGain(); math.Abs(gain-1) > 1e-9 {
+		return nil, fmt.Errorf("pid: new disturbance observer: Q-filter static gain %v is not 1", gain)
+	}
+	filter, err := model.NewSimulation(q.Series(model.TransferFunction{
+		Numerator:   []float64{1},
+		Denominator: []float64{1},
+		DeadTime:    pn.DeadTime,
+	}))
+	if err != nil {
+		return nil, fmt.Errorf("pid: new disturbance observer: Q-filter: %w", err)
+	}
+	inverse, err := model.NewSimulation(q.Series(model.TransferFunction{
+		Numerator:   pn.Denominator,
+		Denominator: pn.Numerator,
+	}))
+	if err != nil {
+		return nil, fmt.Errorf("pid: new disturbance observer: Q-filter times inverse nominal model: %w", err)
+	}
+	return &DisturbanceObserver[Input]{
+		inverse: inverse,
+		filter:  filter,
+		sample:  sample,
+		span:    span,
+		update:  update,
+		reset:   reset,
+	}, nil
+}
+
+// Reset the state of the disturbance observer and the wrapped controller.
+func (o *DisturbanceObserver[Input]) Reset() {
+	o.reset()
+	o.inverse.Reset()
+	o.filter.Reset()
+	o.actualSignal, o.started = 0, false
+	o.State = DisturbanceObserverState{}
+}
+
+// Update the disturbance estimate with the actual signal and the control signal applied since the previous
+// update, and update the wrapped controller with the compensation added to the feed forward signal.
+func (o *DisturbanceObserver[Input]) Update(input Input) {
+	actualSignal, samplingInterval := o.sample(input)
+	if isNaNOrInf(actualSignal) {
+		// Hold the latest valid actual signal, so that the observer stays in time with the applied control signal.
+		actualSignal = o.actualSignal
+	} else if !o.started {
+		o.actualSignal, o.started = actualSignal, true
+	}
+	if o.started && samplingInterval > 0 {
+		// Hold the mean of the previous and current actual signal, which approximates its linear interpolation.
+		o.inverse.Update((o.actualSignal+actualSignal)/2, samplingInterval)
+		o.filter.Update(o.State.AppliedControlSignal, samplingInterval)
+		o.State.EstimatedDisturbance = clampFinite(o.inverse.Output() - o.filter.Output())
+		span := o.span()
+		o.State.Compensation = clamp(-o.State.EstimatedDisturbance, -span, span)
+	}
+	o.actualSignal = actualSignal
+	o.State.AppliedControlSignal = o.update(input, o.State.Compensation)
+}
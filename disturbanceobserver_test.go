@@ -0,0 +1,185 @@
+package pid
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"go.einride.tech/pid/model"
+	"gotest.tools/v3/assert"
+)
+
+// runDisturbanceObserverLoop runs a closed loop with a reference step, and a load disturbance at the process
+// input between the given times, and returns the actual signal and the disturbance estimate of each sample.
+func runDisturbanceObserverLoop(
+	t *testing.T,
+	update func(reference, actual float64) (controlSignal, estimate float64),
+	duration time.Duration,
+	disturbance float64,
+	start, end time.Duration,
+) (actual, estimates []float64) {
+	t.Helper()
+	plant, err := model.NewSimulation(model.FOPDT{Gain: 2, TimeConstant: time.Second})
+	assert.NilError(t, err)
+	const dt = 10 * time.Millisecond
+	for now := time.Duration(0); now < duration; now += dt {
+		actual = append(actual, plant.Output())
+		u, estimate := update(1, plant.Output())
+		estimates = append(estimates, estimate)
+		if now >= start && now < end {
+			u += disturbance
+		}
+		plant.Update(u, dt)
+	}
+	return actual, estimates
+}
+
+func TestDisturbanceObserver_Update(t *testing.T) {
+	// Given a controller with a disturbance observer of a perfect nominal model
+	nominalModel := model.FOPDT{Gain: 2, TimeConstant: time.Second}
+	config := AntiWindupControllerConfig{
+		ProportionalGain:    1,
+		IntegralGain:        1,
+		AntiWindUpGain:      1,
+		LowPassTimeConstant: 100 * time.Millisecond,
+		MinOutput:           -10,
+		MaxOutput:           10,
+	}
+	c := &AntiWindupController{Config: config}
+	o, err := NewAntiWindupDisturbanceObserver(c, nominalModel, model.LowPassFilter(1, 50*time.Millisecond))
+	assert.NilError(t, err)
+	const dt = 10 * time.Millisecond
+	// When a load disturbance enters the loop after 5 s
+	actual, estimates := runDisturbanceObserverLoop(t, func(reference, actual float64) (float64, float64) {
+		o.Update(AntiWindupControllerInput{ReferenceSignal: reference, ActualSignal: actual, SamplingInterval: dt})
+		return c.State.ControlSignal, o.State.EstimatedDisturbance
+	}, 10*time.Second, 1, 5*time.Second, 10*time.Second)
+	// And the same disturbance enters the loop of the controller alone
+	c = &AntiWindupController{Config: config}
+	reference, _ := runDisturbanceObserverLoop(t, func(reference, actual float64) (float64, float64) {
+		c.Update(AntiWindupControllerInput{ReferenceSignal: reference, ActualSignal: actual, SamplingInterval: dt})
+		return c.State.ControlSignal, 0
+	}, 10*time.Second, 1, 5*time.Second, 10*time.Second)
+	// Then the disturbance observer should reduce the deviation caused by the disturbance
+	var deviation, referenceDeviation float64
+	for k := 500; k < len(actual); k++ {
+		deviation = math.Max(deviation, math.Abs(actual[k]-1))
+		referenceDeviation = math.Max(referenceDeviation, math.Abs(reference[k]-1))
+	}
+	assert.Assert(t, deviation < referenceDeviation/5, "%v %v", deviation, referenceDeviation)
+	// And the disturbance should be estimated, and compensated through the feed forward signal
+	assert.Assert(t, math.Abs(estimates[499]) < 1e-3, estimates[499])
+	assert.Assert(t, math.Abs(o.State.EstimatedDisturbance-1) < 1e-3, o.State)
+	assert.Equal(t, -o.State.EstimatedDisturbance, o.State.Compensation)
+}
+
+func TestDisturbanceObserver_Update_Saturation(t *testing.T) {
+	// Given a controller with tight output limits and a disturbance observer
+	c := &AntiWindupController{
+		Config: AntiWindupControllerConfig{
+			ProportionalGain:    1,
+			IntegralGain:        1,
+			AntiWindUpGain:      1,
+			LowPassTimeConstant: 100 * time.Millisecond,
+			MinOutput:           -1,
+			MaxOutput:           1,
+		},
+	}
+	o, err := NewAntiWindupDisturbanceObserver(
+		c, model.FOPDT{Gain: 2, TimeConstant: time.Second}, model.LowPassFilter(1, 50*time.Millisecond),
+	)
+	assert.NilError(t, err)
+	const dt = 10 * time.Millisecond
+	// When a load disturbance saturates the actuator between 5 s and 10 s
+	actual, estimates := runDisturbanceObserverLoop(t, func(reference, actual float64) (float64, float64) {
+		o.Update(AntiWindupControllerInput{ReferenceSignal: reference, ActualSignal: actual, SamplingInterval: dt})
+		return c.State.ControlSignal, o.State.EstimatedDisturbance
+	}, 20*time.Second, -2, 5*time.Second, 10*time.Second)
+	// Then the disturbance estimate should not wind up during saturation
+	assert.Assert(t, math.Abs(estimates[999]+2) < 1e-2, estimates[999])
+	// And the actual signal should recover without overshoot when the disturbance disappears
+	var maxActual float64
+	for _, y := range actual[1000:] {
+		maxActual = math.Max(maxActual, y)
+	}
+	assert.Assert(t, maxActual < 1.05, maxActual)
+	assert.Assert(t, math.Abs(actual[len(actual)-1]-1) < 1e-3, actual[len(actual)-1])
+}
+
+func TestDisturbanceObserver_Update_Tracking(t *testing.T) {
+	// Given a tracking controller with a disturbance observer
+	c := &TrackingController{
+		Config: TrackingControllerConfig{
+			ProportionalGain:    1,
+			IntegralGain:        1,
+			AntiWindUpGain:      1,
+			LowPassTimeConstant: 100 * time.Millisecond,
+			MinOutput:           -10,
+			MaxOutput:           10,
+		},
+	}
+	o, err := NewTrackingDisturbanceObserver(
+		c, model.FOPDT{Gain: 2, TimeConstant: time.Second}, model.LowPassFilter(2, 50*time.Millisecond),
+	)
+	assert.NilError(t, err)
+	const dt = 10 * time.Millisecond
+	// When the process is driven in manual mode by a constant applied control signal
+	_, estimates := runDisturbanceObserverLoop(t, func(reference, actual float64) (float64, float64) {
+		o.Update(TrackingControllerInput{
+			ReferenceSignal:      reference,
+			ActualSignal:         actual,
+			AppliedControlSignal: 0.3,
+			SamplingInterval:     dt,
+		})
+		return 0.3, o.State.EstimatedDisturbance
+	}, 5*time.Second, 0, 0, 0)
+	// Then the observer should follow the applied control signal, and estimate no disturbance
+	assert.Equal(t, 0.3, o.State.AppliedControlSignal)
+	for _, estimate := range estimates[10:] {
+		assert.Assert(t, math.Abs(estimate) < 1e-2, estimate)
+	}
+}
+
+func TestDisturbanceObserver_Update_InvalidInput(t *testing.T) {
+	// Given a disturbance observer with state
+	c := &AntiWindupController{
+		Config: AntiWindupControllerConfig{
+			ProportionalGain:    1,
+			IntegralGain:        1,
+			AntiWindUpGain:      1,
+			LowPassTimeConstant: 100 * time.Millisecond,
+			MinOutput:           -10,
+			MaxOutput:           10,
+		},
+	}
+	o, err := NewAntiWindupDisturbanceObserver(
+		c, model.FOPDT{Gain: 2, TimeConstant: time.Second}, model.LowPassFilter(1, 50*time.Millisecond),
+	)
+	assert.NilError(t, err)
+	const dt = 10 * time.Millisecond
+	o.Update(AntiWindupControllerInput{ReferenceSignal: 1, ActualSignal: 0.5, SamplingInterval: dt})
+	// When updating with an invalid actual signal
+	o.Update(AntiWindupControllerInput{ReferenceSignal: 1, ActualSignal: math.NaN(), SamplingInterval: dt})
+	// Then the estimate should remain finite
+	assert.Assert(t, !math.IsNaN(o.State.EstimatedDisturbance))
+	assert.Assert(t, !math.IsNaN(c.State.ControlSignal))
+	// When resetting the disturbance observer
+	o.Reset()
+	// Then the state of the observer and the wrapped controller should be reset
+	assert.Equal(t, DisturbanceObserverState{}, o.State)
+	assert.Equal(t, AntiWindupControllerState{}, c.State)
+}
+
+func TestNewDisturbanceObserver_Errors(t *testing.T) {
+	c := &AntiWindupController{}
+	nominalModel := model.FOPDT{Gain: 2, TimeConstant: time.Second}
+	_, err := NewAntiWindupDisturbanceObserver(c, nominalModel, model.TransferFunction{
+		Numerator:   []float64{2},
+		Denominator: []float64{0.1, 1},
+	})
+	assert.ErrorContains(t, err, "pid: new disturbance observer: Q-filter static gain 2 is not 1")
+	_, err = NewAntiWindupDisturbanceObserver(c, nominalModel, model.LowPassFilter(0, 0))
+	assert.ErrorContains(t, err, "Q-filter times inverse nominal model: model: new simulation:")
+	_, err = NewAntiWindupDisturbanceObserver(c, model.TransferFunction{}, model.LowPassFilter(1, time.Second))
+	assert.ErrorContains(t, err, "pid: new disturbance observer: nominal model: model: validate transfer function")
+}
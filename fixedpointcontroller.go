@@ -0,0 +1,184 @@
+package pid
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// FixedPointAntiWindupController implements an AntiWindupController in Q16 fixed-point arithmetic, for
+// microcontrollers without an FPU.
+//
+// The controller follows the same equations as AntiWindupController, with every intermediate result rounded to
+// the nearest Q16 and saturated to the Q16 range instead of overflowing. A config for the controller is
+// typically created from an AntiWindupControllerConfig with NewFixedPointAntiWindupControllerConfig.
+type FixedPointAntiWindupController struct {
+	// Config for the FixedPointAntiWindupController.
+	Config FixedPointAntiWindupControllerConfig
+	// State of the FixedPointAntiWindupController.
+	State FixedPointAntiWindupControllerState
+}
+
+// FixedPointAntiWindupControllerConfig contains config parameters for a FixedPointAntiWindupController.
+type FixedPointAntiWindupControllerConfig struct {
+	// ProportionalGain is the P part gain.
+	ProportionalGain Q16
+	// IntegralGain is the I part gain.
+	IntegralGain Q16
+	// DerivativeGain is the D part gain.
+	DerivativeGain Q16
+	// AntiWindUpGain is the anti-windup tracking gain.
+	AntiWindUpGain Q16
+	// IntegralDischargeTimeConstant is the time constant to discharge the integral state of the PID controller (s)
+	IntegralDischargeTimeConstant Q16
+	// LowPassTimeConstant is the D part low-pass filter time constant (s).
+	LowPassTimeConstant Q16
+	// MaxOutput is the max output from the PID.
+	MaxOutput Q16
+	// MinOutput is the min output from the PID.
+	MinOutput Q16
+}
+
+// FixedPointAntiWindupControllerState holds mutable state for a FixedPointAntiWindupController.
+type FixedPointAntiWindupControllerState struct {
+	// ControlError is the difference between reference and current value.
+	ControlError Q16
+	// ControlErrorIntegrand is the control error integrand, which includes the anti-windup correction.
+	ControlErrorIntegrand Q16
+	// ControlErrorIntegral is the control error integrand integrated over time.
+	ControlErrorIntegral Q16
+	// ControlErrorDerivative is the low-pass filtered time-derivative of the control error.
+	ControlErrorDerivative Q16
+	// ControlSignal is the current control signal output of the controller.
+	ControlSignal Q16
+	// UnsaturatedControlSignal is the control signal before saturation.
+	UnsaturatedControlSignal Q16
+}
+
+// FixedPointAntiWindupControllerInput holds the input parameters to a FixedPointAntiWindupController.
+type FixedPointAntiWindupControllerInput struct {
+	// ReferenceSignal is the reference value for the signal to control.
+	ReferenceSignal Q16
+	// ActualSignal is the actual value of the signal to control.
+	ActualSignal Q16
+	// FeedForwardSignal is the contribution of the feed-forward control loop in the controller output.
+	FeedForwardSignal Q16
+	// SamplingInterval is the time interval elapsed since the previous call of the controller Update method.
+	SamplingInterval time.Duration
+}
+
+// FixedPointQuantizationError holds the absolute quantization errors of a conversion to a
+// FixedPointAntiWindupControllerConfig.
+type FixedPointQuantizationError struct {
+	// ProportionalGain is the quantization error of the P part gain.
+	ProportionalGain float64
+	// IntegralGain is the quantization error of the I part gain.
+	IntegralGain float64
+	// DerivativeGain is the quantization error of the D part gain.
+	DerivativeGain float64
+	// AntiWindUpGain is the quantization error of the anti-windup tracking gain.
+	AntiWindUpGain float64
+	// IntegralDischargeTimeConstant is the quantization error of the integral discharge time constant (s).
+	IntegralDischargeTimeConstant float64
+	// LowPassTimeConstant is the quantization error of the D part low-pass filter time constant (s).
+	LowPassTimeConstant float64
+	// MaxOutput is the quantization error of the max output.
+	MaxOutput float64
+	// MinOutput is the quantization error of the min output.
+	MinOutput float64
+}
+
+// Max returns the largest quantization error.
+func (e FixedPointQuantizationError) Max() float64 {
+	return max(
+		e.ProportionalGain,
+		e.IntegralGain,
+		e.DerivativeGain,
+		e.AntiWindUpGain,
+		e.IntegralDischargeTimeConstant,
+		e.LowPassTimeConstant,
+		e.MaxOutput,
+		e.MinOutput,
+	)
+}
+
+// NewFixedPointAntiWindupControllerConfig converts an AntiWindupControllerConfig to a
+// FixedPointAntiWindupControllerConfig, and reports the quantization error of each parameter.
+//
+// An error is returned when a parameter is outside of the Q16 range.
+func NewFixedPointAntiWindupControllerConfig(
+	c AntiWindupControllerConfig,
+) (FixedPointAntiWindupControllerConfig, FixedPointQuantizationError, error) {
+	var result FixedPointAntiWindupControllerConfig
+	var quantizationError FixedPointQuantizationError
+	for _, parameter := range []struct {
+		name              string
+		value             float64
+		result            *Q16
+		quantizationError *float64
+	}{
+		{"proportional gain", c.ProportionalGain, &result.ProportionalGain, &quantizationError.ProportionalGain},
+		{"integral gain", c.IntegralGain, &result.IntegralGain, &quantizationError.IntegralGain},
+		{"derivative gain", c.DerivativeGain, &result.DerivativeGain, &quantizationError.DerivativeGain},
+		{"anti-windup gain", c.AntiWindUpGain, &result.AntiWindUpGain, &quantizationError.AntiWindUpGain},
+		{
+			"integral discharge time constant",
+			c.IntegralDischargeTimeConstant,
+			&result.IntegralDischargeTimeConstant,
+			&quantizationError.IntegralDischargeTimeConstant,
+		},
+		{
+			"low-pass time constant",
+			c.LowPassTimeConstant.Seconds(),
+			&result.LowPassTimeConstant,
+			&quantizationError.LowPassTimeConstant,
+		},
+		{"max output", c.MaxOutput, &result.MaxOutput, &quantizationError.MaxOutput},
+		{"min output", c.MinOutput, &result.MinOutput, &quantizationError.MinOutput},
+	} {
+		if math.IsNaN(parameter.value) || parameter.value < Q16Min.Float() || parameter.value > Q16Max.Float() {
+			return FixedPointAntiWindupControllerConfig{}, FixedPointQuantizationError{}, fmt.Errorf(
+				"pid: new fixed-point config: %s %v outside of Q16 range", parameter.name, parameter.value,
+			)
+		}
+		*parameter.result = Q16FromFloat(parameter.value)
+		*parameter.quantizationError = math.Abs(parameter.result.Float() - parameter.value)
+	}
+	return result, quantizationError, nil
+}
+
+// Reset the controller state.
+func (c *FixedPointAntiWindupController) Reset() {
+	c.State = FixedPointAntiWindupControllerState{}
+}
+
+// Update the controller state.
+func (c *FixedPointAntiWindupController) Update(input FixedPointAntiWindupControllerInput) {
+	dt := Q16FromDuration(input.SamplingInterval)
+	e := input.ReferenceSignal.Sub(input.ActualSignal)
+	controlErrorIntegral := c.State.ControlErrorIntegrand.Mul(dt).Add(c.State.ControlErrorIntegral)
+	// Equivalent to ((e-e')/Tf + D') / (dt/Tf + 1), with one division less.
+	controlErrorDerivative := e.Sub(c.State.ControlError).
+		Add(c.Config.LowPassTimeConstant.Mul(c.State.ControlErrorDerivative)).
+		Div(dt.Add(c.Config.LowPassTimeConstant))
+	c.State.UnsaturatedControlSignal = e.Mul(c.Config.ProportionalGain).
+		Add(c.Config.IntegralGain.Mul(controlErrorIntegral)).
+		Add(c.Config.DerivativeGain.Mul(controlErrorDerivative)).
+		Add(input.FeedForwardSignal)
+	c.State.ControlSignal = c.State.UnsaturatedControlSignal.Clamp(c.Config.MinOutput, c.Config.MaxOutput)
+	c.State.ControlErrorIntegrand = e.Add(
+		c.Config.AntiWindUpGain.Mul(c.State.ControlSignal.Sub(c.State.UnsaturatedControlSignal)),
+	)
+	c.State.ControlErrorIntegral = controlErrorIntegral
+	c.State.ControlErrorDerivative = controlErrorDerivative
+	c.State.ControlError = e
+}
+
+// DischargeIntegral provides the ability to discharge the controller integral state
+// over a configurable period of time.
+func (c *FixedPointAntiWindupController) DischargeIntegral(dt time.Duration) {
+	c.State.ControlErrorIntegrand = 0
+	c.State.ControlErrorIntegral = Q16One.Sub(
+		Q16FromDuration(dt).Div(c.Config.IntegralDischargeTimeConstant),
+	).Clamp(0, Q16One).Mul(c.State.ControlErrorIntegral)
+}
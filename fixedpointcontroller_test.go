@@ -0,0 +1,136 @@
+package pid
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestFixedPointAntiWindupController_Equivalence(t *testing.T) {
+	// Given a floating-point controller and its fixed-point conversion
+	config := AntiWindupControllerConfig{
+		ProportionalGain:              2.0,
+		IntegralGain:                  1.0,
+		DerivativeGain:                0.1,
+		AntiWindUpGain:                0.5,
+		IntegralDischargeTimeConstant: 10,
+		LowPassTimeConstant:           100 * time.Millisecond,
+		MaxOutput:                     5,
+		MinOutput:                     -5,
+	}
+	fixedPointConfig, quantizationError, err := NewFixedPointAntiWindupControllerConfig(config)
+	assert.NilError(t, err)
+	assert.Assert(t, quantizationError.Max() <= 0.5/float64(Q16One))
+	floatingPoint := &AntiWindupController{Config: config}
+	fixedPoint := &FixedPointAntiWindupController{Config: fixedPointConfig}
+	for i := range 1000 {
+		reference := math.Sin(float64(i) / 50)
+		actual := 0.5 * math.Cos(float64(i)/70)
+		// When updating both controllers with the same inputs
+		floatingPoint.Update(AntiWindupControllerInput{
+			ReferenceSignal:  reference,
+			ActualSignal:     actual,
+			SamplingInterval: dtTest,
+		})
+		fixedPoint.Update(FixedPointAntiWindupControllerInput{
+			ReferenceSignal:  Q16FromFloat(reference),
+			ActualSignal:     Q16FromFloat(actual),
+			SamplingInterval: dtTest,
+		})
+		// Then the control signals should be equal within the accumulated rounding error
+		assert.Assert(
+			t,
+			math.Abs(floatingPoint.State.ControlSignal-fixedPoint.State.ControlSignal.Float()) < 1e-2,
+			"step %d: %v != %v", i, floatingPoint.State.ControlSignal, fixedPoint.State.ControlSignal,
+		)
+	}
+}
+
+func TestFixedPointAntiWindupController_Saturation(t *testing.T) {
+	// Given a fixed-point PI controller with a large control error
+	config, _, err := NewFixedPointAntiWindupControllerConfig(AntiWindupControllerConfig{
+		ProportionalGain: 1000,
+		IntegralGain:     1000,
+		AntiWindUpGain:   1,
+		MaxOutput:        10,
+		MinOutput:        -10,
+	})
+	assert.NilError(t, err)
+	c := &FixedPointAntiWindupController{Config: config}
+	// When updating the controller
+	c.Update(FixedPointAntiWindupControllerInput{
+		ReferenceSignal:  Q16FromInt(1000),
+		ActualSignal:     Q16FromInt(-1000),
+		SamplingInterval: dtTest,
+	})
+	// Then the intermediate results should saturate instead of overflowing
+	assert.Equal(t, Q16Max, c.State.UnsaturatedControlSignal)
+	assert.Equal(t, Q16FromInt(10), c.State.ControlSignal)
+	for range 1000 {
+		c.Update(FixedPointAntiWindupControllerInput{
+			ReferenceSignal:  Q16FromInt(1000),
+			ActualSignal:     Q16FromInt(-1000),
+			SamplingInterval: dtTest,
+		})
+		// And the control signal should stay within the output limits
+		assert.Assert(t, c.State.ControlSignal >= config.MinOutput && c.State.ControlSignal <= config.MaxOutput)
+	}
+	// And the anti-windup should keep the integral from winding up
+	assert.Assert(t, c.State.ControlErrorIntegral < 0)
+}
+
+func TestFixedPointAntiWindupController_Reset(t *testing.T) {
+	// Given a fixed-point controller with non-zero state
+	c := &FixedPointAntiWindupController{
+		Config: FixedPointAntiWindupControllerConfig{
+			ProportionalGain: Q16One,
+			IntegralGain:     Q16One,
+			MaxOutput:        Q16FromInt(10),
+			MinOutput:        Q16FromInt(-10),
+		},
+	}
+	c.Update(FixedPointAntiWindupControllerInput{ReferenceSignal: Q16One, SamplingInterval: dtTest})
+	assert.Assert(t, c.State != FixedPointAntiWindupControllerState{})
+	// When resetting the controller
+	c.Reset()
+	// Then the state should be zero
+	assert.Equal(t, FixedPointAntiWindupControllerState{}, c.State)
+}
+
+func TestFixedPointAntiWindupController_DischargeIntegral(t *testing.T) {
+	// Given a fixed-point controller with an integral state
+	c := &FixedPointAntiWindupController{
+		Config: FixedPointAntiWindupControllerConfig{IntegralDischargeTimeConstant: Q16FromInt(10)},
+		State:  FixedPointAntiWindupControllerState{ControlErrorIntegral: Q16FromInt(4)},
+	}
+	// When discharging the integral for a second
+	c.DischargeIntegral(time.Second)
+	// Then the integral should be discharged by a tenth
+	assert.Assert(t, math.Abs(c.State.ControlErrorIntegral.Float()-3.6) < 1e-4)
+	// And when discharging for longer than the time constant, the integral should be zero
+	c.DischargeIntegral(20 * time.Second)
+	assert.Equal(t, Q16(0), c.State.ControlErrorIntegral)
+}
+
+func TestNewFixedPointAntiWindupControllerConfig(t *testing.T) {
+	t.Run("quantization error", func(t *testing.T) {
+		_, quantizationError, err := NewFixedPointAntiWindupControllerConfig(AntiWindupControllerConfig{
+			ProportionalGain: 0.1,
+			IntegralGain:     0.5,
+		})
+		assert.NilError(t, err)
+		assert.Assert(t, quantizationError.ProportionalGain > 0)
+		assert.Equal(t, 0.0, quantizationError.IntegralGain)
+		assert.Equal(t, math.Abs(Q16FromFloat(0.1).Float()-0.1), quantizationError.Max())
+	})
+	t.Run("out of range", func(t *testing.T) {
+		_, _, err := NewFixedPointAntiWindupControllerConfig(AntiWindupControllerConfig{MaxOutput: 1e5})
+		assert.ErrorContains(t, err, "max output")
+	})
+	t.Run("nan", func(t *testing.T) {
+		_, _, err := NewFixedPointAntiWindupControllerConfig(AntiWindupControllerConfig{IntegralGain: math.NaN()})
+		assert.ErrorContains(t, err, "integral gain")
+	})
+}
@@ -0,0 +1,33 @@
+package pid
+
+import "math"
+
+// Float is a constraint for the floating-point types supported by the generic controllers.
+type Float interface {
+	~float32 | ~float64
+}
+
+// maxFloat returns the largest finite value of T.
+func maxFloat[T Float]() T {
+	maxFloat32 := T(math.MaxFloat32)
+	if math.IsInf(float64(T(maxFloat32*2)), 0) {
+		// T is a float32, which overflows beyond math.MaxFloat32.
+		return maxFloat32
+	}
+	maxFloat64 := math.MaxFloat64
+	return T(maxFloat64)
+}
+
+// clampFinite clamps x to the finite range [-maxFloat, maxFloat] of T.
+func clampFinite[T Float](x T) T {
+	return clamp(x, -maxFloat[T](), maxFloat[T]())
+}
+
+// clamp x to the range [lo, hi], with the same NaN and signed zero semantics as math.Max(lo, math.Min(hi, x)).
+func clamp[T Float](x, lo, hi T) T {
+	return T(math.Max(float64(lo), math.Min(float64(hi), float64(x))))
+}
+
+func isNaNOrInf[T Float](x T) bool {
+	return math.IsNaN(float64(x)) || math.IsInf(float64(x), 0)
+}
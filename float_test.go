@@ -0,0 +1,186 @@
+package pid
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+const float32Tolerance = 1e-4
+
+func TestControllerOf_Float32Equivalence(t *testing.T) {
+	// Given float32 and float64 instantiations of the same PID controller
+	c64 := &Controller{Config: ControllerConfig{ProportionalGain: 2, IntegralGain: 1, DerivativeGain: 0.1}}
+	c32 := &ControllerOf[float32]{
+		Config: ControllerConfigOf[float32]{ProportionalGain: 2, IntegralGain: 1, DerivativeGain: 0.1},
+	}
+	// When updating them with the same inputs
+	for i := range 500 {
+		reference, actual := math.Sin(float64(i)/50), math.Cos(float64(i)/30)
+		c64.Update(ControllerInput{
+			ReferenceSignal:  reference,
+			ActualSignal:     actual,
+			SamplingInterval: dtTest,
+		})
+		c32.Update(ControllerInputOf[float32]{
+			ReferenceSignal:  float32(reference),
+			ActualSignal:     float32(actual),
+			SamplingInterval: dtTest,
+		})
+		// Then the states should be numerically equivalent
+		assertFloat32Equivalent(t, c64.State.ControlSignal, c32.State.ControlSignal)
+		assertFloat32Equivalent(t, c64.State.ControlErrorIntegral, c32.State.ControlErrorIntegral)
+	}
+}
+
+func TestAntiWindupControllerOf_Float32Equivalence(t *testing.T) {
+	// Given float32 and float64 instantiations of the same saturated PID controller
+	c64 := &AntiWindupController{
+		Config: AntiWindupControllerConfig{
+			LowPassTimeConstant: 1 * time.Second,
+			ProportionalGain:    1,
+			IntegralGain:        10,
+			DerivativeGain:      0.01,
+			AntiWindUpGain:      10,
+			MinOutput:           -10,
+			MaxOutput:           10,
+		},
+	}
+	c32 := &AntiWindupControllerOf[float32]{
+		Config: AntiWindupControllerConfigOf[float32]{
+			LowPassTimeConstant: 1 * time.Second,
+			ProportionalGain:    1,
+			IntegralGain:        10,
+			DerivativeGain:      0.01,
+			AntiWindUpGain:      10,
+			MinOutput:           -10,
+			MaxOutput:           10,
+		},
+	}
+	// When updating them with the same inputs, including saturating ones
+	for i := range 500 {
+		reference := 20 * math.Sin(float64(i)/50)
+		c64.Update(AntiWindupControllerInput{
+			ReferenceSignal:   reference,
+			ActualSignal:      c64.State.ControlSignal,
+			FeedForwardSignal: 1,
+			SamplingInterval:  dtTest,
+		})
+		c32.Update(AntiWindupControllerInputOf[float32]{
+			ReferenceSignal:   float32(reference),
+			ActualSignal:      c32.State.ControlSignal,
+			FeedForwardSignal: 1,
+			SamplingInterval:  dtTest,
+		})
+		// Then the states should be numerically equivalent
+		assertFloat32Equivalent(t, c64.State.ControlSignal, c32.State.ControlSignal)
+		assertFloat32Equivalent(t, c64.State.UnsaturatedControlSignal, c32.State.UnsaturatedControlSignal)
+		assertFloat32Equivalent(t, c64.State.ControlErrorIntegral, c32.State.ControlErrorIntegral)
+	}
+	c64.DischargeIntegral(dtTest)
+	c32.DischargeIntegral(dtTest)
+	assertFloat32Equivalent(t, c64.State.ControlErrorIntegral, c32.State.ControlErrorIntegral)
+}
+
+func TestTrackingControllerOf_Float32Equivalence(t *testing.T) {
+	// Given float32 and float64 instantiations of the same tracking PID controller
+	c64 := &TrackingController{
+		Config: TrackingControllerConfig{
+			LowPassTimeConstant: 1 * time.Second,
+			ProportionalGain:    1,
+			IntegralGain:        10,
+			DerivativeGain:      0.01,
+			AntiWindUpGain:      10,
+			MinOutput:           -10,
+			MaxOutput:           10,
+		},
+	}
+	c32 := &TrackingControllerOf[float32]{
+		Config: TrackingControllerConfigOf[float32]{
+			LowPassTimeConstant: 1 * time.Second,
+			ProportionalGain:    1,
+			IntegralGain:        10,
+			DerivativeGain:      0.01,
+			AntiWindUpGain:      10,
+			MinOutput:           -10,
+			MaxOutput:           10,
+		},
+	}
+	// When updating them with the same inputs and a rate-limited actuator
+	for i := range 500 {
+		reference := 20 * math.Sin(float64(i)/50)
+		c64.Update(TrackingControllerInput{
+			ReferenceSignal:      reference,
+			ActualSignal:         c64.State.ControlSignal,
+			AppliedControlSignal: c64.State.ControlSignal * 0.9,
+			SamplingInterval:     dtTest,
+		})
+		c32.Update(TrackingControllerInputOf[float32]{
+			ReferenceSignal:      float32(reference),
+			ActualSignal:         c32.State.ControlSignal,
+			AppliedControlSignal: c32.State.ControlSignal * 0.9,
+			SamplingInterval:     dtTest,
+		})
+		// Then the states should be numerically equivalent
+		assertFloat32Equivalent(t, c64.State.ControlSignal, c32.State.ControlSignal)
+		assertFloat32Equivalent(t, c64.State.ControlErrorIntegral, c32.State.ControlErrorIntegral)
+	}
+}
+
+func TestAntiWindupControllerOf_Float32Clamping(t *testing.T) {
+	// Given a float32 controller fed with measurements at the limit of the float32 range
+	c := &AntiWindupControllerOf[float32]{
+		Config: AntiWindupControllerConfigOf[float32]{
+			LowPassTimeConstant: 1 * time.Second,
+			IntegralGain:        10,
+			AntiWindUpGain:      0.01,
+			MinOutput:           -10,
+			MaxOutput:           10,
+		},
+	}
+	for range 220 {
+		c.Update(AntiWindupControllerInputOf[float32]{
+			ReferenceSignal:  5,
+			ActualSignal:     -math.MaxFloat32,
+			SamplingInterval: dtTest,
+		})
+	}
+	// Then the state should remain finite
+	assert.Assert(t, !isNaNOrInf(c.State.ControlError))
+	assert.Assert(t, !isNaNOrInf(c.State.ControlErrorIntegral))
+	assert.Assert(t, !isNaNOrInf(c.State.ControlErrorDerivative))
+	assert.Equal(t, float32(10), c.State.ControlSignal)
+}
+
+func TestAntiWindupControllerStateOf_Float32Checkpoint(t *testing.T) {
+	// Given a float32 state
+	expected := AntiWindupControllerStateOf[float32]{ControlErrorIntegral: 1.5, ControlSignal: -2.25}
+	data, err := expected.MarshalBinary()
+	assert.NilError(t, err)
+	// Then it should be restorable as both a float32 and a float64 state
+	var actual32 AntiWindupControllerStateOf[float32]
+	assert.NilError(t, actual32.UnmarshalBinary(data))
+	assert.Equal(t, expected, actual32)
+	var actual64 AntiWindupControllerState
+	assert.NilError(t, actual64.UnmarshalBinary(data))
+	assert.Equal(t, AntiWindupControllerState{ControlErrorIntegral: 1.5, ControlSignal: -2.25}, actual64)
+}
+
+type namedFloat32 float32
+
+func TestMaxFloat(t *testing.T) {
+	assert.Equal(t, float32(math.MaxFloat32), maxFloat[float32]())
+	assert.Equal(t, namedFloat32(math.MaxFloat32), maxFloat[namedFloat32]())
+	assert.Equal(t, math.MaxFloat64, maxFloat[float64]())
+}
+
+func assertFloat32Equivalent(t *testing.T, expected float64, actual float32) {
+	t.Helper()
+	assert.Assert(
+		t,
+		math.Abs(expected-float64(actual)) <= float32Tolerance*math.Max(1, math.Abs(expected)),
+		"expected %v, got %v", expected, actual,
+	)
+}
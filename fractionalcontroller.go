@@ -0,0 +1,220 @@
+package pid
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"time"
+)
+
+// DefaultFractionalMemoryLength is the default number of samples in the memory of a FractionalController.
+const DefaultFractionalMemoryLength = 100
+
+// FractionalController implements a fractional-order PI^λD^μ controller with low-pass filter of the derivative
+// term, feed forward term, a saturated control output and anti-windup.
+//
+// The fractional-order derivative and integral are approximated with Grünwald–Letnikov sums over the most recent
+// samples, as defined in Podlubny, Fractional-order systems and PI^λD^μ-controllers, 1999
+// (https://doi.org/10.1109/9.739144). The memory of the sums is truncated to a configurable number of samples,
+// and the sampling interval is assumed to be constant.
+//
+// The integral of order λ is computed as the integer integral of the fractional derivative of order 1-λ of the
+// control error integrand, so that the integral state is retained in full regardless of the memory length, and
+// the saturation, anti-windup and integral discharge semantics are the same as for an AntiWindupController. With
+// orders λ = μ = 1 the controller is equivalent to an AntiWindupController.
+type FractionalController struct {
+	// Config for the FractionalController.
+	Config FractionalControllerConfig
+	// State of the FractionalController.
+	State FractionalControllerState
+}
+
+// FractionalControllerConfig contains config parameters for a FractionalController.
+type FractionalControllerConfig struct {
+	// ProportionalGain is the P part gain.
+	ProportionalGain float64
+	// IntegralGain is the I part gain.
+	IntegralGain float64
+	// DerivativeGain is the D part gain.
+	DerivativeGain float64
+	// IntegralOrder is the order λ of the I part, typically in (0, 2).
+	IntegralOrder float64
+	// DerivativeOrder is the order μ of the D part, typically in (0, 2).
+	DerivativeOrder float64
+	// MemoryLength is the number of samples in the Grünwald–Letnikov sums. Defaults to
+	// DefaultFractionalMemoryLength when zero.
+	MemoryLength int
+	// AntiWindUpGain is the anti-windup tracking gain.
+	AntiWindUpGain float64
+	// IntegralDischargeTimeConstant is the time constant to discharge the integral state of the PID controller (s)
+	IntegralDischargeTimeConstant float64
+	// LowPassTimeConstant is the D part low-pass filter time constant => cut-off frequency 1/LowPassTimeConstant.
+	// Zero disables the filter.
+	LowPassTimeConstant time.Duration
+	// MaxOutput is the max output from the PID.
+	MaxOutput float64
+	// MinOutput is the min output from the PID.
+	MinOutput float64
+}
+
+// FractionalControllerState holds mutable state for a FractionalController.
+type FractionalControllerState struct {
+	// ControlError is the difference between reference and current value.
+	ControlError float64
+	// ControlErrorIntegrand is the control error integrand, which includes the anti-windup correction.
+	ControlErrorIntegrand float64
+	// ControlErrorIntegral is the fractional-order integral of the control error integrand.
+	ControlErrorIntegral float64
+	// ControlErrorDerivative is the low-pass filtered fractional-order derivative of the control error.
+	ControlErrorDerivative float64
+	// ControlSignal is the current control signal output of the controller.
+	ControlSignal float64
+	// UnsaturatedControlSignal is the control signal before saturation.
+	UnsaturatedControlSignal float64
+	// ControlErrors is the memory of the most recent control errors, a ring buffer with the latest at
+	// MemoryHead.
+	ControlErrors []float64
+	// ControlErrorIntegrands is the memory of the most recent control error integrands, a ring buffer with the
+	// latest at MemoryHead.
+	ControlErrorIntegrands []float64
+	// MemoryHead is the index of the latest sample in ControlErrors and ControlErrorIntegrands.
+	MemoryHead int
+}
+
+// Clone returns a copy of the state that does not share memory with the state.
+//
+// The memory is updated in place, so a copy of the state by value changes with updates of the controller, and a
+// state to restore later should be saved with Clone.
+func (s FractionalControllerState) Clone() FractionalControllerState {
+	s.ControlErrors = slices.Clone(s.ControlErrors)
+	s.ControlErrorIntegrands = slices.Clone(s.ControlErrorIntegrands)
+	return s
+}
+
+// FractionalControllerInput holds the input parameters to a FractionalController.
+type FractionalControllerInput struct {
+	// ReferenceSignal is the reference value for the signal to control.
+	ReferenceSignal float64
+	// ActualSignal is the actual value of the signal to control.
+	ActualSignal float64
+	// FeedForwardSignal is the contribution of the feed-forward control loop in the controller output.
+	FeedForwardSignal float64
+	// SamplingInterval is the time interval elapsed since the previous call of the controller Update method.
+	SamplingInterval time.Duration
+}
+
+// Validate returns an error when a parameter of the config is NaN or infinite, when an order is outside of
+// [0, 2], when the memory length or a time constant is negative, or when MinOutput is larger than MaxOutput.
+func (c FractionalControllerConfig) Validate() error {
+	if err := validateFinite(
+		finiteParameter[float64]{"proportional gain", c.ProportionalGain},
+		finiteParameter[float64]{"integral gain", c.IntegralGain},
+		finiteParameter[float64]{"derivative gain", c.DerivativeGain},
+		finiteParameter[float64]{"integral order", c.IntegralOrder},
+		finiteParameter[float64]{"derivative order", c.DerivativeOrder},
+		finiteParameter[float64]{"anti-windup gain", c.AntiWindUpGain},
+		finiteParameter[float64]{"integral discharge time constant", c.IntegralDischargeTimeConstant},
+	); err != nil {
+		return err
+	}
+	switch {
+	case c.IntegralOrder < 0 || c.IntegralOrder > 2:
+		return fmt.Errorf("pid: invalid config: integral order %v outside of [0, 2]", c.IntegralOrder)
+	case c.DerivativeOrder < 0 || c.DerivativeOrder > 2:
+		return fmt.Errorf("pid: invalid config: derivative order %v outside of [0, 2]", c.DerivativeOrder)
+	case c.MemoryLength < 0:
+		return fmt.Errorf("pid: invalid config: negative memory length %d", c.MemoryLength)
+	case c.IntegralDischargeTimeConstant < 0:
+		return fmt.Errorf("pid: invalid config: negative integral discharge time constant %v",
+			c.IntegralDischargeTimeConstant)
+	case c.LowPassTimeConstant < 0:
+		return fmt.Errorf("pid: invalid config: negative low-pass time constant %v", c.LowPassTimeConstant)
+	case math.IsNaN(c.MinOutput) || math.IsNaN(c.MaxOutput):
+		return fmt.Errorf("pid: invalid config: NaN output limit")
+	case c.MinOutput > c.MaxOutput:
+		return fmt.Errorf("pid: invalid config: min output %v larger than max output %v", c.MinOutput, c.MaxOutput)
+	}
+	return nil
+}
+
+// Reset the controller state.
+func (c *FractionalController) Reset() {
+	c.State = FractionalControllerState{}
+}
+
+// Update the controller state.
+//
+// Changing the memory length of the config clears the memory of the controller on the next update.
+func (c *FractionalController) Update(input FractionalControllerInput) {
+	if isNaNOrInf(input.ReferenceSignal) || isNaNOrInf(input.ActualSignal) {
+		return
+	}
+	n := c.Config.MemoryLength
+	if n <= 0 {
+		n = DefaultFractionalMemoryLength
+	}
+	if len(c.State.ControlErrors) != n || len(c.State.ControlErrorIntegrands) != n {
+		c.State.ControlErrors = make([]float64, n)
+		c.State.ControlErrorIntegrands = make([]float64, n)
+		c.State.MemoryHead = 0
+	}
+	dt := input.SamplingInterval.Seconds()
+	lowPassTimeConstant := c.Config.LowPassTimeConstant.Seconds()
+	// I^λ = I^1 D^(1-λ), with the latest integrand from the previous update as in the AntiWindupController.
+	controlErrorIntegral := c.State.ControlErrorIntegral + math.Pow(dt, c.Config.IntegralOrder)*
+		grunwaldLetnikovSum(1-c.Config.IntegralOrder, c.State.ControlErrorIntegrands, c.State.MemoryHead)
+	c.State.MemoryHead = (c.State.MemoryHead + 1) % n
+	e := input.ReferenceSignal - input.ActualSignal
+	c.State.ControlErrors[c.State.MemoryHead] = e
+	// Low-pass filter of the derivative, as ((e-e')/Tf + D') / (dt/Tf + 1) for μ = 1.
+	controlErrorDerivative := (lowPassTimeConstant*c.State.ControlErrorDerivative +
+		math.Pow(dt, 1-c.Config.DerivativeOrder)*
+			grunwaldLetnikovSum(c.Config.DerivativeOrder, c.State.ControlErrors, c.State.MemoryHead)) /
+		(lowPassTimeConstant + dt)
+	c.State.UnsaturatedControlSignal = e*c.Config.ProportionalGain + c.Config.IntegralGain*controlErrorIntegral +
+		c.Config.DerivativeGain*controlErrorDerivative + input.FeedForwardSignal
+	c.State.ControlSignal = clamp(c.State.UnsaturatedControlSignal, c.Config.MinOutput, c.Config.MaxOutput)
+	c.State.ControlErrorIntegrand = e + c.Config.AntiWindUpGain*(c.State.ControlSignal-c.State.UnsaturatedControlSignal)
+	c.State.ControlErrorIntegrand = clampFinite(c.State.ControlErrorIntegrand)
+	c.State.ControlErrorIntegrands[c.State.MemoryHead] = c.State.ControlErrorIntegrand
+	c.State.ControlErrorIntegral = clampFinite(controlErrorIntegral)
+	c.State.ControlErrorDerivative = clampFinite(controlErrorDerivative)
+	c.State.ControlError = clampFinite(e)
+}
+
+// DischargeIntegral provides the ability to discharge the controller integral state
+// over a configurable period of time.
+//
+// The memory of the integrand is cleared, so that it does not charge the integral again.
+func (c *FractionalController) DischargeIntegral(dt time.Duration) {
+	c.State.ControlErrorIntegrand = 0.0
+	clear(c.State.ControlErrorIntegrands)
+	c.State.ControlErrorIntegral = clamp(
+		1-dt.Seconds()/c.Config.IntegralDischargeTimeConstant, 0, 1.0,
+	) * c.State.ControlErrorIntegral
+}
+
+// grunwaldLetnikovSum returns the sum of the Grünwald–Letnikov weights of the order times the samples of the ring
+// buffer, from the latest sample at head and backwards.
+//
+// The weights are the signed binomial coefficients w_j = (-1)^j (order choose j), computed with the recurrence
+// w_0 = 1, w_j = w_{j-1} (1 - (order+1)/j).
+func grunwaldLetnikovSum(order float64, samples []float64, head int) float64 {
+	var sum float64
+	w := 1.0
+	for j := range samples {
+		if j > 0 {
+			w *= 1 - (order+1)/float64(j)
+			if w == 0 {
+				// All remaining weights are zero for integer orders.
+				break
+			}
+		}
+		i := head - j
+		if i < 0 {
+			i += len(samples)
+		}
+		sum += w * samples[i]
+	}
+	return sum
+}
@@ -0,0 +1,238 @@
+package pid
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestFractionalController_IntegerOrders(t *testing.T) {
+	// Given a fractional controller with integer orders and an AntiWindupController with the same config
+	awc := &AntiWindupController{
+		Config: AntiWindupControllerConfig{
+			ProportionalGain:    2,
+			IntegralGain:        1,
+			DerivativeGain:      0.5,
+			AntiWindUpGain:      0.5,
+			LowPassTimeConstant: 100 * time.Millisecond,
+			MinOutput:           -2,
+			MaxOutput:           2,
+		},
+	}
+	fc := &FractionalController{
+		Config: FractionalControllerConfig{
+			ProportionalGain:    2,
+			IntegralGain:        1,
+			DerivativeGain:      0.5,
+			IntegralOrder:       1,
+			DerivativeOrder:     1,
+			MemoryLength:        10,
+			AntiWindUpGain:      0.5,
+			LowPassTimeConstant: 100 * time.Millisecond,
+			MinOutput:           -2,
+			MaxOutput:           2,
+		},
+	}
+	// When updating both with the same saturating inputs
+	for i := range 1000 {
+		reference := 5 * math.Sin(float64(i)/100)
+		actual := math.Sin(float64(i) / 50)
+		awc.Update(AntiWindupControllerInput{
+			ReferenceSignal:   reference,
+			ActualSignal:      actual,
+			FeedForwardSignal: 0.1,
+			SamplingInterval:  dtTest,
+		})
+		fc.Update(FractionalControllerInput{
+			ReferenceSignal:   reference,
+			ActualSignal:      actual,
+			FeedForwardSignal: 0.1,
+			SamplingInterval:  dtTest,
+		})
+		// Then the controllers should be equivalent
+		assert.Assert(t, math.Abs(awc.State.UnsaturatedControlSignal-fc.State.UnsaturatedControlSignal) < 1e-9)
+		assert.Equal(t, awc.State.ControlSignal == awc.State.UnsaturatedControlSignal,
+			fc.State.ControlSignal == fc.State.UnsaturatedControlSignal)
+	}
+	assert.Assert(t, math.Abs(awc.State.ControlErrorIntegral-fc.State.ControlErrorIntegral) < 1e-9)
+}
+
+func TestFractionalController_HalfIntegral(t *testing.T) {
+	// Given a fractional I^0.5 controller
+	c := &FractionalController{
+		Config: FractionalControllerConfig{
+			IntegralGain:  1,
+			IntegralOrder: 0.5,
+			MemoryLength:  2000,
+			MinOutput:     math.Inf(-1),
+			MaxOutput:     math.Inf(1),
+		},
+	}
+	// When integrating a unit control error for 1s
+	for range 1000 {
+		c.Update(FractionalControllerInput{ReferenceSignal: 1, SamplingInterval: time.Millisecond})
+	}
+	// Then the integral should approximate the half-integral of the unit step, 2*sqrt(t/π)
+	assert.Assert(t, math.Abs(c.State.ControlSignal-2/math.Sqrt(math.Pi)) < 1e-2, c.State.ControlSignal)
+}
+
+func TestFractionalController_HalfDerivative(t *testing.T) {
+	// Given an unfiltered fractional D^0.5 controller
+	c := &FractionalController{
+		Config: FractionalControllerConfig{
+			DerivativeGain:  1,
+			DerivativeOrder: 0.5,
+			MemoryLength:    2000,
+			MinOutput:       math.Inf(-1),
+			MaxOutput:       math.Inf(1),
+		},
+	}
+	// When differentiating a unit ramp control error for 1s
+	for i := range 1000 {
+		c.Update(FractionalControllerInput{ReferenceSignal: float64(i+1) / 1000, SamplingInterval: time.Millisecond})
+	}
+	// Then the derivative should approximate the half-derivative of the unit ramp, 2*sqrt(t/π)
+	assert.Assert(t, math.Abs(c.State.ControlSignal-2/math.Sqrt(math.Pi)) < 1e-2, c.State.ControlSignal)
+}
+
+func TestFractionalController_AntiWindup(t *testing.T) {
+	// Given saturated fractional PI controllers with and without anti-windup
+	config := FractionalControllerConfig{
+		ProportionalGain: 1,
+		IntegralGain:     1,
+		IntegralOrder:    0.7,
+		DerivativeOrder:  1,
+		MinOutput:        -1,
+		MaxOutput:        1,
+	}
+	windup := &FractionalController{Config: config}
+	config.AntiWindUpGain = 1
+	antiWindup := &FractionalController{Config: config}
+	// When saturating for a long time
+	for range 1000 {
+		windup.Update(FractionalControllerInput{ReferenceSignal: 10, SamplingInterval: dtTest})
+		antiWindup.Update(FractionalControllerInput{ReferenceSignal: 10, SamplingInterval: dtTest})
+	}
+	// Then the anti-windup should limit the integral
+	assert.Equal(t, 1.0, antiWindup.State.ControlSignal)
+	assert.Assert(t, antiWindup.State.ControlErrorIntegral < windup.State.ControlErrorIntegral/10)
+}
+
+func TestFractionalController_NaN(t *testing.T) {
+	// Given a fractional PID controller with state
+	c := &FractionalController{
+		Config: FractionalControllerConfig{
+			ProportionalGain: 1,
+			IntegralGain:     1,
+			DerivativeGain:   1,
+			IntegralOrder:    0.5,
+			DerivativeOrder:  0.5,
+			MinOutput:        -10,
+			MaxOutput:        10,
+		},
+	}
+	c.Update(FractionalControllerInput{ReferenceSignal: 1, SamplingInterval: dtTest})
+	expected := c.State
+	// When updating with NaN and Inf inputs
+	c.Update(FractionalControllerInput{ReferenceSignal: math.NaN(), SamplingInterval: dtTest})
+	c.Update(FractionalControllerInput{ActualSignal: math.Inf(1), SamplingInterval: dtTest})
+	// Then the state should be unchanged
+	assert.Equal(t, expected.ControlSignal, c.State.ControlSignal)
+	assert.Equal(t, expected.ControlErrorIntegral, c.State.ControlErrorIntegral)
+	assert.Equal(t, expected.ControlErrorDerivative, c.State.ControlErrorDerivative)
+	assert.Equal(t, expected.MemoryHead, c.State.MemoryHead)
+}
+
+func TestFractionalController_RestoreState(t *testing.T) {
+	// Given a fractional PID controller with a saved state
+	c := &FractionalController{
+		Config: FractionalControllerConfig{
+			ProportionalGain: 1,
+			IntegralGain:     1,
+			DerivativeGain:   1,
+			IntegralOrder:    0.5,
+			DerivativeOrder:  0.5,
+			MemoryLength:     10,
+			MinOutput:        -10,
+			MaxOutput:        10,
+		},
+	}
+	for range 5 {
+		c.Update(FractionalControllerInput{ReferenceSignal: 1, SamplingInterval: dtTest})
+	}
+	saved := c.State.Clone()
+	c.Update(FractionalControllerInput{ReferenceSignal: 2, SamplingInterval: dtTest})
+	expected := c.State.Clone()
+	// When updating further and restoring the saved state
+	for range 20 {
+		c.Update(FractionalControllerInput{ReferenceSignal: -1, SamplingInterval: dtTest})
+	}
+	c.DischargeIntegral(time.Second)
+	c.State = saved.Clone()
+	c.Update(FractionalControllerInput{ReferenceSignal: 2, SamplingInterval: dtTest})
+	// Then the controller should continue from the saved state
+	assert.DeepEqual(t, expected, c.State)
+}
+
+func TestFractionalController_Update_NoAllocations(t *testing.T) {
+	// Given a fractional PID controller with its memory allocated
+	c := &FractionalController{
+		Config: FractionalControllerConfig{
+			ProportionalGain: 1,
+			IntegralGain:     1,
+			DerivativeGain:   1,
+			IntegralOrder:    0.5,
+			DerivativeOrder:  0.5,
+			MinOutput:        -10,
+			MaxOutput:        10,
+		},
+	}
+	input := FractionalControllerInput{ReferenceSignal: 1, SamplingInterval: dtTest}
+	c.Update(input)
+	// When updating
+	// Then no memory should be allocated
+	assert.Equal(t, 0.0, testing.AllocsPerRun(100, func() { c.Update(input) }))
+}
+
+func TestFractionalController_DischargeIntegral(t *testing.T) {
+	// Given a fractional PI controller with a charged integral
+	c := &FractionalController{
+		Config: FractionalControllerConfig{
+			IntegralGain:                  1,
+			IntegralOrder:                 0.5,
+			IntegralDischargeTimeConstant: 1,
+			MinOutput:                     -10,
+			MaxOutput:                     10,
+		},
+	}
+	for range 100 {
+		c.Update(FractionalControllerInput{ReferenceSignal: 1, SamplingInterval: dtTest})
+	}
+	integral := c.State.ControlErrorIntegral
+	// When discharging half of the integral
+	c.DischargeIntegral(500 * time.Millisecond)
+	// Then
+	assert.Equal(t, integral/2, c.State.ControlErrorIntegral)
+	// And the memory of the integrand should not charge the integral again
+	c.Update(FractionalControllerInput{SamplingInterval: dtTest})
+	assert.Equal(t, integral/2, c.State.ControlErrorIntegral)
+}
+
+func TestFractionalController_Reset(t *testing.T) {
+	c := &FractionalController{Config: FractionalControllerConfig{ProportionalGain: 1, MaxOutput: 10}}
+	c.Update(FractionalControllerInput{ReferenceSignal: 1, SamplingInterval: dtTest})
+	c.Reset()
+	assert.Equal(t, 0.0, c.State.ControlSignal)
+	assert.Assert(t, c.State.ControlErrors == nil)
+}
+
+func TestFractionalControllerConfig_Validate(t *testing.T) {
+	assert.NilError(t, FractionalControllerConfig{IntegralOrder: 0.5, DerivativeOrder: 1.5}.Validate())
+	assert.ErrorContains(t, FractionalControllerConfig{IntegralOrder: 3}.Validate(), "integral order 3 outside of [0, 2]")
+	assert.ErrorContains(t, FractionalControllerConfig{MemoryLength: -1}.Validate(), "negative memory length -1")
+	assert.ErrorContains(
+		t, FractionalControllerConfig{DerivativeOrder: math.NaN()}.Validate(), "derivative order NaN not finite",
+	)
+}
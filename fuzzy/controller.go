@@ -0,0 +1,85 @@
+package fuzzy
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"go.einride.tech/pid"
+)
+
+// Controller implements a fuzzy gain-adaptive AntiWindupController.
+//
+// Before each update, the gains of the wrapped controller are set to the base gains of the config plus the
+// adjustments inferred by the rule base from the control error of the update and the filtered control error
+// derivative of the previous update. The adapted gains are clamped to be non-negative.
+type Controller struct {
+	// Config for the Controller.
+	Config ControllerConfig
+	// State of the Controller.
+	State ControllerState
+
+	// buffer holds the membership degrees and activations of the rule base, reused across updates.
+	buffer []float64
+}
+
+// ControllerConfig contains configurable parameters for a Controller.
+type ControllerConfig struct {
+	// Controller is the config of the wrapped controller, with the base gains.
+	Controller pid.AntiWindupControllerConfig
+	// RuleBase infers the gain adjustments.
+	RuleBase RuleBase
+}
+
+// ControllerState holds mutable state for a Controller.
+type ControllerState struct {
+	// Controller is the state of the wrapped controller.
+	Controller pid.AntiWindupControllerState
+	// ProportionalGain is the adapted P part gain of the latest update.
+	ProportionalGain float64
+	// IntegralGain is the adapted I part gain of the latest update.
+	IntegralGain float64
+	// DerivativeGain is the adapted D part gain of the latest update.
+	DerivativeGain float64
+}
+
+// Validate returns an error when the config of the wrapped controller or the rule base is invalid.
+func (c ControllerConfig) Validate() error {
+	if err := c.Controller.Validate(); err != nil {
+		return fmt.Errorf("fuzzy: invalid config: %w", err)
+	}
+	return c.RuleBase.Validate()
+}
+
+// Reset the controller state.
+func (c *Controller) Reset() {
+	c.State = ControllerState{}
+}
+
+// Update the controller state.
+func (c *Controller) Update(input pid.AntiWindupControllerInput) {
+	controller := pid.AntiWindupController{Config: c.Config.Controller, State: c.State.Controller}
+	if !math.IsNaN(input.ReferenceSignal) && !math.IsNaN(input.ActualSignal) &&
+		!math.IsInf(input.ReferenceSignal, 0) && !math.IsInf(input.ActualSignal, 0) {
+		var adjustment Adjustment
+		adjustment, c.buffer = c.Config.RuleBase.infer(
+			input.ReferenceSignal-input.ActualSignal, c.State.Controller.ControlErrorDerivative, c.buffer,
+		)
+		controller.Config.ProportionalGain = math.Max(0, controller.Config.ProportionalGain+adjustment.ProportionalGain)
+		controller.Config.IntegralGain = math.Max(0, controller.Config.IntegralGain+adjustment.IntegralGain)
+		controller.Config.DerivativeGain = math.Max(0, controller.Config.DerivativeGain+adjustment.DerivativeGain)
+		c.State.ProportionalGain = controller.Config.ProportionalGain
+		c.State.IntegralGain = controller.Config.IntegralGain
+		c.State.DerivativeGain = controller.Config.DerivativeGain
+	}
+	controller.Update(input)
+	c.State.Controller = controller.State
+}
+
+// DischargeIntegral provides the ability to discharge the controller integral state
+// over a configurable period of time.
+func (c *Controller) DischargeIntegral(dt time.Duration) {
+	controller := pid.AntiWindupController{Config: c.Config.Controller, State: c.State.Controller}
+	controller.DischargeIntegral(dt)
+	c.State.Controller = controller.State
+}
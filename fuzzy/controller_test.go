@@ -0,0 +1,112 @@
+package fuzzy
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"go.einride.tech/pid"
+	"gotest.tools/v3/assert"
+)
+
+func TestController_Update(t *testing.T) {
+	// Given a fuzzy gain-adaptive controller
+	c := &Controller{
+		Config: ControllerConfig{
+			Controller: pid.AntiWindupControllerConfig{
+				ProportionalGain:    1,
+				IntegralGain:        1,
+				DerivativeGain:      0.1,
+				AntiWindUpGain:      0.5,
+				LowPassTimeConstant: 100 * time.Millisecond,
+				MinOutput:           -10,
+				MaxOutput:           10,
+			},
+			RuleBase: newTestRuleBase(),
+		},
+	}
+	assert.NilError(t, c.Config.Validate())
+	// When updating with a large control error
+	c.Update(pid.AntiWindupControllerInput{ReferenceSignal: 1, SamplingInterval: 10 * time.Millisecond})
+	// Then the proportional gain should be increased and the integral gain decreased
+	assert.Equal(t, 2.0, c.State.ProportionalGain)
+	assert.Equal(t, 0.0, c.State.IntegralGain)
+	assert.Equal(t, 0.1, c.State.DerivativeGain)
+	// And the wrapped controller should use the adapted gains
+	plain := pid.AntiWindupController{Config: c.Config.Controller}
+	plain.Config.ProportionalGain = 2
+	plain.Config.IntegralGain = 0
+	plain.Update(pid.AntiWindupControllerInput{ReferenceSignal: 1, SamplingInterval: 10 * time.Millisecond})
+	assert.Equal(t, plain.State, c.State.Controller)
+	// When updating with a zero control error
+	c.Update(pid.AntiWindupControllerInput{ReferenceSignal: 0, SamplingInterval: 10 * time.Millisecond})
+	// Then the base gains should be used
+	assert.Equal(t, 1.0, c.State.ProportionalGain)
+	assert.Equal(t, 1.0, c.State.IntegralGain)
+}
+
+func TestController_Update_NonNegativeGains(t *testing.T) {
+	// Given a fuzzy controller with base gains smaller than the adjustments
+	c := &Controller{
+		Config: ControllerConfig{
+			Controller: pid.AntiWindupControllerConfig{ProportionalGain: 1, IntegralGain: 0.25},
+			RuleBase:   newTestRuleBase(),
+		},
+	}
+	// When decreasing the integral gain by more than the base gain
+	c.Update(pid.AntiWindupControllerInput{ReferenceSignal: 1, SamplingInterval: 10 * time.Millisecond})
+	// Then the gain should be clamped to zero
+	assert.Equal(t, 0.0, c.State.IntegralGain)
+}
+
+func TestController_Update_NaN(t *testing.T) {
+	// Given a fuzzy controller with state
+	c := &Controller{
+		Config: ControllerConfig{
+			Controller: pid.AntiWindupControllerConfig{
+				ProportionalGain:    1,
+				IntegralGain:        1,
+				LowPassTimeConstant: 100 * time.Millisecond,
+			},
+			RuleBase: newTestRuleBase(),
+		},
+	}
+	c.Update(pid.AntiWindupControllerInput{ReferenceSignal: 0.5, SamplingInterval: 10 * time.Millisecond})
+	expected := c.State
+	// When updating with an invalid input
+	c.Update(pid.AntiWindupControllerInput{ReferenceSignal: math.NaN(), SamplingInterval: 10 * time.Millisecond})
+	// Then the state should be unchanged
+	assert.Equal(t, expected, c.State)
+}
+
+func TestController_Update_NoAllocations(t *testing.T) {
+	// Given a fuzzy controller with the default centroid defuzzifier
+	ruleBase := newTestRuleBase()
+	ruleBase.Defuzzifier = nil
+	c := &Controller{
+		Config: ControllerConfig{
+			Controller: pid.AntiWindupControllerConfig{ProportionalGain: 1, IntegralGain: 1},
+			RuleBase:   ruleBase,
+		},
+	}
+	input := pid.AntiWindupControllerInput{ReferenceSignal: 0.5, SamplingInterval: 10 * time.Millisecond}
+	c.Update(input)
+	// When updating
+	// Then no memory should be allocated
+	assert.Equal(t, 0.0, testing.AllocsPerRun(100, func() { c.Update(input) }))
+}
+
+func TestController_DischargeIntegral(t *testing.T) {
+	// Given a fuzzy controller with a charged integral
+	c := &Controller{
+		Config: ControllerConfig{Controller: pid.AntiWindupControllerConfig{IntegralDischargeTimeConstant: 1}},
+	}
+	c.State.Controller.ControlErrorIntegral = 10
+	// When discharging half of the integral
+	c.DischargeIntegral(500 * time.Millisecond)
+	// Then
+	assert.Equal(t, 5.0, c.State.Controller.ControlErrorIntegral)
+	// And resetting should clear the state
+	c.Reset()
+	assert.Equal(t, ControllerState{}, c.State)
+}
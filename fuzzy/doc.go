@@ -0,0 +1,8 @@
+// Package fuzzy provides a gain-adaptive PID controller with gains adjusted online by fuzzy inference.
+//
+// The proportional, integral and derivative gains of a wrapped pid.AntiWindupController are adjusted from a fuzzy
+// rule base over the control error and its derivative, as proposed in Zhao, Tomizuka and Isaka, Fuzzy Gain
+// Scheduling of PID Controllers, 1993 (https://doi.org/10.1109/21.260663). Membership functions, rules and
+// defuzzification are pluggable, and the saturation, anti-windup and derivative filtering of the wrapped
+// controller are reused.
+package fuzzy
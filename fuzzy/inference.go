@@ -0,0 +1,253 @@
+package fuzzy
+
+import (
+	"fmt"
+	"math"
+)
+
+const defaultCentroidResolution = 101
+
+// Rule is a fuzzy rule of the form: IF the error is Error AND the error derivative is ErrorDerivative THEN the
+// adjustment of the proportional gain is ProportionalGain, the adjustment of the integral gain is IntegralGain
+// and the adjustment of the derivative gain is DerivativeGain.
+//
+// The fields are set names of the corresponding variables of the RuleBase. An empty antecedent matches any value,
+// and an empty consequent leaves the adjustment of the gain unaffected by the rule.
+type Rule struct {
+	// Error is the name of the set of the control error.
+	Error string
+	// ErrorDerivative is the name of the set of the control error derivative.
+	ErrorDerivative string
+	// ProportionalGain is the name of the set of the proportional gain adjustment.
+	ProportionalGain string
+	// IntegralGain is the name of the set of the integral gain adjustment.
+	IntegralGain string
+	// DerivativeGain is the name of the set of the derivative gain adjustment.
+	DerivativeGain string
+}
+
+// RuleBase is a fuzzy rule base for adjustments of the gains of a PID controller.
+type RuleBase struct {
+	// Error is the input variable of the control error.
+	Error Variable
+	// ErrorDerivative is the input variable of the filtered control error derivative.
+	ErrorDerivative Variable
+	// ProportionalGain is the output variable of the proportional gain adjustment.
+	ProportionalGain Variable
+	// IntegralGain is the output variable of the integral gain adjustment.
+	IntegralGain Variable
+	// DerivativeGain is the output variable of the derivative gain adjustment.
+	DerivativeGain Variable
+	// Rules of the rule base.
+	Rules []Rule
+	// And is the t-norm that combines the antecedents of a rule, such as math.Min or Product. Defaults to math.Min
+	// when nil.
+	And func(a, b float64) float64
+	// Defuzzifier computes the adjustments from the activations of the output sets. Defaults to Centroid when
+	// nil.
+	Defuzzifier Defuzzifier
+}
+
+// Adjustment is an adjustment of the gains of a PID controller.
+type Adjustment struct {
+	// ProportionalGain is the adjustment of the P part gain.
+	ProportionalGain float64
+	// IntegralGain is the adjustment of the I part gain.
+	IntegralGain float64
+	// DerivativeGain is the adjustment of the D part gain.
+	DerivativeGain float64
+}
+
+// Product is the product t-norm.
+func Product(a, b float64) float64 {
+	return a * b
+}
+
+// Defuzzifier computes a crisp value of a variable from the activation in [0, 1] of each of its sets.
+type Defuzzifier interface {
+	Defuzzify(v Variable, activations []float64) float64
+}
+
+// Centroid is the Mamdani defuzzifier. The membership function of each set is clipped at its activation, the
+// clipped functions are aggregated by maximum, and the centroid of the aggregate over the universe of discourse
+// is computed numerically.
+type Centroid struct {
+	// Resolution is the number of points in the universe of discourse the centroid is computed from. Defaults to
+	// 101 when zero.
+	Resolution int
+}
+
+var _ Defuzzifier = Centroid{}
+
+// Defuzzify implements Defuzzifier.
+func (d Centroid) Defuzzify(v Variable, activations []float64) float64 {
+	n := d.Resolution
+	if n <= 1 {
+		n = defaultCentroidResolution
+	}
+	var moment, area float64
+	for i := range n {
+		x := v.Min + (v.Max-v.Min)*float64(i)/float64(n-1)
+		var degree float64
+		for j, s := range v.Sets {
+			degree = math.Max(degree, math.Min(activations[j], s.Membership.Degree(x)))
+		}
+		moment += x * degree
+		area += degree
+	}
+	if area == 0 {
+		return 0
+	}
+	return moment / area
+}
+
+// WeightedAverage is a defuzzifier that computes the activation-weighted average of the centers of the sets. It
+// is cheaper than Centroid, and equals the output of a zero-order Sugeno system with the centers as singletons.
+type WeightedAverage struct{}
+
+var _ Defuzzifier = WeightedAverage{}
+
+// Defuzzify implements Defuzzifier.
+func (WeightedAverage) Defuzzify(v Variable, activations []float64) float64 {
+	var sum, weights float64
+	for i, s := range v.Sets {
+		sum += activations[i] * s.Membership.Center()
+		weights += activations[i]
+	}
+	if weights == 0 {
+		return 0
+	}
+	return sum / weights
+}
+
+// Validate returns an error when a variable of the rule base is invalid, or when a rule references an unknown
+// set.
+func (r RuleBase) Validate() error {
+	if err := r.Error.validate("error"); err != nil {
+		return fmt.Errorf("fuzzy: invalid rule base: %w", err)
+	}
+	if err := r.ErrorDerivative.validate("error derivative"); err != nil {
+		return fmt.Errorf("fuzzy: invalid rule base: %w", err)
+	}
+	for _, output := range r.outputs() {
+		if len(output.variable.Sets) == 0 {
+			continue
+		}
+		if err := output.variable.validate(output.name); err != nil {
+			return fmt.Errorf("fuzzy: invalid rule base: %w", err)
+		}
+	}
+	for i, rule := range r.Rules {
+		for _, reference := range []struct {
+			name     string
+			set      string
+			variable Variable
+		}{
+			{"error", rule.Error, r.Error},
+			{"error derivative", rule.ErrorDerivative, r.ErrorDerivative},
+			{"proportional gain", rule.ProportionalGain, r.ProportionalGain},
+			{"integral gain", rule.IntegralGain, r.IntegralGain},
+			{"derivative gain", rule.DerivativeGain, r.DerivativeGain},
+		} {
+			if reference.set != "" && reference.variable.index(reference.set) < 0 {
+				return fmt.Errorf("fuzzy: invalid rule base: rule %d: unknown %s set %q", i, reference.name, reference.set)
+			}
+		}
+	}
+	return nil
+}
+
+// Infer the gain adjustments for the control error and control error derivative.
+//
+// The inputs are clamped to the universes of discourse of the input variables. Rules that reference unknown sets
+// do not fire, and a gain adjustment is zero when no rule with a consequent for the gain fires.
+func (r RuleBase) Infer(controlError, controlErrorDerivative float64) Adjustment {
+	adjustment, _ := r.infer(controlError, controlErrorDerivative, nil)
+	return adjustment
+}
+
+// infer the gain adjustments with the membership degrees and activations stored in the buffer, which is grown as
+// needed and returned for reuse.
+func (r RuleBase) infer(controlError, controlErrorDerivative float64, buffer []float64) (Adjustment, []float64) {
+	and := r.And
+	if and == nil {
+		and = math.Min
+	}
+	var defuzzifier Defuzzifier = Centroid{}
+	if r.Defuzzifier != nil {
+		defuzzifier = r.Defuzzifier
+	}
+	outputs := r.outputs()
+	n := len(r.Error.Sets) + len(r.ErrorDerivative.Sets)
+	for _, output := range outputs {
+		n += len(output.variable.Sets)
+	}
+	if cap(buffer) < n {
+		buffer = make([]float64, n)
+	}
+	buffer = buffer[:n]
+	clear(buffer)
+	rest := buffer
+	next := func(size int) []float64 {
+		result := rest[:size:size]
+		rest = rest[size:]
+		return result
+	}
+	errorDegrees := next(len(r.Error.Sets))
+	r.Error.fuzzify(controlError, errorDegrees)
+	derivativeDegrees := next(len(r.ErrorDerivative.Sets))
+	r.ErrorDerivative.fuzzify(controlErrorDerivative, derivativeDegrees)
+	var activations [3][]float64
+	for i, output := range outputs {
+		activations[i] = next(len(output.variable.Sets))
+	}
+	for _, rule := range r.Rules {
+		strength, ok := 1.0, true
+		for _, antecedent := range []struct {
+			set      string
+			variable Variable
+			degrees  []float64
+		}{
+			{rule.Error, r.Error, errorDegrees},
+			{rule.ErrorDerivative, r.ErrorDerivative, derivativeDegrees},
+		} {
+			if antecedent.set == "" {
+				continue
+			}
+			j := antecedent.variable.index(antecedent.set)
+			if j < 0 {
+				ok = false
+				break
+			}
+			strength = and(strength, antecedent.degrees[j])
+		}
+		if !ok || strength == 0 {
+			continue
+		}
+		for i, consequent := range [...]string{rule.ProportionalGain, rule.IntegralGain, rule.DerivativeGain} {
+			if j := outputs[i].variable.index(consequent); consequent != "" && j >= 0 {
+				activations[i][j] = math.Max(activations[i][j], strength)
+			}
+		}
+	}
+	var result [3]float64
+	for i, output := range outputs {
+		if len(output.variable.Sets) > 0 {
+			result[i] = defuzzifier.Defuzzify(output.variable, activations[i])
+		}
+	}
+	return Adjustment{ProportionalGain: result[0], IntegralGain: result[1], DerivativeGain: result[2]}, buffer
+}
+
+type namedVariable struct {
+	name     string
+	variable Variable
+}
+
+func (r RuleBase) outputs() [3]namedVariable {
+	return [...]namedVariable{
+		{"proportional gain", r.ProportionalGain},
+		{"integral gain", r.IntegralGain},
+		{"derivative gain", r.DerivativeGain},
+	}
+}
@@ -0,0 +1,113 @@
+package fuzzy
+
+import (
+	"math"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func newTestRuleBase() RuleBase {
+	return RuleBase{
+		Error:            NewUniformVariable(-1, 1, "N", "Z", "P"),
+		ErrorDerivative:  NewUniformVariable(-1, 1, "N", "Z", "P"),
+		ProportionalGain: NewUniformVariable(0, 1, "S", "L"),
+		IntegralGain:     NewUniformVariable(-1, 0, "L", "S"),
+		Rules: []Rule{
+			{Error: "N", ProportionalGain: "L", IntegralGain: "L"},
+			{Error: "Z", ProportionalGain: "S", IntegralGain: "S"},
+			{Error: "P", ProportionalGain: "L", IntegralGain: "L"},
+		},
+		Defuzzifier: WeightedAverage{},
+	}
+}
+
+func TestRuleBase_Infer(t *testing.T) {
+	r := newTestRuleBase()
+	assert.NilError(t, r.Validate())
+	for _, tt := range []struct {
+		name         string
+		controlError float64
+		expected     Adjustment
+	}{
+		{name: "large negative error", controlError: -1, expected: Adjustment{ProportionalGain: 1, IntegralGain: -1}},
+		{name: "zero error", controlError: 0, expected: Adjustment{}},
+		{name: "half error", controlError: 0.5, expected: Adjustment{ProportionalGain: 0.5, IntegralGain: -0.5}},
+		{name: "clamped error", controlError: 100, expected: Adjustment{ProportionalGain: 1, IntegralGain: -1}},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, r.Infer(tt.controlError, 0))
+		})
+	}
+}
+
+func TestRuleBase_Infer_Centroid(t *testing.T) {
+	// Given a rule base with centroid defuzzification
+	r := newTestRuleBase()
+	r.Defuzzifier = Centroid{Resolution: 10001}
+	// When the zero error rule fires alone
+	adjustment := r.Infer(0, 0)
+	// Then the adjustment should be the centroid of the shoulder of the small set
+	assert.Assert(t, math.Abs(adjustment.ProportionalGain-1.0/3) < 1e-3, adjustment.ProportionalGain)
+	assert.Assert(t, math.Abs(adjustment.IntegralGain+1.0/3) < 1e-3, adjustment.IntegralGain)
+	// And the derivative gain adjustment should be zero without sets
+	assert.Equal(t, 0.0, adjustment.DerivativeGain)
+}
+
+func TestRuleBase_Infer_And(t *testing.T) {
+	// Given a rule over both the error and error derivative
+	r := RuleBase{
+		Error:            NewUniformVariable(-1, 1, "N", "Z", "P"),
+		ErrorDerivative:  NewUniformVariable(-1, 1, "N", "Z", "P"),
+		ProportionalGain: Variable{Min: 0, Max: 1, Sets: []Set{{Name: "L", Membership: Triangular{0, 1, 1}}}},
+		Rules:            []Rule{{Error: "P", ErrorDerivative: "P", ProportionalGain: "L"}},
+		Defuzzifier:      activationDefuzzifier{},
+	}
+	// Then the minimum t-norm should be used by default
+	assert.Equal(t, 0.25, r.Infer(0.5, 0.25).ProportionalGain)
+	// And the product t-norm when configured
+	r.And = Product
+	assert.Equal(t, 0.125, r.Infer(0.5, 0.25).ProportionalGain)
+}
+
+// activationDefuzzifier returns the activation of the first set, to test inference.
+type activationDefuzzifier struct{}
+
+func (activationDefuzzifier) Defuzzify(_ Variable, activations []float64) float64 {
+	return activations[0]
+}
+
+func TestRuleBase_Validate(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		modify   func(*RuleBase)
+		expected string
+	}{
+		{
+			name:     "unknown error set",
+			modify:   func(r *RuleBase) { r.Rules = append(r.Rules, Rule{Error: "X"}) },
+			expected: `fuzzy: invalid rule base: rule 3: unknown error set "X"`,
+		},
+		{
+			name:     "unknown derivative gain set",
+			modify:   func(r *RuleBase) { r.Rules = append(r.Rules, Rule{DerivativeGain: "L"}) },
+			expected: `rule 3: unknown derivative gain set "L"`,
+		},
+		{
+			name:     "duplicate set",
+			modify:   func(r *RuleBase) { r.Error.Sets = append(r.Error.Sets, r.Error.Sets[0]) },
+			expected: `error: duplicate set "N"`,
+		},
+		{
+			name:     "empty universe",
+			modify:   func(r *RuleBase) { r.ErrorDerivative = Variable{} },
+			expected: "error derivative: empty universe [0, 0]",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newTestRuleBase()
+			tt.modify(&r)
+			assert.ErrorContains(t, r.Validate(), tt.expected)
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package fuzzy
+
+import (
+	"fmt"
+	"math"
+)
+
+// MembershipFunction is the membership function of a fuzzy set.
+type MembershipFunction interface {
+	// Degree returns the degree of membership of x in [0, 1].
+	Degree(x float64) float64
+	// Center returns a representative value of the set, used by the WeightedAverage defuzzifier.
+	Center() float64
+}
+
+// Triangular is a triangular membership function, with full membership at Peak and no membership outside of
+// (Left, Right). Left may equal Peak, and Peak may equal Right, for a shoulder with full membership at the edge.
+type Triangular struct {
+	Left, Peak, Right float64
+}
+
+var _ MembershipFunction = Triangular{}
+
+// Degree implements MembershipFunction.
+func (f Triangular) Degree(x float64) float64 {
+	switch {
+	case x == f.Peak:
+		return 1
+	case x <= f.Left || x >= f.Right:
+		return 0
+	case x < f.Peak:
+		return (x - f.Left) / (f.Peak - f.Left)
+	default:
+		return (f.Right - x) / (f.Right - f.Peak)
+	}
+}
+
+// Center implements MembershipFunction.
+func (f Triangular) Center() float64 {
+	return f.Peak
+}
+
+// Trapezoidal is a trapezoidal membership function, with full membership in [B, C] and no membership outside of
+// (A, D).
+type Trapezoidal struct {
+	A, B, C, D float64
+}
+
+var _ MembershipFunction = Trapezoidal{}
+
+// Degree implements MembershipFunction.
+func (f Trapezoidal) Degree(x float64) float64 {
+	switch {
+	case x >= f.B && x <= f.C:
+		return 1
+	case x <= f.A || x >= f.D:
+		return 0
+	case x < f.B:
+		return (x - f.A) / (f.B - f.A)
+	default:
+		return (f.D - x) / (f.D - f.C)
+	}
+}
+
+// Center implements MembershipFunction.
+func (f Trapezoidal) Center() float64 {
+	return (f.B + f.C) / 2
+}
+
+// Gaussian is a Gaussian membership function.
+type Gaussian struct {
+	Mean, StandardDeviation float64
+}
+
+var _ MembershipFunction = Gaussian{}
+
+// Degree implements MembershipFunction.
+func (f Gaussian) Degree(x float64) float64 {
+	z := (x - f.Mean) / f.StandardDeviation
+	return math.Exp(-z * z / 2)
+}
+
+// Center implements MembershipFunction.
+func (f Gaussian) Center() float64 {
+	return f.Mean
+}
+
+// Set is a named fuzzy set.
+type Set struct {
+	// Name of the set, such as "NB" (negative big) or "ZO" (zero), referenced by rules.
+	Name string
+	// Membership is the membership function of the set.
+	Membership MembershipFunction
+}
+
+// Variable is a linguistic variable, with fuzzy sets over a universe of discourse.
+type Variable struct {
+	// Min is the lower bound of the universe of discourse.
+	Min float64
+	// Max is the upper bound of the universe of discourse.
+	Max float64
+	// Sets of the variable.
+	Sets []Set
+}
+
+// NewUniformVariable creates a variable with triangular sets with the names, with peaks evenly spaced over
+// [min, max] and shoulders at the bounds. A typical choice of names is NB, NM, NS, ZO, PS, PM, PB.
+func NewUniformVariable(min, max float64, names ...string) Variable {
+	result := Variable{Min: min, Max: max, Sets: make([]Set, 0, len(names))}
+	if len(names) == 1 {
+		f := Trapezoidal{A: min, B: min, C: max, D: max}
+		result.Sets = append(result.Sets, Set{Name: names[0], Membership: f})
+		return result
+	}
+	step := (max - min) / float64(len(names)-1)
+	for i, name := range names {
+		peak := min + float64(i)*step
+		f := Triangular{Left: peak - step, Peak: peak, Right: peak + step}
+		if i == 0 {
+			f.Left = peak
+		}
+		if i == len(names)-1 {
+			f.Right = peak
+		}
+		result.Sets = append(result.Sets, Set{Name: name, Membership: f})
+	}
+	return result
+}
+
+// index returns the index of the named set, or -1 if there is no such set.
+func (v Variable) index(name string) int {
+	for i, s := range v.Sets {
+		if s.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// fuzzify returns the degree of membership in each set of x clamped to the universe of discourse.
+func (v Variable) fuzzify(x float64, degrees []float64) {
+	x = math.Max(v.Min, math.Min(v.Max, x))
+	for i, s := range v.Sets {
+		degrees[i] = s.Membership.Degree(x)
+	}
+}
+
+func (v Variable) validate(name string) error {
+	if !(v.Min < v.Max) {
+		return fmt.Errorf("%s: empty universe [%v, %v]", name, v.Min, v.Max)
+	}
+	if len(v.Sets) == 0 {
+		return fmt.Errorf("%s: no sets", name)
+	}
+	for i, s := range v.Sets {
+		if s.Membership == nil {
+			return fmt.Errorf("%s: set %q: no membership function", name, s.Name)
+		}
+		if v.index(s.Name) != i {
+			return fmt.Errorf("%s: duplicate set %q", name, s.Name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package fuzzy
+
+import (
+	"math"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestTriangular_Degree(t *testing.T) {
+	f := Triangular{Left: -1, Peak: 0, Right: 2}
+	assert.Equal(t, 0.0, f.Degree(-2))
+	assert.Equal(t, 0.5, f.Degree(-0.5))
+	assert.Equal(t, 1.0, f.Degree(0))
+	assert.Equal(t, 0.5, f.Degree(1))
+	assert.Equal(t, 0.0, f.Degree(2))
+	assert.Equal(t, 0.0, f.Center())
+	// Shoulders have full membership at the edge.
+	assert.Equal(t, 1.0, Triangular{Left: 0, Peak: 0, Right: 1}.Degree(0))
+}
+
+func TestTrapezoidal_Degree(t *testing.T) {
+	f := Trapezoidal{A: 0, B: 1, C: 2, D: 4}
+	assert.Equal(t, 0.0, f.Degree(0))
+	assert.Equal(t, 0.5, f.Degree(0.5))
+	assert.Equal(t, 1.0, f.Degree(1.5))
+	assert.Equal(t, 0.5, f.Degree(3))
+	assert.Equal(t, 0.0, f.Degree(5))
+	assert.Equal(t, 1.5, f.Center())
+}
+
+func TestGaussian_Degree(t *testing.T) {
+	f := Gaussian{Mean: 1, StandardDeviation: 2}
+	assert.Equal(t, 1.0, f.Degree(1))
+	assert.Equal(t, math.Exp(-0.5), f.Degree(3))
+	assert.Equal(t, 1.0, f.Center())
+}
+
+func TestNewUniformVariable(t *testing.T) {
+	// Given a uniform variable with seven sets
+	v := NewUniformVariable(-3, 3, "NB", "NM", "NS", "ZO", "PS", "PM", "PB")
+	assert.Equal(t, 7, len(v.Sets))
+	assert.Equal(t, 0.0, v.Sets[3].Membership.Center())
+	// Then the sets should form a partition of unity over the universe of discourse
+	degrees := make([]float64, len(v.Sets))
+	for x := -4.0; x <= 4; x += 0.1 {
+		v.fuzzify(x, degrees)
+		var sum float64
+		for _, degree := range degrees {
+			sum += degree
+		}
+		assert.Assert(t, math.Abs(sum-1) < 1e-12, x)
+	}
+	// And a single set should have full membership everywhere
+	single := NewUniformVariable(0, 1, "ANY")
+	single.fuzzify(0.5, degrees[:1])
+	assert.Equal(t, 1.0, degrees[0])
+}
@@ -2,6 +2,10 @@ module go.einride.tech/pid
 
 go 1.24
 
-require gotest.tools/v3 v3.5.2
+require (
+	go.yaml.in/yaml/v3 v3.0.4
+	google.golang.org/protobuf v1.36.11
+	gotest.tools/v3 v3.5.2
+)
 
 require github.com/google/go-cmp v0.7.0 // indirect
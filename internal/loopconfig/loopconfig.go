@@ -0,0 +1,215 @@
+// Package loopconfig provides the JSON and YAML config file format of a control loop shared by the commands.
+package loopconfig
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"go.einride.tech/pid"
+	"go.einride.tech/pid/model"
+	"go.einride.tech/pid/sim"
+	"go.yaml.in/yaml/v3"
+)
+
+// Config is the config file format of a control loop and its simulation.
+type Config struct {
+	// Controller is the controller to simulate.
+	Controller Controller `json:"controller" yaml:"controller"`
+	// Plant is the process model to control.
+	Plant Plant `json:"plant" yaml:"plant"`
+	// SamplingInterval is the sampling interval of the controller.
+	SamplingInterval Duration `json:"samplingInterval" yaml:"samplingInterval"`
+	// Duration of the simulation.
+	Duration Duration `json:"duration" yaml:"duration"`
+	// Reference holds the reference signal steps. The reference signal is zero before the first step.
+	Reference []Step `json:"reference" yaml:"reference"`
+	// Disturbance holds the steps of the load disturbance at the process input. The disturbance is zero before
+	// the first step.
+	Disturbance []Step `json:"disturbance" yaml:"disturbance"`
+	// FeedForward holds the feed-forward signal steps. The feed-forward signal is zero before the first step.
+	FeedForward []Step `json:"feedForward" yaml:"feedForward"`
+	// MeasurementNoise is the standard deviation of white Gaussian noise added to the measured actual signal.
+	MeasurementNoise float64 `json:"measurementNoise" yaml:"measurementNoise"`
+	// Seed of the measurement noise generator.
+	Seed uint64 `json:"seed" yaml:"seed"`
+}
+
+// Controller is the config of a controller.
+type Controller struct {
+	// Type is the controller type, "antiWindup" (default) or "pid".
+	Type string `json:"type" yaml:"type"`
+	// ProportionalGain is the P part gain.
+	ProportionalGain float64 `json:"proportionalGain" yaml:"proportionalGain"`
+	// IntegralGain is the I part gain.
+	IntegralGain float64 `json:"integralGain" yaml:"integralGain"`
+	// DerivativeGain is the D part gain.
+	DerivativeGain float64 `json:"derivativeGain" yaml:"derivativeGain"`
+	// AntiWindUpGain is the anti-windup tracking gain.
+	AntiWindUpGain float64 `json:"antiWindUpGain" yaml:"antiWindUpGain"`
+	// IntegralDischargeTimeConstant is the time constant to discharge the integral state of the PID controller (s).
+	IntegralDischargeTimeConstant float64 `json:"integralDischargeTimeConstant" yaml:"integralDischargeTimeConstant"`
+	// LowPassTimeConstant is the D part low-pass filter time constant => cut-off frequency 1/LowPassTimeConstant.
+	LowPassTimeConstant Duration `json:"lowPassTimeConstant" yaml:"lowPassTimeConstant"`
+	// MaxOutput is the max output from the PID.
+	MaxOutput float64 `json:"maxOutput" yaml:"maxOutput"`
+	// MinOutput is the min output from the PID.
+	MinOutput float64 `json:"minOutput" yaml:"minOutput"`
+}
+
+// Plant is the config of a process model. Exactly one of the models must be set.
+type Plant struct {
+	// FOPDT is a first-order plus dead time model.
+	FOPDT *FOPDT `json:"fopdt" yaml:"fopdt"`
+	// TransferFunction is a rational transfer function model with dead time.
+	TransferFunction *TransferFunction `json:"transferFunction" yaml:"transferFunction"`
+}
+
+// FOPDT is the config of a model.FOPDT.
+type FOPDT struct {
+	// Gain is the static gain K.
+	Gain float64 `json:"gain" yaml:"gain"`
+	// TimeConstant is the time constant T.
+	TimeConstant Duration `json:"timeConstant" yaml:"timeConstant"`
+	// DeadTime is the dead time L.
+	DeadTime Duration `json:"deadTime" yaml:"deadTime"`
+}
+
+// TransferFunction is the config of a model.TransferFunction.
+type TransferFunction struct {
+	// Numerator holds the numerator coefficients in descending powers of s.
+	Numerator []float64 `json:"numerator" yaml:"numerator"`
+	// Denominator holds the denominator coefficients in descending powers of s.
+	Denominator []float64 `json:"denominator" yaml:"denominator"`
+	// DeadTime is the dead time L of the transfer function.
+	DeadTime Duration `json:"deadTime" yaml:"deadTime"`
+}
+
+// Step is the config of a sim.Step.
+type Step struct {
+	// Time of the step.
+	Time Duration `json:"time" yaml:"time"`
+	// Value of the signal from the time of the step.
+	Value float64 `json:"value" yaml:"value"`
+}
+
+// Duration is a time.Duration in the format of time.ParseDuration, such as "1.5s".
+type Duration time.Duration
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (d *Duration) UnmarshalText(text []byte) error {
+	parsed, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	*d = Duration(parsed)
+	return nil
+}
+
+// Read reads a config file, as JSON if the file has a .json extension and as YAML otherwise.
+func Read(name string) (Config, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return Config{}, err
+	}
+	var result Config
+	if filepath.Ext(name) == ".json" {
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		err = decoder.Decode(&result)
+	} else {
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		err = decoder.Decode(&result)
+	}
+	if err != nil {
+		return Config{}, fmt.Errorf("parse %s: %w", name, err)
+	}
+	return result, nil
+}
+
+// SimConfig converts the config to a simulation config.
+func (c Config) SimConfig() (sim.Config, error) {
+	controller, err := c.Controller.SimController()
+	if err != nil {
+		return sim.Config{}, err
+	}
+	plant, err := c.Plant.Model()
+	if err != nil {
+		return sim.Config{}, err
+	}
+	return sim.Config{
+		Controller:       controller,
+		Plant:            plant,
+		SamplingInterval: time.Duration(c.SamplingInterval),
+		Duration:         time.Duration(c.Duration),
+		Reference:        simSteps(c.Reference),
+		Disturbance:      simSteps(c.Disturbance),
+		FeedForward:      simSteps(c.FeedForward),
+		MeasurementNoise: c.MeasurementNoise,
+		Seed:             c.Seed,
+	}, nil
+}
+
+// SimController returns the controller for simulation.
+func (c Controller) SimController() (sim.Controller, error) {
+	switch c.Type {
+	case "", "antiWindup":
+		return sim.NewAntiWindupController(c.AntiWindupControllerConfig()), nil
+	case "pid":
+		return sim.NewController(pid.ControllerConfig{
+			ProportionalGain: c.ProportionalGain,
+			IntegralGain:     c.IntegralGain,
+			DerivativeGain:   c.DerivativeGain,
+		}), nil
+	default:
+		return nil, fmt.Errorf("unknown controller type %q", c.Type)
+	}
+}
+
+// AntiWindupControllerConfig returns the controller config as a pid.AntiWindupControllerConfig.
+func (c Controller) AntiWindupControllerConfig() pid.AntiWindupControllerConfig {
+	return pid.AntiWindupControllerConfig{
+		ProportionalGain:              c.ProportionalGain,
+		IntegralGain:                  c.IntegralGain,
+		DerivativeGain:                c.DerivativeGain,
+		AntiWindUpGain:                c.AntiWindUpGain,
+		IntegralDischargeTimeConstant: c.IntegralDischargeTimeConstant,
+		LowPassTimeConstant:           time.Duration(c.LowPassTimeConstant),
+		MaxOutput:                     c.MaxOutput,
+		MinOutput:                     c.MinOutput,
+	}
+}
+
+// Model returns the process model.
+func (c Plant) Model() (model.Model, error) {
+	switch {
+	case c.FOPDT != nil && c.TransferFunction != nil:
+		return nil, fmt.Errorf("plant: both fopdt and transferFunction specified")
+	case c.FOPDT != nil:
+		return model.FOPDT{
+			Gain:         c.FOPDT.Gain,
+			TimeConstant: time.Duration(c.FOPDT.TimeConstant),
+			DeadTime:     time.Duration(c.FOPDT.DeadTime),
+		}, nil
+	case c.TransferFunction != nil:
+		return model.TransferFunction{
+			Numerator:   c.TransferFunction.Numerator,
+			Denominator: c.TransferFunction.Denominator,
+			DeadTime:    time.Duration(c.TransferFunction.DeadTime),
+		}, nil
+	default:
+		return nil, fmt.Errorf("plant: one of fopdt or transferFunction required")
+	}
+}
+
+func simSteps(steps []Step) []sim.Step {
+	result := make([]sim.Step, 0, len(steps))
+	for _, s := range steps {
+		result = append(result, sim.Step{Time: time.Duration(s.Time), Value: s.Value})
+	}
+	return result
+}
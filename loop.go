@@ -0,0 +1,121 @@
+package pid
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Loop runs a controller at a fixed period.
+//
+// Each iteration of the loop reads a measurement of type M from the sensor, updates the controller with the
+// measurement and the sampling interval measured on the Clock, and writes the resulting control signal to the
+// actuator.
+//
+// Iterations are scheduled on a fixed grid of deadlines, so that jitter does not accumulate into drift. An
+// iteration that does not complete before the start of the next period is an overrun, and the periods that were
+// skipped because of it are missed deadlines.
+type Loop[M any] struct {
+	// Period is the nominal period of the loop.
+	Period time.Duration
+	// Clock is the time base of the loop. Defaults to SystemClock when nil.
+	Clock Clock
+	// ReadSensor reads the measurement at the start of an iteration.
+	ReadSensor func(ctx context.Context) (M, error)
+	// Update updates the controller with the measurement and the time interval elapsed since the previous
+	// iteration, and returns the resulting control signal.
+	Update func(measurement M, samplingInterval time.Duration) float64
+	// WriteActuator writes the control signal to the actuator at the end of an iteration.
+	WriteActuator func(ctx context.Context, controlSignal float64) error
+
+	mu    sync.Mutex
+	stats LoopStats
+}
+
+// LoopStats holds timing statistics for a Loop.
+type LoopStats struct {
+	// Iterations is the number of completed iterations.
+	Iterations int
+	// Overruns is the number of iterations that did not complete before the start of the next period.
+	Overruns int
+	// MissedDeadlines is the number of periods that were skipped because of overruns.
+	MissedDeadlines int
+	// Jitter is the delay between the scheduled and the actual start of the latest iteration.
+	Jitter time.Duration
+	// MaxJitter is the largest Jitter observed.
+	MaxJitter time.Duration
+	// ExecutionTime is the time spent executing the latest iteration.
+	ExecutionTime time.Duration
+	// MaxExecutionTime is the largest ExecutionTime observed.
+	MaxExecutionTime time.Duration
+	// SamplingInterval is the sampling interval passed to the controller in the latest iteration.
+	SamplingInterval time.Duration
+}
+
+// Stats returns a snapshot of the loop timing statistics. It is safe to call concurrently with Run.
+func (l *Loop[M]) Stats() LoopStats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.stats
+}
+
+// Run the loop until the context is canceled or an iteration fails.
+//
+// The first iteration runs one period after Run is called, and uses the period as sampling interval.
+func (l *Loop[M]) Run(ctx context.Context) error {
+	if l.Period <= 0 {
+		return fmt.Errorf("pid: run loop: non-positive period %v", l.Period)
+	}
+	clock := l.Clock
+	if clock == nil {
+		clock = SystemClock{}
+	}
+	previous := clock.Now()
+	deadline := previous.Add(l.Period)
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-clock.After(deadline.Sub(clock.Now())):
+		}
+		start := clock.Now()
+		samplingInterval := start.Sub(previous)
+		previous = start
+		measurement, err := l.ReadSensor(ctx)
+		if err != nil {
+			return fmt.Errorf("pid: run loop: read sensor: %w", err)
+		}
+		controlSignal := l.Update(measurement, samplingInterval)
+		if err := l.WriteActuator(ctx, controlSignal); err != nil {
+			return fmt.Errorf("pid: run loop: write actuator: %w", err)
+		}
+		end := clock.Now()
+		next := deadline.Add(l.Period)
+		var missed int
+		if end.After(next) {
+			missed = int(end.Sub(next)/l.Period) + 1
+			next = next.Add(time.Duration(missed) * l.Period)
+		}
+		l.record(start.Sub(deadline), end.Sub(start), samplingInterval, missed)
+		deadline = next
+	}
+}
+
+func (l *Loop[M]) record(jitter, executionTime, samplingInterval time.Duration, missed int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.stats.Iterations++
+	if missed > 0 {
+		l.stats.Overruns++
+		l.stats.MissedDeadlines += missed
+	}
+	l.stats.Jitter = jitter
+	l.stats.MaxJitter = max(l.stats.MaxJitter, jitter)
+	l.stats.ExecutionTime = executionTime
+	l.stats.MaxExecutionTime = max(l.stats.MaxExecutionTime, executionTime)
+	l.stats.SamplingInterval = samplingInterval
+}
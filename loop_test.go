@@ -0,0 +1,171 @@
+package pid
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestLoop_Run(t *testing.T) {
+	// Given a loop running a P controller against an integrating plant
+	c := &AntiWindupController{
+		Config: AntiWindupControllerConfig{
+			LowPassTimeConstant: 1 * time.Second,
+			ProportionalGain:    10,
+			MinOutput:           -10,
+			MaxOutput:           10,
+		},
+	}
+	var plant float64
+	var actuated []float64
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	loop := Loop[float64]{
+		Period: time.Millisecond,
+		ReadSensor: func(context.Context) (float64, error) {
+			return plant, nil
+		},
+		Update: func(measurement float64, samplingInterval time.Duration) float64 {
+			c.Update(AntiWindupControllerInput{
+				ReferenceSignal:  1,
+				ActualSignal:     measurement,
+				SamplingInterval: samplingInterval,
+			})
+			return c.State.ControlSignal
+		},
+		WriteActuator: func(_ context.Context, controlSignal float64) error {
+			actuated = append(actuated, controlSignal)
+			plant += controlSignal * time.Millisecond.Seconds()
+			if len(actuated) == 10 {
+				cancel()
+			}
+			return nil
+		},
+	}
+	// When running the loop until canceled
+	err := loop.Run(ctx)
+	// Then
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, 10, len(actuated))
+	assert.Equal(t, 10.0, actuated[0])
+	stats := loop.Stats()
+	assert.Equal(t, 10, stats.Iterations)
+	assert.Assert(t, stats.SamplingInterval > 0)
+	assert.Assert(t, stats.MaxJitter >= stats.Jitter)
+	assert.Assert(t, stats.MaxExecutionTime >= stats.ExecutionTime)
+}
+
+func TestLoop_Overrun(t *testing.T) {
+	// Given a loop whose second iteration takes several periods to execute
+	const period = 5 * time.Millisecond
+	var iterations int
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	loop := Loop[struct{}]{
+		Period: period,
+		ReadSensor: func(context.Context) (struct{}, error) {
+			return struct{}{}, nil
+		},
+		Update: func(struct{}, time.Duration) float64 {
+			iterations++
+			if iterations == 2 {
+				time.Sleep(3*period + period/2)
+			}
+			return 0
+		},
+		WriteActuator: func(context.Context, float64) error {
+			if iterations == 3 {
+				cancel()
+			}
+			return nil
+		},
+	}
+	// When
+	assert.ErrorIs(t, loop.Run(ctx), context.Canceled)
+	// Then the overrun and the skipped periods should be recorded
+	stats := loop.Stats()
+	assert.Equal(t, 3, stats.Iterations)
+	assert.Equal(t, 1, stats.Overruns)
+	assert.Assert(t, stats.MissedDeadlines >= 3)
+	assert.Assert(t, stats.MaxExecutionTime >= 3*period)
+}
+
+func TestLoop_Errors(t *testing.T) {
+	errSensor := errors.New("sensor failure")
+	loop := Loop[float64]{
+		Period: time.Millisecond,
+		ReadSensor: func(context.Context) (float64, error) {
+			return 0, errSensor
+		},
+	}
+	assert.ErrorIs(t, loop.Run(context.Background()), errSensor)
+	loop.Period = 0
+	assert.ErrorContains(t, loop.Run(context.Background()), "non-positive period")
+}
+
+func TestLoop_FakeClock(t *testing.T) {
+	// Given a loop on a fake clock
+	const period = 10 * time.Millisecond
+	clock := NewFakeClock(time.Unix(0, 0))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var samplingIntervals []time.Duration
+	loop := Loop[float64]{
+		Period: period,
+		Clock:  clock,
+		ReadSensor: func(context.Context) (float64, error) {
+			return 0, nil
+		},
+		Update: func(_ float64, samplingInterval time.Duration) float64 {
+			samplingIntervals = append(samplingIntervals, samplingInterval)
+			if len(samplingIntervals) == 3 {
+				// Simulate an execution time of two and a half periods
+				clock.Advance(2*period + period/2)
+			}
+			return 0
+		},
+		WriteActuator: func(context.Context, float64) error {
+			return nil
+		},
+	}
+	done := make(chan error)
+	go func() {
+		done <- loop.Run(ctx)
+	}()
+	// When the first iteration starts on time
+	clock.BlockUntilWaiters(1)
+	clock.Advance(period)
+	// And the second iteration starts with 2ms of jitter
+	clock.BlockUntilWaiters(1)
+	clock.Advance(period + 2*time.Millisecond)
+	// And the third iteration starts on time and overruns
+	clock.BlockUntilWaiters(1)
+	clock.Advance(period - 2*time.Millisecond)
+	// And the fourth iteration starts on the next period boundary after the overrun
+	clock.BlockUntilWaiters(1)
+	clock.Advance(period / 2)
+	clock.BlockUntilWaiters(1)
+	cancel()
+	assert.ErrorIs(t, <-done, context.Canceled)
+	// Then the sampling intervals should include the jitter and the gap
+	assert.DeepEqual(t, []time.Duration{
+		period,
+		period + 2*time.Millisecond,
+		period - 2*time.Millisecond,
+		3 * period,
+	}, samplingIntervals)
+	// And the statistics should be exact
+	assert.Equal(t, LoopStats{
+		Iterations:       4,
+		Overruns:         1,
+		MissedDeadlines:  2,
+		Jitter:           0,
+		MaxJitter:        2 * time.Millisecond,
+		ExecutionTime:    0,
+		MaxExecutionTime: 2*period + period/2,
+		SamplingInterval: 3 * period,
+	}, loop.Stats())
+}
@@ -0,0 +1,5 @@
+// Package model provides linear process models for simulation and analysis of control loops.
+//
+// Models are continuous-time transfer functions with an optional dead time. A Simulation integrates a model in
+// time for a piecewise constant input, and FrequencyResponse evaluates a model on the imaginary axis.
+package model
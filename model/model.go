@@ -0,0 +1,157 @@
+package model
+
+import (
+	"fmt"
+	"math"
+	"math/cmplx"
+	"time"
+)
+
+// Model is a linear process model.
+type Model interface {
+	// TransferFunction returns the transfer function of the model.
+	TransferFunction() TransferFunction
+}
+
+// TransferFunction is a continuous-time transfer function with dead time,
+//
+//	G(s) = (b0 s^m + ... + bm) / (a0 s^n + ... + an) e^(-sL).
+type TransferFunction struct {
+	// Numerator holds the numerator coefficients in descending powers of s.
+	Numerator []float64
+	// Denominator holds the denominator coefficients in descending powers of s.
+	Denominator []float64
+	// DeadTime is the dead time L of the transfer function.
+	DeadTime time.Duration
+}
+
+var _ Model = TransferFunction{}
+
+// TransferFunction implements Model.
+func (g TransferFunction) TransferFunction() TransferFunction {
+	return g
+}
+
+// Validate returns an error if the transfer function is not proper, or has invalid coefficients.
+func (g TransferFunction) Validate() error {
+	numerator, denominator := trimLeadingZeros(g.Numerator), trimLeadingZeros(g.Denominator)
+	switch {
+	case len(denominator) == 0:
+		return fmt.Errorf("model: validate transfer function: zero denominator")
+	case len(numerator) > len(denominator):
+		return fmt.Errorf("model: validate transfer function: improper transfer function")
+	case g.DeadTime < 0:
+		return fmt.Errorf("model: validate transfer function: negative dead time %v", g.DeadTime)
+	}
+	for _, coefficient := range append(numerator, denominator...) {
+		if math.IsNaN(coefficient) || math.IsInf(coefficient, 0) {
+			return fmt.Errorf("model: validate transfer function: invalid coefficient %v", coefficient)
+		}
+	}
+	return nil
+}
+
+// Order returns the order of the transfer function, which is the degree of the denominator.
+func (g TransferFunction) Order() int {
+	return max(0, len(trimLeadingZeros(g.Denominator))-1)
+}
+
+// StaticGain returns the static gain G(0) of the transfer function.
+func (g TransferFunction) StaticGain() float64 {
+	if len(g.Numerator) == 0 || len(g.Denominator) == 0 {
+		return 0
+	}
+	return g.Numerator[len(g.Numerator)-1] / g.Denominator[len(g.Denominator)-1]
+}
+
+// FrequencyResponse returns G(jω) for the angular frequency ω (rad/s).
+func (g TransferFunction) FrequencyResponse(omega float64) complex128 {
+	s := complex(0, omega)
+	return evaluatePolynomial(g.Numerator, s) / evaluatePolynomial(g.Denominator, s) *
+		cmplx.Exp(-s*complex(g.DeadTime.Seconds(), 0))
+}
+
+// Series returns the transfer function of g in series with h, G(s) H(s), with the sum of the dead times.
+func (g TransferFunction) Series(h TransferFunction) TransferFunction {
+	return TransferFunction{
+		Numerator:   multiplyPolynomials(g.Numerator, h.Numerator),
+		Denominator: multiplyPolynomials(g.Denominator, h.Denominator),
+		DeadTime:    g.DeadTime + h.DeadTime,
+	}
+}
+
+// LowPassFilter returns the binomial low-pass filter of the order and time constant, with unit static gain,
+//
+//	Q(s) = 1 / (T s + 1)^n.
+func LowPassFilter(order int, timeConstant time.Duration) TransferFunction {
+	denominator := []float64{1}
+	for range order {
+		denominator = multiplyPolynomials(denominator, []float64{timeConstant.Seconds(), 1})
+	}
+	return TransferFunction{Numerator: []float64{1}, Denominator: denominator}
+}
+
+// FOPDT is a first-order plus dead time process model,
+//
+//	G(s) = K / (T s + 1) e^(-sL).
+type FOPDT struct {
+	// Gain is the static gain K.
+	Gain float64
+	// TimeConstant is the time constant T.
+	TimeConstant time.Duration
+	// DeadTime is the dead time L.
+	DeadTime time.Duration
+}
+
+var _ Model = FOPDT{}
+
+// TransferFunction implements Model.
+func (m FOPDT) TransferFunction() TransferFunction {
+	return TransferFunction{
+		Numerator:   []float64{m.Gain},
+		Denominator: []float64{m.TimeConstant.Seconds(), 1},
+		DeadTime:    m.DeadTime,
+	}
+}
+
+// FrequencyResponse returns G(jω) for the angular frequency ω (rad/s).
+func (m FOPDT) FrequencyResponse(omega float64) complex128 {
+	return m.TransferFunction().FrequencyResponse(omega)
+}
+
+// NormalizedDeadTime returns the ratio L / (L + T), which is close to 0 for lag-dominant processes and close to
+// 1 for delay-dominant processes.
+func (m FOPDT) NormalizedDeadTime() float64 {
+	if m.DeadTime+m.TimeConstant == 0 {
+		return 0
+	}
+	return m.DeadTime.Seconds() / (m.DeadTime + m.TimeConstant).Seconds()
+}
+
+func evaluatePolynomial(coefficients []float64, x complex128) complex128 {
+	var result complex128
+	for _, coefficient := range coefficients {
+		result = result*x + complex(coefficient, 0)
+	}
+	return result
+}
+
+func multiplyPolynomials(a, b []float64) []float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return nil
+	}
+	result := make([]float64, len(a)+len(b)-1)
+	for i, ai := range a {
+		for j, bj := range b {
+			result[i+j] += ai * bj
+		}
+	}
+	return result
+}
+
+func trimLeadingZeros(coefficients []float64) []float64 {
+	for len(coefficients) > 0 && coefficients[0] == 0 {
+		coefficients = coefficients[1:]
+	}
+	return coefficients
+}
@@ -0,0 +1,101 @@
+package model
+
+import (
+	"math"
+	"math/cmplx"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestTransferFunction_Validate(t *testing.T) {
+	for _, tt := range []struct {
+		name          string
+		g             TransferFunction
+		expectedError string
+	}{
+		{name: "valid", g: TransferFunction{Numerator: []float64{1}, Denominator: []float64{1, 1}}},
+		{
+			name: "leading zeros",
+			g:    TransferFunction{Numerator: []float64{0, 0, 1}, Denominator: []float64{0, 1, 1}},
+		},
+		{
+			name:          "zero denominator",
+			g:             TransferFunction{Numerator: []float64{1}, Denominator: []float64{0}},
+			expectedError: "zero denominator",
+		},
+		{
+			name:          "improper",
+			g:             TransferFunction{Numerator: []float64{1, 0}, Denominator: []float64{1}},
+			expectedError: "improper",
+		},
+		{
+			name: "negative dead time",
+			g: TransferFunction{
+				Numerator: []float64{1}, Denominator: []float64{1}, DeadTime: -time.Second,
+			},
+			expectedError: "negative dead time",
+		},
+		{
+			name:          "nan",
+			g:             TransferFunction{Numerator: []float64{math.NaN()}, Denominator: []float64{1}},
+			expectedError: "invalid coefficient",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.g.Validate()
+			if tt.expectedError == "" {
+				assert.NilError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tt.expectedError)
+			}
+		})
+	}
+}
+
+func TestFOPDT_FrequencyResponse(t *testing.T) {
+	// Given a FOPDT model
+	m := FOPDT{Gain: 2, TimeConstant: 5 * time.Second, DeadTime: time.Second}
+	for _, omega := range []float64{0, 0.1, 1, 10} {
+		// When evaluating the frequency response
+		g := m.FrequencyResponse(omega)
+		// Then the magnitude and phase should match the analytic expressions
+		assert.Assert(t, math.Abs(cmplx.Abs(g)-2/math.Hypot(1, 5*omega)) < 1e-12)
+		expectedPhase := -math.Atan(5*omega) - omega
+		assert.Assert(t, math.Abs(math.Remainder(cmplx.Phase(g)-expectedPhase, 2*math.Pi)) < 1e-12)
+	}
+	assert.Equal(t, 2.0, m.TransferFunction().StaticGain())
+	assert.Equal(t, 1.0/6, m.NormalizedDeadTime())
+}
+
+func TestTransferFunction_Order(t *testing.T) {
+	assert.Equal(t, 2, TransferFunction{Denominator: []float64{0, 1, 2, 1}}.Order())
+	assert.Equal(t, 0, TransferFunction{}.Order())
+}
+
+func TestTransferFunction_Series(t *testing.T) {
+	// Given a FOPDT model and a first-order filter
+	g := FOPDT{Gain: 2, TimeConstant: 3 * time.Second, DeadTime: time.Second}.TransferFunction()
+	h := TransferFunction{Numerator: []float64{1, 1}, Denominator: []float64{0.5, 1}, DeadTime: time.Second}
+	// When connecting them in series
+	series := g.Series(h)
+	// Then the coefficients should be multiplied and the dead times added
+	assert.DeepEqual(t, []float64{2, 2}, series.Numerator)
+	assert.DeepEqual(t, []float64{1.5, 3.5, 1}, series.Denominator)
+	assert.Equal(t, 2*time.Second, series.DeadTime)
+	// And the frequency response should be the product of the frequency responses
+	for _, omega := range []float64{0.1, 1, 10} {
+		expected := g.FrequencyResponse(omega) * h.FrequencyResponse(omega)
+		assert.Assert(t, cmplx.Abs(series.FrequencyResponse(omega)-expected) < 1e-12)
+	}
+}
+
+func TestLowPassFilter(t *testing.T) {
+	q := LowPassFilter(3, 100*time.Millisecond)
+	assert.NilError(t, q.Validate())
+	assert.Equal(t, 3, q.Order())
+	assert.Equal(t, 1.0, q.StaticGain())
+	// The gain at the cut-off frequency is 1/sqrt(2) for each order.
+	assert.Assert(t, math.Abs(cmplx.Abs(q.FrequencyResponse(10))-math.Pow(0.5, 1.5)) < 1e-12)
+}
@@ -0,0 +1,185 @@
+package model
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// Simulation simulates the time response of a Model to a piecewise constant input.
+//
+// The model is realized in controllable canonical form and integrated with the classical fourth-order
+// Runge-Kutta method, with an integration step small enough for the fastest poles of the model. The dead time
+// is simulated exactly by delaying the input.
+type Simulation struct {
+	// a holds the normalized denominator coefficients a1...an.
+	a []float64
+	// c holds the output coefficients of the states.
+	c []float64
+	// d is the direct feedthrough of the input.
+	d float64
+	// maxStep is the max integration step (s).
+	maxStep float64
+	// deadTime of the model.
+	deadTime time.Duration
+	// x holds the states.
+	x []float64
+	// k1...k4 and tmp are scratch buffers for the integration.
+	k1, k2, k3, k4, tmp []float64
+	// inputs holds the inputs within the dead time, oldest first.
+	inputs []timedInput
+	// time is the current simulation time.
+	time time.Duration
+	// output is the current output.
+	output float64
+}
+
+type timedInput struct {
+	time  time.Duration
+	value float64
+}
+
+// NewSimulation creates a new Simulation of the model, starting at rest.
+func NewSimulation(m Model) (*Simulation, error) {
+	g := m.TransferFunction()
+	if err := g.Validate(); err != nil {
+		return nil, fmt.Errorf("model: new simulation: %w", err)
+	}
+	denominator := trimLeadingZeros(g.Denominator)
+	n := len(denominator) - 1
+	// Normalize the denominator to a monic polynomial, and pad the numerator to the same length.
+	a := make([]float64, n)
+	for i := range a {
+		a[i] = denominator[i+1] / denominator[0]
+	}
+	b := make([]float64, n+1)
+	numerator := trimLeadingZeros(g.Numerator)
+	copy(b[n+1-len(numerator):], numerator)
+	for i := range b {
+		b[i] /= denominator[0]
+	}
+	// In controllable canonical form with x[n-1] as the highest derivative, the output is the sum of
+	// (b[k] - a[k] b[0]) times the (n-k)th derivative.
+	c := make([]float64, n)
+	for k := 1; k <= n; k++ {
+		c[n-k] = b[k] - a[k-1]*b[0]
+	}
+	// The Cauchy bound bounds the magnitude of the poles, keep the integration step an order of magnitude
+	// shorter than the fastest time constant for accuracy.
+	var bound float64
+	for _, ai := range a {
+		bound = math.Max(bound, math.Abs(ai))
+	}
+	return &Simulation{
+		a:        a,
+		c:        c,
+		d:        b[0],
+		maxStep:  0.1 / (1 + bound),
+		deadTime: g.DeadTime,
+		x:        make([]float64, n),
+		k1:       make([]float64, n),
+		k2:       make([]float64, n),
+		k3:       make([]float64, n),
+		k4:       make([]float64, n),
+		tmp:      make([]float64, n),
+	}, nil
+}
+
+// Time returns the current simulation time.
+func (s *Simulation) Time() time.Duration {
+	return s.time
+}
+
+// Output returns the current output of the model.
+func (s *Simulation) Output() float64 {
+	return s.output
+}
+
+// Reset the simulation to rest at time zero.
+func (s *Simulation) Reset() {
+	clear(s.x)
+	s.inputs = s.inputs[:0]
+	s.time = 0
+	s.output = 0
+}
+
+// Update applies the input for the duration dt, and returns the output at the end of the interval.
+func (s *Simulation) Update(input float64, dt time.Duration) float64 {
+	if dt <= 0 {
+		return s.output
+	}
+	s.inputs = append(s.inputs, timedInput{time: s.time, value: input})
+	end := s.time + dt
+	// Integrate piecewise between the times where the delayed input changes.
+	t := s.time
+	for t < end {
+		next := end
+		for _, in := range s.inputs {
+			if changeTime := in.time + s.deadTime; changeTime > t && changeTime < next {
+				next = changeTime
+			}
+		}
+		s.integrate(s.delayedInput(t), (next - t).Seconds())
+		t = next
+	}
+	s.time = end
+	// Drop inputs that no longer affect the delayed input.
+	for len(s.inputs) > 1 && s.inputs[1].time+s.deadTime <= s.time {
+		s.inputs = s.inputs[1:]
+	}
+	s.output = s.d * s.delayedInput(s.time)
+	for i, ci := range s.c {
+		s.output += ci * s.x[i]
+	}
+	return s.output
+}
+
+// delayedInput returns the input applied at time t - L, which is zero before the first input.
+func (s *Simulation) delayedInput(t time.Duration) float64 {
+	var result float64
+	for _, in := range s.inputs {
+		if in.time+s.deadTime > t {
+			break
+		}
+		result = in.value
+	}
+	return result
+}
+
+// integrate the states for the duration h (s) with a constant input u.
+func (s *Simulation) integrate(u, h float64) {
+	if len(s.x) == 0 {
+		return
+	}
+	steps := math.Ceil(h / s.maxStep)
+	dt := h / steps
+	for range int(steps) {
+		s.derivative(s.k1, s.x, u)
+		s.axpy(s.tmp, s.x, s.k1, dt/2)
+		s.derivative(s.k2, s.tmp, u)
+		s.axpy(s.tmp, s.x, s.k2, dt/2)
+		s.derivative(s.k3, s.tmp, u)
+		s.axpy(s.tmp, s.x, s.k3, dt)
+		s.derivative(s.k4, s.tmp, u)
+		for i := range s.x {
+			s.x[i] += dt / 6 * (s.k1[i] + 2*s.k2[i] + 2*s.k3[i] + s.k4[i])
+		}
+	}
+}
+
+// derivative computes the state derivative dx of the states x for the input u.
+func (s *Simulation) derivative(dx, x []float64, u float64) {
+	n := len(x)
+	copy(dx, x[1:])
+	dx[n-1] = u
+	for k, ak := range s.a {
+		dx[n-1] -= ak * x[n-1-k]
+	}
+}
+
+// axpy computes result = x + alpha*y.
+func (s *Simulation) axpy(result, x, y []float64, alpha float64) {
+	for i := range result {
+		result[i] = x[i] + alpha*y[i]
+	}
+}
@@ -0,0 +1,94 @@
+package model
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestSimulation_FOPDTStepResponse(t *testing.T) {
+	// Given a simulation of a FOPDT model
+	m := FOPDT{Gain: 2, TimeConstant: 5 * time.Second, DeadTime: 1500 * time.Millisecond}
+	s, err := NewSimulation(m)
+	assert.NilError(t, err)
+	const dt = 100 * time.Millisecond
+	for i := 1; i <= 300; i++ {
+		// When applying a unit step input
+		y := s.Update(1, dt)
+		// Then the output should match the analytic step response
+		tt := (time.Duration(i) * dt).Seconds()
+		var expected float64
+		if tt > 1.5 {
+			expected = 2 * (1 - math.Exp(-(tt-1.5)/5))
+		}
+		assert.Assert(t, math.Abs(y-expected) < 1e-6, "t=%v: %v != %v", tt, y, expected)
+	}
+	assert.Equal(t, 30*time.Second, s.Time())
+}
+
+func TestSimulation_FractionalDeadTime(t *testing.T) {
+	// Given a first-order model with a dead time that is not a multiple of the sampling interval
+	s, err := NewSimulation(FOPDT{Gain: 1, TimeConstant: time.Second, DeadTime: 250 * time.Millisecond})
+	assert.NilError(t, err)
+	// When applying a unit step input with a sampling interval of 100 ms
+	for range 10 {
+		s.Update(1, 100*time.Millisecond)
+	}
+	// Then the output should match the analytic response
+	assert.Assert(t, math.Abs(s.Output()-(1-math.Exp(-0.75))) < 1e-6)
+}
+
+func TestSimulation_SecondOrderStepResponse(t *testing.T) {
+	// Given a simulation of 1/(s+1)^2
+	s, err := NewSimulation(TransferFunction{Numerator: []float64{1}, Denominator: []float64{1, 2, 1}})
+	assert.NilError(t, err)
+	for i := 1; i <= 100; i++ {
+		// When applying a unit step input
+		y := s.Update(1, 100*time.Millisecond)
+		// Then the output should match the analytic step response
+		tt := float64(i) / 10
+		assert.Assert(t, math.Abs(y-(1-(1+tt)*math.Exp(-tt))) < 1e-6)
+	}
+}
+
+func TestSimulation_DirectFeedthrough(t *testing.T) {
+	// Given a simulation of (s+2)/(s+1)
+	s, err := NewSimulation(TransferFunction{Numerator: []float64{1, 2}, Denominator: []float64{1, 1}})
+	assert.NilError(t, err)
+	for i := 1; i <= 50; i++ {
+		// When applying a unit step input
+		y := s.Update(1, 100*time.Millisecond)
+		// Then the output should match the analytic step response
+		tt := float64(i) / 10
+		assert.Assert(t, math.Abs(y-(2-math.Exp(-tt))) < 1e-6)
+	}
+}
+
+func TestSimulation_Stiff(t *testing.T) {
+	// Given a simulation of a model with a time constant much shorter than the sampling interval
+	s, err := NewSimulation(FOPDT{Gain: 1, TimeConstant: time.Millisecond})
+	assert.NilError(t, err)
+	// When applying a unit step input
+	y := s.Update(1, time.Second)
+	// Then the simulation should be stable
+	assert.Assert(t, math.Abs(y-1) < 1e-6)
+}
+
+func TestSimulation_Reset(t *testing.T) {
+	s, err := NewSimulation(FOPDT{Gain: 1, TimeConstant: time.Second, DeadTime: time.Second})
+	assert.NilError(t, err)
+	for range 30 {
+		s.Update(1, 100*time.Millisecond)
+	}
+	s.Reset()
+	assert.Equal(t, 0.0, s.Output())
+	assert.Equal(t, time.Duration(0), s.Time())
+	assert.Equal(t, 0.0, s.Update(1, 100*time.Millisecond))
+}
+
+func TestNewSimulation_Invalid(t *testing.T) {
+	_, err := NewSimulation(TransferFunction{Numerator: []float64{1}})
+	assert.ErrorContains(t, err, "model: new simulation")
+}
@@ -0,0 +1,221 @@
+package pid
+
+import (
+	"time"
+)
+
+// Modes of controllers in ModeChangeEvent.
+const (
+	// ModeNormal is the mode of a controller in normal operation.
+	ModeNormal = "normal"
+	// ModeFailsafe is the mode of a WatchdogController in failsafe.
+	ModeFailsafe = "failsafe"
+)
+
+// ObservedAntiWindupController wraps an AntiWindupController to notify an Observer of events.
+//
+// Once wrapped, the state and config of the controller should only be changed through the wrapper, so that no
+// events are missed.
+type ObservedAntiWindupController struct {
+	// Name of the controller in events.
+	Name string
+	// Controller is the observed controller.
+	Controller *AntiWindupController
+	// Observer is notified of events. No events are notified when nil.
+	Observer Observer
+
+	saturated bool
+}
+
+// Update the controller state.
+func (c *ObservedAntiWindupController) Update(input AntiWindupControllerInput) {
+	c.Controller.Update(input)
+	if isNaNOrInf(input.ReferenceSignal) || isNaNOrInf(input.ActualSignal) {
+		c.observe(InvalidInputEvent{ReferenceSignal: input.ReferenceSignal, ActualSignal: input.ActualSignal})
+		return
+	}
+	c.saturated = observeSaturation(c.Name, c.Observer, c.saturated, c.Controller.State.ControlSignal,
+		c.Controller.State.UnsaturatedControlSignal)
+}
+
+// Reset the controller state.
+func (c *ObservedAntiWindupController) Reset() {
+	event := ResetEvent{
+		PreviousControlSignal:        c.Controller.State.ControlSignal,
+		PreviousControlErrorIntegral: c.Controller.State.ControlErrorIntegral,
+	}
+	c.Controller.Reset()
+	c.saturated = false
+	c.observe(event)
+}
+
+// DischargeIntegral discharges the controller integral state over a configurable period of time.
+func (c *ObservedAntiWindupController) DischargeIntegral(dt time.Duration) {
+	previous := c.Controller.State.ControlErrorIntegral
+	c.Controller.DischargeIntegral(dt)
+	c.observe(IntegralDischargeEvent{
+		Duration:                     dt,
+		PreviousControlErrorIntegral: previous,
+		ControlErrorIntegral:         c.Controller.State.ControlErrorIntegral,
+	})
+}
+
+// SetConfig changes the controller config.
+func (c *ObservedAntiWindupController) SetConfig(config AntiWindupControllerConfig) {
+	previous := c.Controller.Config
+	c.Controller.Config = config
+	c.observe(ConfigChangeEvent{PreviousConfig: previous, Config: config})
+}
+
+func (c *ObservedAntiWindupController) observe(event Event) {
+	if c.Observer != nil {
+		c.Observer.Observe(c.Name, event)
+	}
+}
+
+// ObservedTrackingController wraps a TrackingController to notify an Observer of events.
+//
+// Once wrapped, the state and config of the controller should only be changed through the wrapper, so that no
+// events are missed.
+type ObservedTrackingController struct {
+	// Name of the controller in events.
+	Name string
+	// Controller is the observed controller.
+	Controller *TrackingController
+	// Observer is notified of events. No events are notified when nil.
+	Observer Observer
+
+	saturated bool
+}
+
+// Update the controller state.
+func (c *ObservedTrackingController) Update(input TrackingControllerInput) {
+	c.Controller.Update(input)
+	if isNaNOrInf(input.ReferenceSignal) || isNaNOrInf(input.ActualSignal) {
+		c.observe(InvalidInputEvent{ReferenceSignal: input.ReferenceSignal, ActualSignal: input.ActualSignal})
+		return
+	}
+	c.saturated = observeSaturation(c.Name, c.Observer, c.saturated, c.Controller.State.ControlSignal,
+		c.Controller.State.UnsaturatedControlSignal)
+}
+
+// Reset the controller state.
+func (c *ObservedTrackingController) Reset() {
+	event := ResetEvent{
+		PreviousControlSignal:        c.Controller.State.ControlSignal,
+		PreviousControlErrorIntegral: c.Controller.State.ControlErrorIntegral,
+	}
+	c.Controller.Reset()
+	c.saturated = false
+	c.observe(event)
+}
+
+// DischargeIntegral discharges the controller integral state over a configurable period of time.
+func (c *ObservedTrackingController) DischargeIntegral(dt time.Duration) {
+	previous := c.Controller.State.ControlErrorIntegral
+	c.Controller.DischargeIntegral(dt)
+	c.observe(IntegralDischargeEvent{
+		Duration:                     dt,
+		PreviousControlErrorIntegral: previous,
+		ControlErrorIntegral:         c.Controller.State.ControlErrorIntegral,
+	})
+}
+
+// SetConfig changes the controller config.
+func (c *ObservedTrackingController) SetConfig(config TrackingControllerConfig) {
+	previous := c.Controller.Config
+	c.Controller.Config = config
+	c.observe(ConfigChangeEvent{PreviousConfig: previous, Config: config})
+}
+
+func (c *ObservedTrackingController) observe(event Event) {
+	if c.Observer != nil {
+		c.Observer.Observe(c.Name, event)
+	}
+}
+
+// ObservedWatchdogController wraps a WatchdogController to notify an Observer of events.
+//
+// In addition to the events of the guarded controller, a ModeChangeEvent is notified when the controller enters
+// and leaves failsafe, with the detected faults as reason, and an IntegralDischargeEvent is notified for each
+// update that discharges the integral in FailsafeDischargeIntegral mode.
+//
+// Once wrapped, the state and config of the controller should only be changed through the wrapper, so that no
+// events are missed.
+type ObservedWatchdogController struct {
+	// Name of the controller in events.
+	Name string
+	// Controller is the observed controller.
+	Controller *WatchdogController
+	// Observer is notified of events. No events are notified when nil.
+	Observer Observer
+
+	saturated bool
+}
+
+// Update the controller state.
+func (c *ObservedWatchdogController) Update(input WatchdogControllerInput) {
+	previous := c.Controller.State
+	c.Controller.Update(input)
+	state := &c.Controller.State
+	if state.Fault&WatchdogFaultInvalid != 0 {
+		c.observe(InvalidInputEvent{ReferenceSignal: input.ReferenceSignal, ActualSignal: input.ActualSignal})
+	}
+	switch {
+	case state.Failsafe && !previous.Failsafe:
+		c.observe(ModeChangeEvent{PreviousMode: ModeNormal, Mode: ModeFailsafe, Reason: state.Fault.String()})
+	case !state.Failsafe && previous.Failsafe:
+		c.observe(ModeChangeEvent{PreviousMode: ModeFailsafe, Mode: ModeNormal, Reason: "recovered"})
+	}
+	if state.Failsafe {
+		if c.Controller.Config.Failsafe == FailsafeDischargeIntegral &&
+			state.Controller.ControlErrorIntegral != previous.Controller.ControlErrorIntegral {
+			c.observe(IntegralDischargeEvent{
+				Duration:                     input.SamplingInterval,
+				PreviousControlErrorIntegral: previous.Controller.ControlErrorIntegral,
+				ControlErrorIntegral:         state.Controller.ControlErrorIntegral,
+			})
+		}
+		return
+	}
+	c.saturated = observeSaturation(c.Name, c.Observer, c.saturated, state.Controller.ControlSignal,
+		state.Controller.UnsaturatedControlSignal)
+}
+
+// Reset the controller state.
+func (c *ObservedWatchdogController) Reset() {
+	event := ResetEvent{
+		PreviousControlSignal:        c.Controller.State.ControlSignal,
+		PreviousControlErrorIntegral: c.Controller.State.Controller.ControlErrorIntegral,
+	}
+	c.Controller.Reset()
+	c.saturated = false
+	c.observe(event)
+}
+
+// SetConfig changes the controller config.
+func (c *ObservedWatchdogController) SetConfig(config WatchdogControllerConfig) {
+	previous := c.Controller.Config
+	c.Controller.Config = config
+	c.observe(ConfigChangeEvent{PreviousConfig: previous, Config: config})
+}
+
+func (c *ObservedWatchdogController) observe(event Event) {
+	if c.Observer != nil {
+		c.Observer.Observe(c.Name, event)
+	}
+}
+
+// observeSaturation notifies a SaturationEvent when the saturation of a controller changed, and returns the
+// current saturation.
+func observeSaturation(name string, observer Observer, saturated bool, controlSignal, unsaturated float64) bool {
+	current := controlSignal != unsaturated
+	if current != saturated && observer != nil {
+		observer.Observe(name, SaturationEvent{
+			Saturated:                current,
+			ControlSignal:            controlSignal,
+			UnsaturatedControlSignal: unsaturated,
+		})
+	}
+	return current
+}
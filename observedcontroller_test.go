@@ -0,0 +1,156 @@
+package pid
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+type eventRecorder struct {
+	controllers []string
+	events      []Event
+}
+
+func (r *eventRecorder) Observe(controller string, event Event) {
+	r.controllers = append(r.controllers, controller)
+	r.events = append(r.events, event)
+}
+
+func TestObservedAntiWindupController(t *testing.T) {
+	// Given an observed PI controller
+	var recorder eventRecorder
+	c := &ObservedAntiWindupController{
+		Name: "speed",
+		Controller: &AntiWindupController{
+			Config: AntiWindupControllerConfig{
+				LowPassTimeConstant:           time.Second,
+				ProportionalGain:              1,
+				IntegralGain:                  1,
+				IntegralDischargeTimeConstant: 1,
+				MinOutput:                     -1,
+				MaxOutput:                     1,
+			},
+		},
+		Observer: &recorder,
+	}
+	initialConfig := c.Controller.Config
+	// When saturating, rejecting an input, leaving saturation, discharging, changing config and resetting
+	c.Update(AntiWindupControllerInput{ReferenceSignal: 2, SamplingInterval: time.Second})
+	c.Update(AntiWindupControllerInput{ReferenceSignal: math.Inf(1), SamplingInterval: time.Second})
+	c.Update(AntiWindupControllerInput{ReferenceSignal: -1, SamplingInterval: time.Second})
+	c.DischargeIntegral(500 * time.Millisecond)
+	config := c.Controller.Config
+	config.ProportionalGain = 2
+	c.SetConfig(config)
+	c.Reset()
+	// Then the events should be observed in order
+	assert.DeepEqual(t, []Event{
+		SaturationEvent{Saturated: true, ControlSignal: 1, UnsaturatedControlSignal: 2},
+		InvalidInputEvent{ReferenceSignal: math.Inf(1)},
+		SaturationEvent{Saturated: false, ControlSignal: 1, UnsaturatedControlSignal: 1},
+		IntegralDischargeEvent{
+			Duration:                     500 * time.Millisecond,
+			PreviousControlErrorIntegral: 2,
+			ControlErrorIntegral:         1,
+		},
+		ConfigChangeEvent{PreviousConfig: initialConfig, Config: config},
+		ResetEvent{PreviousControlSignal: 1, PreviousControlErrorIntegral: 1},
+	}, recorder.events)
+	assert.DeepEqual(t, []string{"speed", "speed", "speed", "speed", "speed", "speed"}, recorder.controllers)
+	assert.Equal(t, AntiWindupControllerState{}, c.Controller.State)
+}
+
+func TestObservedTrackingController(t *testing.T) {
+	// Given an observed P controller without observer
+	c := &ObservedTrackingController{
+		Controller: &TrackingController{
+			Config: TrackingControllerConfig{
+				LowPassTimeConstant: time.Second,
+				ProportionalGain:    1,
+				MinOutput:           -1,
+				MaxOutput:           1,
+			},
+		},
+	}
+	// When updating, the controller should behave as unobserved
+	c.Update(TrackingControllerInput{ReferenceSignal: 2, SamplingInterval: time.Second})
+	assert.Equal(t, 1.0, c.Controller.State.ControlSignal)
+	// And events should be observed once an observer is attached
+	var recorder eventRecorder
+	c.Observer = &recorder
+	c.Update(TrackingControllerInput{ReferenceSignal: 0.5, SamplingInterval: time.Second})
+	c.Update(TrackingControllerInput{ActualSignal: math.NaN(), SamplingInterval: time.Second})
+	c.DischargeIntegral(time.Second)
+	c.SetConfig(c.Controller.Config)
+	c.Reset()
+	assert.Equal(t, 5, len(recorder.events))
+	assert.DeepEqual(t, SaturationEvent{ControlSignal: 0.5, UnsaturatedControlSignal: 0.5}, recorder.events[0])
+	_, ok := recorder.events[1].(InvalidInputEvent)
+	assert.Assert(t, ok)
+	_, ok = recorder.events[2].(IntegralDischargeEvent)
+	assert.Assert(t, ok)
+	_, ok = recorder.events[3].(ConfigChangeEvent)
+	assert.Assert(t, ok)
+	assert.DeepEqual(t, ResetEvent{PreviousControlSignal: 0.5}, recorder.events[4])
+}
+
+func TestObservedWatchdogController(t *testing.T) {
+	// Given an observed watchdog controller that discharges the integral in failsafe
+	var recorder eventRecorder
+	c := &ObservedWatchdogController{
+		Name: "steering",
+		Controller: &WatchdogController{
+			Config: WatchdogControllerConfig{
+				Controller: AntiWindupControllerConfig{
+					LowPassTimeConstant:           1 * time.Second,
+					ProportionalGain:              1,
+					IntegralGain:                  10,
+					IntegralDischargeTimeConstant: 1,
+					MinOutput:                     -10,
+					MaxOutput:                     10,
+				},
+				RecoveryTime: 30 * time.Millisecond,
+				Failsafe:     FailsafeDischargeIntegral,
+			},
+		},
+		Observer: &recorder,
+	}
+	// When charging the integral with healthy measurements
+	for i := range 10 {
+		c.Update(WatchdogControllerInput{ReferenceSignal: 1, ActualSignal: float64(i) / 100, SamplingInterval: dtTest})
+	}
+	assert.Equal(t, 0, len(recorder.events))
+	// And entering failsafe on an invalid measurement
+	c.Update(WatchdogControllerInput{ReferenceSignal: 1, ActualSignal: math.NaN(), SamplingInterval: dtTest})
+	// Then the rejected input, the mode change and the discharge should be observed
+	assert.Equal(t, 3, len(recorder.events))
+	_, ok := recorder.events[0].(InvalidInputEvent)
+	assert.Assert(t, ok)
+	assert.DeepEqual(
+		t, ModeChangeEvent{PreviousMode: ModeNormal, Mode: ModeFailsafe, Reason: "invalid"}, recorder.events[1],
+	)
+	_, ok = recorder.events[2].(IntegralDischargeEvent)
+	assert.Assert(t, ok)
+	// When recovering with healthy measurements
+	recorder = eventRecorder{}
+	for i := range 10 {
+		c.Update(WatchdogControllerInput{ReferenceSignal: 1, ActualSignal: 0.1 + float64(i)/100, SamplingInterval: dtTest})
+	}
+	// Then leaving failsafe should be observed
+	var modeChanges []Event
+	for _, event := range recorder.events {
+		if _, ok := event.(ModeChangeEvent); ok {
+			modeChanges = append(modeChanges, event)
+		}
+	}
+	assert.DeepEqual(
+		t, []Event{ModeChangeEvent{PreviousMode: ModeFailsafe, Mode: ModeNormal, Reason: "recovered"}}, modeChanges,
+	)
+	// And resetting and changing config should be observed
+	recorder = eventRecorder{}
+	c.SetConfig(c.Controller.Config)
+	c.Reset()
+	assert.Equal(t, 2, len(recorder.events))
+}
@@ -0,0 +1,208 @@
+package pid
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// Observer is notified of notable controller events, such as saturation, rejected inputs and config changes.
+//
+// Observers are attached to controllers with the observed controller wrappers, for example
+// ObservedAntiWindupController. Observe is called synchronously from the control loop and should not block.
+type Observer interface {
+	// Observe is called with the name of the controller and the event.
+	Observe(controller string, event Event)
+}
+
+// ObserverFunc is a function that implements Observer.
+type ObserverFunc func(controller string, event Event)
+
+var _ Observer = ObserverFunc(nil)
+
+// Observe implements Observer.
+func (f ObserverFunc) Observe(controller string, event Event) {
+	f(controller, event)
+}
+
+// Event is a notable controller event. It is one of SaturationEvent, InvalidInputEvent, IntegralDischargeEvent,
+// ResetEvent, ModeChangeEvent and ConfigChangeEvent.
+type Event interface {
+	slog.LogValuer
+	// Message returns a short description of the event.
+	Message() string
+}
+
+// SaturationEvent is the event of a controller entering or leaving saturation.
+type SaturationEvent struct {
+	// Saturated is true when the controller entered saturation, and false when it left saturation.
+	Saturated bool
+	// ControlSignal is the control signal after the update.
+	ControlSignal float64
+	// UnsaturatedControlSignal is the control signal before saturation after the update.
+	UnsaturatedControlSignal float64
+}
+
+// InvalidInputEvent is the event of a controller rejecting a NaN or Inf input.
+type InvalidInputEvent struct {
+	// ReferenceSignal is the rejected reference signal.
+	ReferenceSignal float64
+	// ActualSignal is the rejected actual signal.
+	ActualSignal float64
+}
+
+// IntegralDischargeEvent is the event of a controller integral state being discharged.
+type IntegralDischargeEvent struct {
+	// Duration is the discharge duration.
+	Duration time.Duration
+	// PreviousControlErrorIntegral is the integral state before the discharge.
+	PreviousControlErrorIntegral float64
+	// ControlErrorIntegral is the integral state after the discharge.
+	ControlErrorIntegral float64
+}
+
+// ResetEvent is the event of a controller state being reset.
+type ResetEvent struct {
+	// PreviousControlSignal is the control signal before the reset.
+	PreviousControlSignal float64
+	// PreviousControlErrorIntegral is the integral state before the reset.
+	PreviousControlErrorIntegral float64
+}
+
+// ModeChangeEvent is the event of a controller changing mode, such as a WatchdogController entering failsafe.
+type ModeChangeEvent struct {
+	// PreviousMode is the mode before the change.
+	PreviousMode string
+	// Mode is the mode after the change.
+	Mode string
+	// Reason for the change.
+	Reason string
+}
+
+// ConfigChangeEvent is the event of a controller config being changed.
+type ConfigChangeEvent struct {
+	// PreviousConfig is the config before the change.
+	PreviousConfig any
+	// Config is the config after the change.
+	Config any
+}
+
+// Message implements Event.
+func (e SaturationEvent) Message() string {
+	if e.Saturated {
+		return "saturation entered"
+	}
+	return "saturation left"
+}
+
+// LogValue implements slog.LogValuer.
+func (e SaturationEvent) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.Float64("controlSignal", e.ControlSignal),
+		slog.Float64("unsaturatedControlSignal", e.UnsaturatedControlSignal),
+	)
+}
+
+// Message implements Event.
+func (e InvalidInputEvent) Message() string {
+	return "invalid input rejected"
+}
+
+// LogValue implements slog.LogValuer.
+func (e InvalidInputEvent) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.Float64("referenceSignal", e.ReferenceSignal),
+		slog.Float64("actualSignal", e.ActualSignal),
+	)
+}
+
+// Message implements Event.
+func (e IntegralDischargeEvent) Message() string {
+	return "integral discharged"
+}
+
+// LogValue implements slog.LogValuer.
+func (e IntegralDischargeEvent) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.Duration("duration", e.Duration),
+		slog.Float64("previousControlErrorIntegral", e.PreviousControlErrorIntegral),
+		slog.Float64("controlErrorIntegral", e.ControlErrorIntegral),
+	)
+}
+
+// Message implements Event.
+func (e ResetEvent) Message() string {
+	return "reset"
+}
+
+// LogValue implements slog.LogValuer.
+func (e ResetEvent) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.Float64("previousControlSignal", e.PreviousControlSignal),
+		slog.Float64("previousControlErrorIntegral", e.PreviousControlErrorIntegral),
+	)
+}
+
+// Message implements Event.
+func (e ModeChangeEvent) Message() string {
+	return "mode changed"
+}
+
+// LogValue implements slog.LogValuer.
+func (e ModeChangeEvent) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.String("previousMode", e.PreviousMode),
+		slog.String("mode", e.Mode),
+		slog.String("reason", e.Reason),
+	)
+}
+
+// Message implements Event.
+func (e ConfigChangeEvent) Message() string {
+	return "config changed"
+}
+
+// LogValue implements slog.LogValuer.
+func (e ConfigChangeEvent) LogValue() slog.Value {
+	return slog.GroupValue(
+		slog.Any("previousConfig", e.PreviousConfig),
+		slog.Any("config", e.Config),
+	)
+}
+
+// SlogObserver is an Observer that logs events to a slog.Handler.
+//
+// Each event is logged as a record with the event message, a controller attribute with the name of the
+// controller, and the attributes of the event. Rejected inputs and modes other than ModeNormal are logged at
+// warning level, and all other events at info level.
+type SlogObserver struct {
+	handler slog.Handler
+}
+
+var _ Observer = &SlogObserver{}
+
+// NewSlogObserver creates a new SlogObserver that logs to the handler.
+func NewSlogObserver(handler slog.Handler) *SlogObserver {
+	return &SlogObserver{handler: handler}
+}
+
+// Observe implements Observer.
+func (o *SlogObserver) Observe(controller string, event Event) {
+	level := slog.LevelInfo
+	switch event := event.(type) {
+	case InvalidInputEvent:
+		level = slog.LevelWarn
+	case ModeChangeEvent:
+		if event.Mode != ModeNormal {
+			level = slog.LevelWarn
+		}
+	}
+	ctx := context.Background()
+	if !o.handler.Enabled(ctx, level) {
+		return
+	}
+	record := slog.NewRecord(time.Now(), level, event.Message(), 0)
+	record.AddAttrs(slog.String("controller", controller))
+	record.AddAttrs(event.LogValue().Group()...)
+	_ = o.handler.Handle(ctx, record)
+}
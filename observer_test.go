@@ -0,0 +1,93 @@
+package pid
+
+import (
+	"bytes"
+	"log/slog"
+	"math"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func newTestSlogObserver(b *bytes.Buffer, level slog.Level) *SlogObserver {
+	return NewSlogObserver(slog.NewTextHandler(b, &slog.HandlerOptions{
+		Level: level,
+		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+			if len(groups) == 0 && a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+}
+
+func TestSlogObserver_Observe(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		event    Event
+		expected string
+	}{
+		{
+			name:  "saturation entered",
+			event: SaturationEvent{Saturated: true, ControlSignal: 1, UnsaturatedControlSignal: 2},
+			expected: `level=INFO msg="saturation entered" controller=speed controlSignal=1 ` +
+				`unsaturatedControlSignal=2`,
+		},
+		{
+			name:     "saturation left",
+			event:    SaturationEvent{ControlSignal: 0.5, UnsaturatedControlSignal: 0.5},
+			expected: `level=INFO msg="saturation left" controller=speed controlSignal=0.5 unsaturatedControlSignal=0.5`,
+		},
+		{
+			name:     "invalid input",
+			event:    InvalidInputEvent{ReferenceSignal: 1, ActualSignal: math.NaN()},
+			expected: `level=WARN msg="invalid input rejected" controller=speed referenceSignal=1 actualSignal=NaN`,
+		},
+		{
+			name:  "integral discharged",
+			event: IntegralDischargeEvent{Duration: time.Second, PreviousControlErrorIntegral: 2, ControlErrorIntegral: 1},
+			expected: `level=INFO msg="integral discharged" controller=speed duration=1s ` +
+				`previousControlErrorIntegral=2 controlErrorIntegral=1`,
+		},
+		{
+			name:     "reset",
+			event:    ResetEvent{PreviousControlSignal: 3, PreviousControlErrorIntegral: 4},
+			expected: `level=INFO msg=reset controller=speed previousControlSignal=3 previousControlErrorIntegral=4`,
+		},
+		{
+			name:  "failsafe entered",
+			event: ModeChangeEvent{PreviousMode: ModeNormal, Mode: ModeFailsafe, Reason: "stuck"},
+			expected: `level=WARN msg="mode changed" controller=speed previousMode=normal mode=failsafe ` +
+				`reason=stuck`,
+		},
+		{
+			name:  "failsafe left",
+			event: ModeChangeEvent{PreviousMode: ModeFailsafe, Mode: ModeNormal, Reason: "recovered"},
+			expected: `level=INFO msg="mode changed" controller=speed previousMode=failsafe mode=normal ` +
+				`reason=recovered`,
+		},
+		{
+			name:  "config changed",
+			event: ConfigChangeEvent{PreviousConfig: ControllerConfig{}, Config: ControllerConfig{ProportionalGain: 1}},
+			expected: `level=INFO msg="config changed" controller=speed previousConfig="{ProportionalGain:0 ` +
+				`IntegralGain:0 DerivativeGain:0}" config="{ProportionalGain:1 IntegralGain:0 DerivativeGain:0}"`,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var b bytes.Buffer
+			newTestSlogObserver(&b, slog.LevelInfo).Observe("speed", tt.event)
+			assert.Equal(t, tt.expected+"\n", b.String())
+		})
+	}
+}
+
+func TestSlogObserver_Level(t *testing.T) {
+	// Given a slog observer that only logs warnings
+	var b bytes.Buffer
+	o := newTestSlogObserver(&b, slog.LevelWarn)
+	// When observing an info event
+	o.Observe("speed", ResetEvent{})
+	// Then nothing should be logged
+	assert.Equal(t, "", b.String())
+}
@@ -0,0 +1,118 @@
+package pid
+
+import (
+	"math"
+	"time"
+)
+
+// OscillationDetector implements online detection of oscillating control loops.
+//
+// The detection method is defined in Hägglund, A Control-Loop Performance Monitor, 1995
+// (https://doi.org/10.1016/0967-0661(95)00164-6). The integrated absolute control error (IAE) is computed
+// between consecutive zero crossings of the control error. Every half-period with an IAE exceeding a limit is
+// counted as a load disturbance, and the loop is considered to be oscillating when the rate of load disturbances
+// within the supervision time exceeds a threshold.
+//
+// The detector can be attached to any controller by feeding it the ControlError from the controller state after
+// each update.
+type OscillationDetector struct {
+	// Config for the OscillationDetector.
+	Config OscillationDetectorConfig
+	// State of the OscillationDetector.
+	State OscillationDetectorState
+}
+
+// OscillationDetectorConfig contains configurable parameters for an OscillationDetector.
+type OscillationDetectorConfig struct {
+	// MinAmplitude is the smallest control error amplitude considered to be an oscillation.
+	MinAmplitude float64
+	// UltimatePeriod is the expected period of oscillations, for example the integral time of the controller.
+	// Together with MinAmplitude it determines the IAE limit MinAmplitude*UltimatePeriod/π of a load disturbance,
+	// which equals the IAE of a half-period of a sinusoid with amplitude MinAmplitude and period UltimatePeriod.
+	UltimatePeriod time.Duration
+	// SupervisionTime is the time constant with which counted load disturbances are forgotten.
+	SupervisionTime time.Duration
+	// DetectionThreshold is the number of load disturbances within the supervision time needed to detect an
+	// oscillation. Hägglund suggests 10 with a supervision time of 50 ultimate periods.
+	DetectionThreshold float64
+}
+
+// OscillationDetectorState holds mutable state for an OscillationDetector.
+type OscillationDetectorState struct {
+	// ControlError is the latest non-zero control error.
+	ControlError float64
+	// IntegralAbsoluteError is the integrated absolute control error since the latest zero crossing.
+	IntegralAbsoluteError float64
+	// PeakAbsoluteError is the largest absolute control error since the latest zero crossing.
+	PeakAbsoluteError float64
+	// HalfPeriod is the time elapsed since the latest zero crossing.
+	HalfPeriod time.Duration
+	// LoadDisturbances is the exponentially forgotten number of load disturbances.
+	LoadDisturbances float64
+	// Oscillating is true when an oscillation has been detected.
+	Oscillating bool
+	// Period is the estimated period of the latest detected load disturbances.
+	Period time.Duration
+	// Amplitude is the estimated amplitude of the latest detected load disturbances.
+	Amplitude float64
+	// PreviousHalfPeriod is the duration of the previous half-period, when it was a load disturbance, and zero
+	// otherwise.
+	PreviousHalfPeriod time.Duration
+	// PreviousPeakAbsoluteError is the largest absolute control error of the previous half-period, when it was a
+	// load disturbance, and zero otherwise.
+	PreviousPeakAbsoluteError float64
+}
+
+// OscillationDetectorInput holds the input parameters to an OscillationDetector.
+type OscillationDetectorInput struct {
+	// ControlError is the control error of the supervised controller.
+	ControlError float64
+	// SamplingInterval is the time interval elapsed since the previous call of the detector Update method.
+	SamplingInterval time.Duration
+}
+
+// Reset the detector state.
+func (d *OscillationDetector) Reset() {
+	d.State = OscillationDetectorState{}
+}
+
+// Update the detector state.
+func (d *OscillationDetector) Update(input OscillationDetectorInput) {
+	if math.IsNaN(input.ControlError) || math.IsInf(input.ControlError, 0) {
+		return
+	}
+	dt := input.SamplingInterval.Seconds()
+	if d.Config.SupervisionTime > 0 {
+		d.State.LoadDisturbances *= math.Exp(-dt / d.Config.SupervisionTime.Seconds())
+	}
+	e := input.ControlError
+	if e*d.State.ControlError < 0 {
+		d.zeroCrossing()
+	}
+	if e != 0 {
+		d.State.ControlError = e
+	}
+	d.State.IntegralAbsoluteError += math.Abs(e) * dt
+	d.State.PeakAbsoluteError = math.Max(d.State.PeakAbsoluteError, math.Abs(e))
+	d.State.HalfPeriod += input.SamplingInterval
+	d.State.Oscillating = d.Config.DetectionThreshold > 0 && d.State.LoadDisturbances >= d.Config.DetectionThreshold
+}
+
+func (d *OscillationDetector) zeroCrossing() {
+	limit := d.Config.MinAmplitude * d.Config.UltimatePeriod.Seconds() / math.Pi
+	if d.State.IntegralAbsoluteError > limit {
+		d.State.LoadDisturbances++
+		if d.State.PreviousHalfPeriod > 0 {
+			d.State.Period = d.State.PreviousHalfPeriod + d.State.HalfPeriod
+			d.State.Amplitude = (d.State.PreviousPeakAbsoluteError + d.State.PeakAbsoluteError) / 2
+		}
+		d.State.PreviousHalfPeriod = d.State.HalfPeriod
+		d.State.PreviousPeakAbsoluteError = d.State.PeakAbsoluteError
+	} else {
+		d.State.PreviousHalfPeriod = 0
+		d.State.PreviousPeakAbsoluteError = 0
+	}
+	d.State.IntegralAbsoluteError = 0
+	d.State.PeakAbsoluteError = 0
+	d.State.HalfPeriod = 0
+}
@@ -0,0 +1,116 @@
+package pid
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestOscillationDetector_Sinusoid(t *testing.T) {
+	// Given an oscillation detector
+	d := &OscillationDetector{
+		Config: OscillationDetectorConfig{
+			MinAmplitude:       0.5,
+			UltimatePeriod:     2 * time.Second,
+			SupervisionTime:    100 * time.Second,
+			DetectionThreshold: 10,
+		},
+	}
+	// When feeding a sinusoidal control error with amplitude 2 and period 4s
+	const amplitude, period = 2.0, 4.0
+	for i := range 4000 {
+		d.Update(OscillationDetectorInput{
+			ControlError:     amplitude * math.Sin(2*math.Pi*float64(i)*dtTest.Seconds()/period+0.1),
+			SamplingInterval: dtTest,
+		})
+	}
+	// Then an oscillation should be detected with the estimated period and amplitude
+	assert.Assert(t, d.State.Oscillating)
+	assert.Assert(t, math.Abs(d.State.Period.Seconds()-period) < 2*dtTest.Seconds())
+	assert.Assert(t, math.Abs(d.State.Amplitude-amplitude) < 1e-2)
+	// And the detection should clear after the oscillation has stopped
+	for range 10000 {
+		d.Update(OscillationDetectorInput{ControlError: 0, SamplingInterval: dtTest})
+	}
+	assert.Assert(t, !d.State.Oscillating)
+}
+
+func TestOscillationDetector_SmallOscillation(t *testing.T) {
+	// Given an oscillation detector
+	d := &OscillationDetector{
+		Config: OscillationDetectorConfig{
+			MinAmplitude:       0.5,
+			UltimatePeriod:     2 * time.Second,
+			SupervisionTime:    100 * time.Second,
+			DetectionThreshold: 10,
+		},
+	}
+	// When feeding an oscillating control error with an amplitude below the minimum amplitude
+	for i := range 4000 {
+		d.Update(OscillationDetectorInput{
+			ControlError:     0.4 * math.Sin(2*math.Pi*float64(i)*dtTest.Seconds()/2+0.1),
+			SamplingInterval: dtTest,
+		})
+	}
+	// Then no oscillation should be detected
+	assert.Assert(t, !d.State.Oscillating)
+	assert.Equal(t, 0.0, d.State.LoadDisturbances)
+}
+
+func TestOscillationDetector_DampedStepResponse(t *testing.T) {
+	// Given an oscillation detector supervising a well damped controller
+	d := &OscillationDetector{
+		Config: OscillationDetectorConfig{
+			MinAmplitude:       0.1,
+			UltimatePeriod:     2 * time.Second,
+			SupervisionTime:    100 * time.Second,
+			DetectionThreshold: 10,
+		},
+	}
+	// When feeding the control error of a step response with a single overshoot
+	for i := range 4000 {
+		tt := float64(i) * dtTest.Seconds()
+		d.Update(OscillationDetectorInput{
+			ControlError:     math.Exp(-tt) * math.Cos(tt),
+			SamplingInterval: dtTest,
+		})
+	}
+	// Then no oscillation should be detected
+	assert.Assert(t, !d.State.Oscillating)
+	assert.Assert(t, d.State.LoadDisturbances < 2)
+}
+
+func TestOscillationDetector_Reset(t *testing.T) {
+	d := &OscillationDetector{}
+	d.State = OscillationDetectorState{ControlError: 1, LoadDisturbances: 3, Oscillating: true}
+	d.Reset()
+	assert.Equal(t, OscillationDetectorState{}, d.State)
+}
+
+func TestOscillationDetector_RestoreState(t *testing.T) {
+	// Given an oscillation detector fed with half of an oscillation
+	config := OscillationDetectorConfig{
+		MinAmplitude:       0.5,
+		UltimatePeriod:     2 * time.Second,
+		SupervisionTime:    100 * time.Second,
+		DetectionThreshold: 10,
+	}
+	d := &OscillationDetector{Config: config}
+	controlError := func(i int) float64 {
+		return 2 * math.Sin(2*math.Pi*float64(i)*dtTest.Seconds()/4+0.1)
+	}
+	for i := range 300 {
+		d.Update(OscillationDetectorInput{ControlError: controlError(i), SamplingInterval: dtTest})
+	}
+	// When restoring its state in a new detector and feeding both the rest of the oscillation
+	restored := &OscillationDetector{Config: config, State: d.State}
+	for i := 300; i < 500; i++ {
+		d.Update(OscillationDetectorInput{ControlError: controlError(i), SamplingInterval: dtTest})
+		restored.Update(OscillationDetectorInput{ControlError: controlError(i), SamplingInterval: dtTest})
+	}
+	// Then the restored detector should have estimated the same period and amplitude
+	assert.Assert(t, restored.State.Period > 0)
+	assert.Equal(t, d.State, restored.State)
+}
@@ -0,0 +1,184 @@
+package pidpb
+
+import (
+	"fmt"
+	"time"
+
+	"go.einride.tech/pid"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// FromControllerConfig converts a pid.ControllerConfig to its protobuf representation.
+func FromControllerConfig(c pid.ControllerConfig) *ControllerConfig {
+	return &ControllerConfig{
+		ProportionalGain: c.ProportionalGain,
+		IntegralGain:     c.IntegralGain,
+		DerivativeGain:   c.DerivativeGain,
+	}
+}
+
+// ToControllerConfig converts a protobuf ControllerConfig to a pid.ControllerConfig.
+func ToControllerConfig(msg *ControllerConfig) pid.ControllerConfig {
+	return pid.ControllerConfig{
+		ProportionalGain: msg.GetProportionalGain(),
+		IntegralGain:     msg.GetIntegralGain(),
+		DerivativeGain:   msg.GetDerivativeGain(),
+	}
+}
+
+// FromControllerState converts a pid.ControllerState to its protobuf representation.
+func FromControllerState(s pid.ControllerState) *ControllerState {
+	return &ControllerState{
+		ControlError:           s.ControlError,
+		ControlErrorIntegral:   s.ControlErrorIntegral,
+		ControlErrorDerivative: s.ControlErrorDerivative,
+		ControlSignal:          s.ControlSignal,
+	}
+}
+
+// ToControllerState converts a protobuf ControllerState to a pid.ControllerState.
+func ToControllerState(msg *ControllerState) pid.ControllerState {
+	return pid.ControllerState{
+		ControlError:           msg.GetControlError(),
+		ControlErrorIntegral:   msg.GetControlErrorIntegral(),
+		ControlErrorDerivative: msg.GetControlErrorDerivative(),
+		ControlSignal:          msg.GetControlSignal(),
+	}
+}
+
+// FromAntiWindupControllerConfig converts a pid.AntiWindupControllerConfig to its protobuf representation.
+func FromAntiWindupControllerConfig(c pid.AntiWindupControllerConfig) *AntiWindupControllerConfig {
+	return &AntiWindupControllerConfig{
+		ProportionalGain:              c.ProportionalGain,
+		IntegralGain:                  c.IntegralGain,
+		DerivativeGain:                c.DerivativeGain,
+		AntiWindUpGain:                c.AntiWindUpGain,
+		IntegralDischargeTimeConstant: c.IntegralDischargeTimeConstant,
+		LowPassTimeConstant:           durationpb.New(c.LowPassTimeConstant),
+		MaxOutput:                     c.MaxOutput,
+		MinOutput:                     c.MinOutput,
+	}
+}
+
+// ToAntiWindupControllerConfig converts a protobuf AntiWindupControllerConfig to a pid.AntiWindupControllerConfig.
+//
+// It returns an error when the low pass time constant is invalid or out of the range of a time.Duration.
+func ToAntiWindupControllerConfig(msg *AntiWindupControllerConfig) (pid.AntiWindupControllerConfig, error) {
+	lowPassTimeConstant, err := toDuration(msg.GetLowPassTimeConstant())
+	if err != nil {
+		return pid.AntiWindupControllerConfig{}, fmt.Errorf(
+			"pidpb: to anti-windup controller config: low pass time constant: %w", err,
+		)
+	}
+	return pid.AntiWindupControllerConfig{
+		ProportionalGain:              msg.GetProportionalGain(),
+		IntegralGain:                  msg.GetIntegralGain(),
+		DerivativeGain:                msg.GetDerivativeGain(),
+		AntiWindUpGain:                msg.GetAntiWindUpGain(),
+		IntegralDischargeTimeConstant: msg.GetIntegralDischargeTimeConstant(),
+		LowPassTimeConstant:           lowPassTimeConstant,
+		MaxOutput:                     msg.GetMaxOutput(),
+		MinOutput:                     msg.GetMinOutput(),
+	}, nil
+}
+
+// FromAntiWindupControllerState converts a pid.AntiWindupControllerState to its protobuf representation.
+func FromAntiWindupControllerState(s pid.AntiWindupControllerState) *AntiWindupControllerState {
+	return &AntiWindupControllerState{
+		ControlError:             s.ControlError,
+		ControlErrorIntegrand:    s.ControlErrorIntegrand,
+		ControlErrorIntegral:     s.ControlErrorIntegral,
+		ControlErrorDerivative:   s.ControlErrorDerivative,
+		ControlSignal:            s.ControlSignal,
+		UnsaturatedControlSignal: s.UnsaturatedControlSignal,
+	}
+}
+
+// ToAntiWindupControllerState converts a protobuf AntiWindupControllerState to a pid.AntiWindupControllerState.
+func ToAntiWindupControllerState(msg *AntiWindupControllerState) pid.AntiWindupControllerState {
+	return pid.AntiWindupControllerState{
+		ControlError:             msg.GetControlError(),
+		ControlErrorIntegrand:    msg.GetControlErrorIntegrand(),
+		ControlErrorIntegral:     msg.GetControlErrorIntegral(),
+		ControlErrorDerivative:   msg.GetControlErrorDerivative(),
+		ControlSignal:            msg.GetControlSignal(),
+		UnsaturatedControlSignal: msg.GetUnsaturatedControlSignal(),
+	}
+}
+
+// FromTrackingControllerConfig converts a pid.TrackingControllerConfig to its protobuf representation.
+func FromTrackingControllerConfig(c pid.TrackingControllerConfig) *TrackingControllerConfig {
+	return &TrackingControllerConfig{
+		ProportionalGain:              c.ProportionalGain,
+		IntegralGain:                  c.IntegralGain,
+		DerivativeGain:                c.DerivativeGain,
+		AntiWindUpGain:                c.AntiWindUpGain,
+		IntegralDischargeTimeConstant: c.IntegralDischargeTimeConstant,
+		LowPassTimeConstant:           durationpb.New(c.LowPassTimeConstant),
+		MaxOutput:                     c.MaxOutput,
+		MinOutput:                     c.MinOutput,
+	}
+}
+
+// ToTrackingControllerConfig converts a protobuf TrackingControllerConfig to a pid.TrackingControllerConfig.
+//
+// It returns an error when the low pass time constant is invalid or out of the range of a time.Duration.
+func ToTrackingControllerConfig(msg *TrackingControllerConfig) (pid.TrackingControllerConfig, error) {
+	lowPassTimeConstant, err := toDuration(msg.GetLowPassTimeConstant())
+	if err != nil {
+		return pid.TrackingControllerConfig{}, fmt.Errorf(
+			"pidpb: to tracking controller config: low pass time constant: %w", err,
+		)
+	}
+	return pid.TrackingControllerConfig{
+		ProportionalGain:              msg.GetProportionalGain(),
+		IntegralGain:                  msg.GetIntegralGain(),
+		DerivativeGain:                msg.GetDerivativeGain(),
+		AntiWindUpGain:                msg.GetAntiWindUpGain(),
+		IntegralDischargeTimeConstant: msg.GetIntegralDischargeTimeConstant(),
+		LowPassTimeConstant:           lowPassTimeConstant,
+		MaxOutput:                     msg.GetMaxOutput(),
+		MinOutput:                     msg.GetMinOutput(),
+	}, nil
+}
+
+// FromTrackingControllerState converts a pid.TrackingControllerState to its protobuf representation.
+func FromTrackingControllerState(s pid.TrackingControllerState) *TrackingControllerState {
+	return &TrackingControllerState{
+		ControlError:             s.ControlError,
+		ControlErrorIntegrand:    s.ControlErrorIntegrand,
+		ControlErrorIntegral:     s.ControlErrorIntegral,
+		ControlErrorDerivative:   s.ControlErrorDerivative,
+		ControlSignal:            s.ControlSignal,
+		UnsaturatedControlSignal: s.UnsaturatedControlSignal,
+	}
+}
+
+// ToTrackingControllerState converts a protobuf TrackingControllerState to a pid.TrackingControllerState.
+func ToTrackingControllerState(msg *TrackingControllerState) pid.TrackingControllerState {
+	return pid.TrackingControllerState{
+		ControlError:             msg.GetControlError(),
+		ControlErrorIntegrand:    msg.GetControlErrorIntegrand(),
+		ControlErrorIntegral:     msg.GetControlErrorIntegral(),
+		ControlErrorDerivative:   msg.GetControlErrorDerivative(),
+		ControlSignal:            msg.GetControlSignal(),
+		UnsaturatedControlSignal: msg.GetUnsaturatedControlSignal(),
+	}
+}
+
+// toDuration converts a protobuf duration to a time.Duration, treating a nil duration as zero.
+func toDuration(d *durationpb.Duration) (time.Duration, error) {
+	if d == nil {
+		return 0, nil
+	}
+	if err := d.CheckValid(); err != nil {
+		return 0, err
+	}
+	result := d.AsDuration()
+	// AsDuration saturates on overflow, which would not be a lossless conversion.
+	if roundTrip := durationpb.New(result); roundTrip.GetSeconds() != d.GetSeconds() ||
+		roundTrip.GetNanos() != d.GetNanos() {
+		return 0, fmt.Errorf("duration %ds %dns out of range", d.GetSeconds(), d.GetNanos())
+	}
+	return result, nil
+}
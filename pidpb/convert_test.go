@@ -0,0 +1,110 @@
+package pidpb
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"go.einride.tech/pid"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"gotest.tools/v3/assert"
+)
+
+func TestControllerConfig_RoundTrip(t *testing.T) {
+	expected := pid.ControllerConfig{ProportionalGain: 1.5, IntegralGain: 0.1, DerivativeGain: 1e-3}
+	actual := ToControllerConfig(roundTrip(t, FromControllerConfig(expected)))
+	assert.Equal(t, expected, actual)
+}
+
+func TestControllerState_RoundTrip(t *testing.T) {
+	expected := pid.ControllerState{
+		ControlError:           1,
+		ControlErrorIntegral:   -2,
+		ControlErrorDerivative: math.MaxFloat64,
+		ControlSignal:          math.SmallestNonzeroFloat64,
+	}
+	actual := ToControllerState(roundTrip(t, FromControllerState(expected)))
+	assert.Equal(t, expected, actual)
+}
+
+func TestAntiWindupControllerConfig_RoundTrip(t *testing.T) {
+	expected := pid.AntiWindupControllerConfig{
+		ProportionalGain:              1,
+		IntegralGain:                  2,
+		DerivativeGain:                3,
+		AntiWindUpGain:                4,
+		IntegralDischargeTimeConstant: 5,
+		LowPassTimeConstant:           1234567891 * time.Nanosecond,
+		MaxOutput:                     10,
+		MinOutput:                     -10,
+	}
+	actual, err := ToAntiWindupControllerConfig(roundTrip(t, FromAntiWindupControllerConfig(expected)))
+	assert.NilError(t, err)
+	assert.Equal(t, expected, actual)
+}
+
+func TestAntiWindupControllerState_RoundTrip(t *testing.T) {
+	expected := pid.AntiWindupControllerState{
+		ControlError:             1,
+		ControlErrorIntegrand:    2,
+		ControlErrorIntegral:     3,
+		ControlErrorDerivative:   4,
+		ControlSignal:            5,
+		UnsaturatedControlSignal: 6,
+	}
+	actual := ToAntiWindupControllerState(roundTrip(t, FromAntiWindupControllerState(expected)))
+	assert.Equal(t, expected, actual)
+}
+
+func TestTrackingControllerConfig_RoundTrip(t *testing.T) {
+	expected := pid.TrackingControllerConfig{
+		ProportionalGain:              1,
+		IntegralGain:                  2,
+		DerivativeGain:                3,
+		AntiWindUpGain:                4,
+		IntegralDischargeTimeConstant: 5,
+		LowPassTimeConstant:           -time.Duration(math.MaxInt64),
+		MaxOutput:                     10,
+		MinOutput:                     -10,
+	}
+	actual, err := ToTrackingControllerConfig(roundTrip(t, FromTrackingControllerConfig(expected)))
+	assert.NilError(t, err)
+	assert.Equal(t, expected, actual)
+}
+
+func TestTrackingControllerState_RoundTrip(t *testing.T) {
+	expected := pid.TrackingControllerState{
+		ControlError:             1,
+		ControlErrorIntegrand:    2,
+		ControlErrorIntegral:     3,
+		ControlErrorDerivative:   4,
+		ControlSignal:            5,
+		UnsaturatedControlSignal: 6,
+	}
+	actual := ToTrackingControllerState(roundTrip(t, FromTrackingControllerState(expected)))
+	assert.Equal(t, expected, actual)
+}
+
+func TestToAntiWindupControllerConfig_Nil(t *testing.T) {
+	actual, err := ToAntiWindupControllerConfig(nil)
+	assert.NilError(t, err)
+	assert.Equal(t, pid.AntiWindupControllerConfig{}, actual)
+}
+
+func TestToAntiWindupControllerConfig_DurationOutOfRange(t *testing.T) {
+	_, err := ToAntiWindupControllerConfig(&AntiWindupControllerConfig{
+		LowPassTimeConstant: &durationpb.Duration{Seconds: math.MaxInt64 / int64(time.Second) * 2},
+	})
+	assert.ErrorContains(t, err, "pidpb: to anti-windup controller config: low pass time constant: ")
+	assert.ErrorContains(t, err, "out of range")
+}
+
+func roundTrip[T proto.Message](t *testing.T, msg T) T {
+	t.Helper()
+	data, err := proto.Marshal(msg)
+	assert.NilError(t, err)
+	result := msg.ProtoReflect().New().Interface().(T)
+	assert.NilError(t, proto.Unmarshal(data, result))
+	return result
+}
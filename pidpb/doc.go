@@ -0,0 +1,5 @@
+// Package pidpb provides protobuf representations of the controller configs and states in package pid,
+// and lossless conversions between them.
+//
+// The generated code is produced from proto/einride/pid/v1/pid.proto with buf, see proto/buf.gen.yaml.
+package pidpb
@@ -0,0 +1,667 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        (unknown)
+// source: einride/pid/v1/pid.proto
+
+package pidpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// ControllerConfig contains configurable parameters for a Controller.
+type ControllerConfig struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Ratio of output response to error signal.
+	ProportionalGain float64 `protobuf:"fixed64,1,opt,name=proportional_gain,json=proportionalGain,proto3" json:"proportional_gain,omitempty"`
+	// Previous error's affect on output.
+	IntegralGain float64 `protobuf:"fixed64,2,opt,name=integral_gain,json=integralGain,proto3" json:"integral_gain,omitempty"`
+	// Decreases the sensitivity to large reference changes.
+	DerivativeGain float64 `protobuf:"fixed64,3,opt,name=derivative_gain,json=derivativeGain,proto3" json:"derivative_gain,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *ControllerConfig) Reset() {
+	*x = ControllerConfig{}
+	mi := &file_einride_pid_v1_pid_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ControllerConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ControllerConfig) ProtoMessage() {}
+
+func (x *ControllerConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_einride_pid_v1_pid_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ControllerConfig.ProtoReflect.Descriptor instead.
+func (*ControllerConfig) Descriptor() ([]byte, []int) {
+	return file_einride_pid_v1_pid_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ControllerConfig) GetProportionalGain() float64 {
+	if x != nil {
+		return x.ProportionalGain
+	}
+	return 0
+}
+
+func (x *ControllerConfig) GetIntegralGain() float64 {
+	if x != nil {
+		return x.IntegralGain
+	}
+	return 0
+}
+
+func (x *ControllerConfig) GetDerivativeGain() float64 {
+	if x != nil {
+		return x.DerivativeGain
+	}
+	return 0
+}
+
+// ControllerState holds mutable state for a Controller.
+type ControllerState struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The difference between reference and current value.
+	ControlError float64 `protobuf:"fixed64,1,opt,name=control_error,json=controlError,proto3" json:"control_error,omitempty"`
+	// The integrated control error over time.
+	ControlErrorIntegral float64 `protobuf:"fixed64,2,opt,name=control_error_integral,json=controlErrorIntegral,proto3" json:"control_error_integral,omitempty"`
+	// The rate of change of the control error.
+	ControlErrorDerivative float64 `protobuf:"fixed64,3,opt,name=control_error_derivative,json=controlErrorDerivative,proto3" json:"control_error_derivative,omitempty"`
+	// The current control signal output of the controller.
+	ControlSignal float64 `protobuf:"fixed64,4,opt,name=control_signal,json=controlSignal,proto3" json:"control_signal,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ControllerState) Reset() {
+	*x = ControllerState{}
+	mi := &file_einride_pid_v1_pid_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ControllerState) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ControllerState) ProtoMessage() {}
+
+func (x *ControllerState) ProtoReflect() protoreflect.Message {
+	mi := &file_einride_pid_v1_pid_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ControllerState.ProtoReflect.Descriptor instead.
+func (*ControllerState) Descriptor() ([]byte, []int) {
+	return file_einride_pid_v1_pid_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ControllerState) GetControlError() float64 {
+	if x != nil {
+		return x.ControlError
+	}
+	return 0
+}
+
+func (x *ControllerState) GetControlErrorIntegral() float64 {
+	if x != nil {
+		return x.ControlErrorIntegral
+	}
+	return 0
+}
+
+func (x *ControllerState) GetControlErrorDerivative() float64 {
+	if x != nil {
+		return x.ControlErrorDerivative
+	}
+	return 0
+}
+
+func (x *ControllerState) GetControlSignal() float64 {
+	if x != nil {
+		return x.ControlSignal
+	}
+	return 0
+}
+
+// AntiWindupControllerConfig contains config parameters for an AntiWindupController.
+type AntiWindupControllerConfig struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The P part gain.
+	ProportionalGain float64 `protobuf:"fixed64,1,opt,name=proportional_gain,json=proportionalGain,proto3" json:"proportional_gain,omitempty"`
+	// The I part gain.
+	IntegralGain float64 `protobuf:"fixed64,2,opt,name=integral_gain,json=integralGain,proto3" json:"integral_gain,omitempty"`
+	// The D part gain.
+	DerivativeGain float64 `protobuf:"fixed64,3,opt,name=derivative_gain,json=derivativeGain,proto3" json:"derivative_gain,omitempty"`
+	// The anti-windup tracking gain.
+	AntiWindUpGain float64 `protobuf:"fixed64,4,opt,name=anti_wind_up_gain,json=antiWindUpGain,proto3" json:"anti_wind_up_gain,omitempty"`
+	// The time constant to discharge the integral state of the PID controller (s).
+	IntegralDischargeTimeConstant float64 `protobuf:"fixed64,5,opt,name=integral_discharge_time_constant,json=integralDischargeTimeConstant,proto3" json:"integral_discharge_time_constant,omitempty"`
+	// The D part low-pass filter time constant.
+	LowPassTimeConstant *durationpb.Duration `protobuf:"bytes,6,opt,name=low_pass_time_constant,json=lowPassTimeConstant,proto3" json:"low_pass_time_constant,omitempty"`
+	// The max output from the PID.
+	MaxOutput float64 `protobuf:"fixed64,7,opt,name=max_output,json=maxOutput,proto3" json:"max_output,omitempty"`
+	// The min output from the PID.
+	MinOutput     float64 `protobuf:"fixed64,8,opt,name=min_output,json=minOutput,proto3" json:"min_output,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AntiWindupControllerConfig) Reset() {
+	*x = AntiWindupControllerConfig{}
+	mi := &file_einride_pid_v1_pid_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AntiWindupControllerConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AntiWindupControllerConfig) ProtoMessage() {}
+
+func (x *AntiWindupControllerConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_einride_pid_v1_pid_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AntiWindupControllerConfig.ProtoReflect.Descriptor instead.
+func (*AntiWindupControllerConfig) Descriptor() ([]byte, []int) {
+	return file_einride_pid_v1_pid_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *AntiWindupControllerConfig) GetProportionalGain() float64 {
+	if x != nil {
+		return x.ProportionalGain
+	}
+	return 0
+}
+
+func (x *AntiWindupControllerConfig) GetIntegralGain() float64 {
+	if x != nil {
+		return x.IntegralGain
+	}
+	return 0
+}
+
+func (x *AntiWindupControllerConfig) GetDerivativeGain() float64 {
+	if x != nil {
+		return x.DerivativeGain
+	}
+	return 0
+}
+
+func (x *AntiWindupControllerConfig) GetAntiWindUpGain() float64 {
+	if x != nil {
+		return x.AntiWindUpGain
+	}
+	return 0
+}
+
+func (x *AntiWindupControllerConfig) GetIntegralDischargeTimeConstant() float64 {
+	if x != nil {
+		return x.IntegralDischargeTimeConstant
+	}
+	return 0
+}
+
+func (x *AntiWindupControllerConfig) GetLowPassTimeConstant() *durationpb.Duration {
+	if x != nil {
+		return x.LowPassTimeConstant
+	}
+	return nil
+}
+
+func (x *AntiWindupControllerConfig) GetMaxOutput() float64 {
+	if x != nil {
+		return x.MaxOutput
+	}
+	return 0
+}
+
+func (x *AntiWindupControllerConfig) GetMinOutput() float64 {
+	if x != nil {
+		return x.MinOutput
+	}
+	return 0
+}
+
+// AntiWindupControllerState holds mutable state for an AntiWindupController.
+type AntiWindupControllerState struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The difference between reference and current value.
+	ControlError float64 `protobuf:"fixed64,1,opt,name=control_error,json=controlError,proto3" json:"control_error,omitempty"`
+	// The control error integrand, which includes the anti-windup correction.
+	ControlErrorIntegrand float64 `protobuf:"fixed64,2,opt,name=control_error_integrand,json=controlErrorIntegrand,proto3" json:"control_error_integrand,omitempty"`
+	// The control error integrand integrated over time.
+	ControlErrorIntegral float64 `protobuf:"fixed64,3,opt,name=control_error_integral,json=controlErrorIntegral,proto3" json:"control_error_integral,omitempty"`
+	// The low-pass filtered time-derivative of the control error.
+	ControlErrorDerivative float64 `protobuf:"fixed64,4,opt,name=control_error_derivative,json=controlErrorDerivative,proto3" json:"control_error_derivative,omitempty"`
+	// The current control signal output of the controller.
+	ControlSignal float64 `protobuf:"fixed64,5,opt,name=control_signal,json=controlSignal,proto3" json:"control_signal,omitempty"`
+	// The control signal before saturation.
+	UnsaturatedControlSignal float64 `protobuf:"fixed64,6,opt,name=unsaturated_control_signal,json=unsaturatedControlSignal,proto3" json:"unsaturated_control_signal,omitempty"`
+	unknownFields            protoimpl.UnknownFields
+	sizeCache                protoimpl.SizeCache
+}
+
+func (x *AntiWindupControllerState) Reset() {
+	*x = AntiWindupControllerState{}
+	mi := &file_einride_pid_v1_pid_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AntiWindupControllerState) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AntiWindupControllerState) ProtoMessage() {}
+
+func (x *AntiWindupControllerState) ProtoReflect() protoreflect.Message {
+	mi := &file_einride_pid_v1_pid_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AntiWindupControllerState.ProtoReflect.Descriptor instead.
+func (*AntiWindupControllerState) Descriptor() ([]byte, []int) {
+	return file_einride_pid_v1_pid_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *AntiWindupControllerState) GetControlError() float64 {
+	if x != nil {
+		return x.ControlError
+	}
+	return 0
+}
+
+func (x *AntiWindupControllerState) GetControlErrorIntegrand() float64 {
+	if x != nil {
+		return x.ControlErrorIntegrand
+	}
+	return 0
+}
+
+func (x *AntiWindupControllerState) GetControlErrorIntegral() float64 {
+	if x != nil {
+		return x.ControlErrorIntegral
+	}
+	return 0
+}
+
+func (x *AntiWindupControllerState) GetControlErrorDerivative() float64 {
+	if x != nil {
+		return x.ControlErrorDerivative
+	}
+	return 0
+}
+
+func (x *AntiWindupControllerState) GetControlSignal() float64 {
+	if x != nil {
+		return x.ControlSignal
+	}
+	return 0
+}
+
+func (x *AntiWindupControllerState) GetUnsaturatedControlSignal() float64 {
+	if x != nil {
+		return x.UnsaturatedControlSignal
+	}
+	return 0
+}
+
+// TrackingControllerConfig contains configurable parameters for a TrackingController.
+type TrackingControllerConfig struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The P part gain.
+	ProportionalGain float64 `protobuf:"fixed64,1,opt,name=proportional_gain,json=proportionalGain,proto3" json:"proportional_gain,omitempty"`
+	// The I part gain.
+	IntegralGain float64 `protobuf:"fixed64,2,opt,name=integral_gain,json=integralGain,proto3" json:"integral_gain,omitempty"`
+	// The D part gain.
+	DerivativeGain float64 `protobuf:"fixed64,3,opt,name=derivative_gain,json=derivativeGain,proto3" json:"derivative_gain,omitempty"`
+	// The anti-windup tracking gain.
+	AntiWindUpGain float64 `protobuf:"fixed64,4,opt,name=anti_wind_up_gain,json=antiWindUpGain,proto3" json:"anti_wind_up_gain,omitempty"`
+	// The time constant to discharge the integral state of the PID controller (s).
+	IntegralDischargeTimeConstant float64 `protobuf:"fixed64,5,opt,name=integral_discharge_time_constant,json=integralDischargeTimeConstant,proto3" json:"integral_discharge_time_constant,omitempty"`
+	// The D part low-pass filter time constant.
+	LowPassTimeConstant *durationpb.Duration `protobuf:"bytes,6,opt,name=low_pass_time_constant,json=lowPassTimeConstant,proto3" json:"low_pass_time_constant,omitempty"`
+	// The max output from the PID.
+	MaxOutput float64 `protobuf:"fixed64,7,opt,name=max_output,json=maxOutput,proto3" json:"max_output,omitempty"`
+	// The min output from the PID.
+	MinOutput     float64 `protobuf:"fixed64,8,opt,name=min_output,json=minOutput,proto3" json:"min_output,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TrackingControllerConfig) Reset() {
+	*x = TrackingControllerConfig{}
+	mi := &file_einride_pid_v1_pid_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TrackingControllerConfig) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TrackingControllerConfig) ProtoMessage() {}
+
+func (x *TrackingControllerConfig) ProtoReflect() protoreflect.Message {
+	mi := &file_einride_pid_v1_pid_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TrackingControllerConfig.ProtoReflect.Descriptor instead.
+func (*TrackingControllerConfig) Descriptor() ([]byte, []int) {
+	return file_einride_pid_v1_pid_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *TrackingControllerConfig) GetProportionalGain() float64 {
+	if x != nil {
+		return x.ProportionalGain
+	}
+	return 0
+}
+
+func (x *TrackingControllerConfig) GetIntegralGain() float64 {
+	if x != nil {
+		return x.IntegralGain
+	}
+	return 0
+}
+
+func (x *TrackingControllerConfig) GetDerivativeGain() float64 {
+	if x != nil {
+		return x.DerivativeGain
+	}
+	return 0
+}
+
+func (x *TrackingControllerConfig) GetAntiWindUpGain() float64 {
+	if x != nil {
+		return x.AntiWindUpGain
+	}
+	return 0
+}
+
+func (x *TrackingControllerConfig) GetIntegralDischargeTimeConstant() float64 {
+	if x != nil {
+		return x.IntegralDischargeTimeConstant
+	}
+	return 0
+}
+
+func (x *TrackingControllerConfig) GetLowPassTimeConstant() *durationpb.Duration {
+	if x != nil {
+		return x.LowPassTimeConstant
+	}
+	return nil
+}
+
+func (x *TrackingControllerConfig) GetMaxOutput() float64 {
+	if x != nil {
+		return x.MaxOutput
+	}
+	return 0
+}
+
+func (x *TrackingControllerConfig) GetMinOutput() float64 {
+	if x != nil {
+		return x.MinOutput
+	}
+	return 0
+}
+
+// TrackingControllerState holds the mutable state a TrackingController.
+type TrackingControllerState struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The difference between reference and current value.
+	ControlError float64 `protobuf:"fixed64,1,opt,name=control_error,json=controlError,proto3" json:"control_error,omitempty"`
+	// The integrated control error over time.
+	ControlErrorIntegrand float64 `protobuf:"fixed64,2,opt,name=control_error_integrand,json=controlErrorIntegrand,proto3" json:"control_error_integrand,omitempty"`
+	// The control error integrand integrated over time.
+	ControlErrorIntegral float64 `protobuf:"fixed64,3,opt,name=control_error_integral,json=controlErrorIntegral,proto3" json:"control_error_integral,omitempty"`
+	// The low-pass filtered time-derivative of the control error.
+	ControlErrorDerivative float64 `protobuf:"fixed64,4,opt,name=control_error_derivative,json=controlErrorDerivative,proto3" json:"control_error_derivative,omitempty"`
+	// The current control signal output of the controller.
+	ControlSignal float64 `protobuf:"fixed64,5,opt,name=control_signal,json=controlSignal,proto3" json:"control_signal,omitempty"`
+	// The control signal before saturation.
+	UnsaturatedControlSignal float64 `protobuf:"fixed64,6,opt,name=unsaturated_control_signal,json=unsaturatedControlSignal,proto3" json:"unsaturated_control_signal,omitempty"`
+	unknownFields            protoimpl.UnknownFields
+	sizeCache                protoimpl.SizeCache
+}
+
+func (x *TrackingControllerState) Reset() {
+	*x = TrackingControllerState{}
+	mi := &file_einride_pid_v1_pid_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TrackingControllerState) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TrackingControllerState) ProtoMessage() {}
+
+func (x *TrackingControllerState) ProtoReflect() protoreflect.Message {
+	mi := &file_einride_pid_v1_pid_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TrackingControllerState.ProtoReflect.Descriptor instead.
+func (*TrackingControllerState) Descriptor() ([]byte, []int) {
+	return file_einride_pid_v1_pid_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *TrackingControllerState) GetControlError() float64 {
+	if x != nil {
+		return x.ControlError
+	}
+	return 0
+}
+
+func (x *TrackingControllerState) GetControlErrorIntegrand() float64 {
+	if x != nil {
+		return x.ControlErrorIntegrand
+	}
+	return 0
+}
+
+func (x *TrackingControllerState) GetControlErrorIntegral() float64 {
+	if x != nil {
+		return x.ControlErrorIntegral
+	}
+	return 0
+}
+
+func (x *TrackingControllerState) GetControlErrorDerivative() float64 {
+	if x != nil {
+		return x.ControlErrorDerivative
+	}
+	return 0
+}
+
+func (x *TrackingControllerState) GetControlSignal() float64 {
+	if x != nil {
+		return x.ControlSignal
+	}
+	return 0
+}
+
+func (x *TrackingControllerState) GetUnsaturatedControlSignal() float64 {
+	if x != nil {
+		return x.UnsaturatedControlSignal
+	}
+	return 0
+}
+
+var File_einride_pid_v1_pid_proto protoreflect.FileDescriptor
+
+const file_einride_pid_v1_pid_proto_rawDesc = "" +
+	"\n" +
+	"\x18einride/pid/v1/pid.proto\x12\x0eeinride.pid.v1\x1a\x1egoogle/protobuf/duration.proto\"\x8d\x01\n" +
+	"\x10ControllerConfig\x12+\n" +
+	"\x11proportional_gain\x18\x01 \x01(\x01R\x10proportionalGain\x12#\n" +
+	"\rintegral_gain\x18\x02 \x01(\x01R\fintegralGain\x12'\n" +
+	"\x0fderivative_gain\x18\x03 \x01(\x01R\x0ederivativeGain\"\xcd\x01\n" +
+	"\x0fControllerState\x12#\n" +
+	"\rcontrol_error\x18\x01 \x01(\x01R\fcontrolError\x124\n" +
+	"\x16control_error_integral\x18\x02 \x01(\x01R\x14controlErrorIntegral\x128\n" +
+	"\x18control_error_derivative\x18\x03 \x01(\x01R\x16controlErrorDerivative\x12%\n" +
+	"\x0econtrol_signal\x18\x04 \x01(\x01R\rcontrolSignal\"\x99\x03\n" +
+	"\x1aAntiWindupControllerConfig\x12+\n" +
+	"\x11proportional_gain\x18\x01 \x01(\x01R\x10proportionalGain\x12#\n" +
+	"\rintegral_gain\x18\x02 \x01(\x01R\fintegralGain\x12'\n" +
+	"\x0fderivative_gain\x18\x03 \x01(\x01R\x0ederivativeGain\x12)\n" +
+	"\x11anti_wind_up_gain\x18\x04 \x01(\x01R\x0eantiWindUpGain\x12G\n" +
+	" integral_discharge_time_constant\x18\x05 \x01(\x01R\x1dintegralDischargeTimeConstant\x12N\n" +
+	"\x16low_pass_time_constant\x18\x06 \x01(\v2\x19.google.protobuf.DurationR\x13lowPassTimeConstant\x12\x1d\n" +
+	"\n" +
+	"max_output\x18\a \x01(\x01R\tmaxOutput\x12\x1d\n" +
+	"\n" +
+	"min_output\x18\b \x01(\x01R\tminOutput\"\xcd\x02\n" +
+	"\x19AntiWindupControllerState\x12#\n" +
+	"\rcontrol_error\x18\x01 \x01(\x01R\fcontrolError\x126\n" +
+	"\x17control_error_integrand\x18\x02 \x01(\x01R\x15controlErrorIntegrand\x124\n" +
+	"\x16control_error_integral\x18\x03 \x01(\x01R\x14controlErrorIntegral\x128\n" +
+	"\x18control_error_derivative\x18\x04 \x01(\x01R\x16controlErrorDerivative\x12%\n" +
+	"\x0econtrol_signal\x18\x05 \x01(\x01R\rcontrolSignal\x12<\n" +
+	"\x1aunsaturated_control_signal\x18\x06 \x01(\x01R\x18unsaturatedControlSignal\"\x97\x03\n" +
+	"\x18TrackingControllerConfig\x12+\n" +
+	"\x11proportional_gain\x18\x01 \x01(\x01R\x10proportionalGain\x12#\n" +
+	"\rintegral_gain\x18\x02 \x01(\x01R\fintegralGain\x12'\n" +
+	"\x0fderivative_gain\x18\x03 \x01(\x01R\x0ederivativeGain\x12)\n" +
+	"\x11anti_wind_up_gain\x18\x04 \x01(\x01R\x0eantiWindUpGain\x12G\n" +
+	" integral_discharge_time_constant\x18\x05 \x01(\x01R\x1dintegralDischargeTimeConstant\x12N\n" +
+	"\x16low_pass_time_constant\x18\x06 \x01(\v2\x19.google.protobuf.DurationR\x13lowPassTimeConstant\x12\x1d\n" +
+	"\n" +
+	"max_output\x18\a \x01(\x01R\tmaxOutput\x12\x1d\n" +
+	"\n" +
+	"min_output\x18\b \x01(\x01R\tminOutput\"\xcb\x02\n" +
+	"\x17TrackingControllerState\x12#\n" +
+	"\rcontrol_error\x18\x01 \x01(\x01R\fcontrolError\x126\n" +
+	"\x17control_error_integrand\x18\x02 \x01(\x01R\x15controlErrorIntegrand\x124\n" +
+	"\x16control_error_integral\x18\x03 \x01(\x01R\x14controlErrorIntegral\x128\n" +
+	"\x18control_error_derivative\x18\x04 \x01(\x01R\x16controlErrorDerivative\x12%\n" +
+	"\x0econtrol_signal\x18\x05 \x01(\x01R\rcontrolSignal\x12<\n" +
+	"\x1aunsaturated_control_signal\x18\x06 \x01(\x01R\x18unsaturatedControlSignalB\x1bZ\x19go.einride.tech/pid/pidpbb\x06proto3"
+
+var (
+	file_einride_pid_v1_pid_proto_rawDescOnce sync.Once
+	file_einride_pid_v1_pid_proto_rawDescData []byte
+)
+
+func file_einride_pid_v1_pid_proto_rawDescGZIP() []byte {
+	file_einride_pid_v1_pid_proto_rawDescOnce.Do(func() {
+		file_einride_pid_v1_pid_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_einride_pid_v1_pid_proto_rawDesc), len(file_einride_pid_v1_pid_proto_rawDesc)))
+	})
+	return file_einride_pid_v1_pid_proto_rawDescData
+}
+
+var file_einride_pid_v1_pid_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_einride_pid_v1_pid_proto_goTypes = []any{
+	(*ControllerConfig)(nil),           // 0: einride.pid.v1.ControllerConfig
+	(*ControllerState)(nil),            // 1: einride.pid.v1.ControllerState
+	(*AntiWindupControllerConfig)(nil), // 2: einride.pid.v1.AntiWindupControllerConfig
+	(*AntiWindupControllerState)(nil),  // 3: einride.pid.v1.AntiWindupControllerState
+	(*TrackingControllerConfig)(nil),   // 4: einride.pid.v1.TrackingControllerConfig
+	(*TrackingControllerState)(nil),    // 5: einride.pid.v1.TrackingControllerState
+	(*durationpb.Duration)(nil),        // 6: google.protobuf.Duration
+}
+var file_einride_pid_v1_pid_proto_depIdxs = []int32{
+	6, // 0: einride.pid.v1.AntiWindupControllerConfig.low_pass_time_constant:type_name -> google.protobuf.Duration
+	6, // 1: einride.pid.v1.TrackingControllerConfig.low_pass_time_constant:type_name -> google.protobuf.Duration
+	2, // [2:2] is the sub-list for method output_type
+	2, // [2:2] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_einride_pid_v1_pid_proto_init() }
+func file_einride_pid_v1_pid_proto_init() {
+	if File_einride_pid_v1_pid_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_einride_pid_v1_pid_proto_rawDesc), len(file_einride_pid_v1_pid_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   6,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_einride_pid_v1_pid_proto_goTypes,
+		DependencyIndexes: file_einride_pid_v1_pid_proto_depIdxs,
+		MessageInfos:      file_einride_pid_v1_pid_proto_msgTypes,
+	}.Build()
+	File_einride_pid_v1_pid_proto = out.File
+	file_einride_pid_v1_pid_proto_goTypes = nil
+	file_einride_pid_v1_pid_proto_depIdxs = nil
+}
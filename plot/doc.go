@@ -0,0 +1,5 @@
+// Package plot renders time series, such as traces of control loops, as standalone SVG images.
+//
+// A Figure has one or more Axes stacked vertically that share the x axis. Only the standard library is used, so
+// the package can be used by tools without external plotting software.
+package plot
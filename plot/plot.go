@@ -0,0 +1,389 @@
+package plot
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Figure is a figure with vertically stacked axes sharing the x axis.
+type Figure struct {
+	// Title of the figure.
+	Title string
+	// Width of the figure in pixels. Defaults to 800.
+	Width int
+	// AxesHeight is the height of each axes in pixels. Defaults to 200.
+	AxesHeight int
+	// XLabel is the label of the shared x axis.
+	XLabel string
+	// Axes of the figure, from top to bottom.
+	Axes []Axes
+}
+
+// Axes is a plot area with a y axis.
+type Axes struct {
+	// YLabel is the label of the y axis.
+	YLabel string
+	// Series are the data series to draw.
+	Series []Series
+	// Lines are horizontal reference lines, such as saturation limits.
+	Lines []Line
+	// Annotations are labelled markers of points, such as overshoot peaks.
+	Annotations []Annotation
+}
+
+// Series is a data series drawn as a line.
+type Series struct {
+	// Name of the series, shown in the legend.
+	Name string
+	// X holds the x coordinates of the data points.
+	X []float64
+	// Y holds the y coordinates of the data points.
+	Y []float64
+	// Color is an SVG color. Defaults to a color from the default palette.
+	Color string
+	// Dashed draws the series with a dashed line.
+	Dashed bool
+}
+
+// Line is a horizontal reference line.
+type Line struct {
+	// Label of the line, drawn at its right end.
+	Label string
+	// Y coordinate of the line.
+	Y float64
+	// Color is an SVG color. Defaults to gray.
+	Color string
+}
+
+// Annotation is a labelled marker of a point.
+type Annotation struct {
+	// X coordinate of the point.
+	X float64
+	// Y coordinate of the point.
+	Y float64
+	// Text of the label.
+	Text string
+}
+
+// palette is the default series color palette.
+var palette = []string{"#1f77b4", "#d62728", "#2ca02c", "#ff7f0e", "#9467bd", "#8c564b", "#e377c2", "#17becf"}
+
+const (
+	defaultWidth      = 800
+	defaultAxesHeight = 200
+	marginLeft        = 70
+	marginRight       = 130
+	marginTop         = 40
+	marginBottom      = 45
+	axesSpacing       = 25
+	tickLength        = 5
+	maxTicks          = 6
+)
+
+// WriteSVG writes the figure as a standalone SVG image.
+func (f Figure) WriteSVG(w io.Writer) error {
+	width, axesHeight := f.Width, f.AxesHeight
+	if width <= 0 {
+		width = defaultWidth
+	}
+	if axesHeight <= 0 {
+		axesHeight = defaultAxesHeight
+	}
+	height := marginTop + len(f.Axes)*axesHeight + max(0, len(f.Axes)-1)*axesSpacing + marginBottom
+	plotWidth := float64(width - marginLeft - marginRight)
+	xMin, xMax := f.xRange()
+	var xTicks []float64
+	for _, x := range ticks(xMin, xMax) {
+		if x >= xMin && x <= xMax {
+			xTicks = append(xTicks, x)
+		}
+	}
+	b := bufio.NewWriter(w)
+	fmt.Fprintf(
+		b,
+		`<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" `+
+			`font-family="sans-serif" font-size="11">`+"\n",
+		width, height, width, height,
+	)
+	fmt.Fprintf(b, `<rect width="%d" height="%d" fill="white"/>`+"\n", width, height)
+	if f.Title != "" {
+		fmt.Fprintf(
+			b, `<text x="%d" y="%d" text-anchor="middle" font-size="14">%s</text>`+"\n",
+			width/2, marginTop/2+5, escape(f.Title),
+		)
+	}
+	for i, axes := range f.Axes {
+		top := float64(marginTop + i*(axesHeight+axesSpacing))
+		r := rect{left: marginLeft, top: top, width: plotWidth, height: float64(axesHeight)}
+		axes.writeSVG(b, r, xMin, xMax, xTicks, i == len(f.Axes)-1)
+	}
+	if f.XLabel != "" {
+		fmt.Fprintf(
+			b, `<text x="%g" y="%d" text-anchor="middle">%s</text>`+"\n",
+			marginLeft+plotWidth/2, height-8, escape(f.XLabel),
+		)
+	}
+	b.WriteString("</svg>\n")
+	return b.Flush()
+}
+
+type rect struct {
+	left, top, width, height float64
+}
+
+func (a Axes) writeSVG(b *bufio.Writer, r rect, xMin, xMax float64, xTicks []float64, xTickLabels bool) {
+	yMin, yMax := a.yRange()
+	yTicks := ticks(yMin, yMax)
+	if len(yTicks) > 0 {
+		yMin, yMax = math.Min(yMin, yTicks[0]), math.Max(yMax, yTicks[len(yTicks)-1])
+	}
+	px := func(x float64) float64 { return r.left + (x-xMin)/(xMax-xMin)*r.width }
+	py := func(y float64) float64 { return r.top + r.height - (y-yMin)/(yMax-yMin)*r.height }
+	// Grid and ticks.
+	for _, x := range xTicks {
+		fmt.Fprintf(
+			b, `<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="#e0e0e0"/>`+"\n",
+			px(x), r.top, px(x), r.top+r.height,
+		)
+		if xTickLabels {
+			fmt.Fprintf(
+				b, `<text x="%.1f" y="%.1f" text-anchor="middle">%s</text>`+"\n",
+				px(x), r.top+r.height+tickLength+12, formatTick(x),
+			)
+		}
+	}
+	for _, y := range yTicks {
+		fmt.Fprintf(
+			b, `<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="#e0e0e0"/>`+"\n",
+			r.left, py(y), r.left+r.width, py(y),
+		)
+		fmt.Fprintf(
+			b, `<text x="%.1f" y="%.1f" text-anchor="end">%s</text>`+"\n",
+			r.left-tickLength-2, py(y)+4, formatTick(y),
+		)
+	}
+	fmt.Fprintf(
+		b, `<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="none" stroke="black"/>`+"\n",
+		r.left, r.top, r.width, r.height,
+	)
+	if a.YLabel != "" {
+		x, y := r.left-50, r.top+r.height/2
+		fmt.Fprintf(
+			b, `<text x="%.1f" y="%.1f" text-anchor="middle" transform="rotate(-90 %.1f %.1f)">%s</text>`+"\n",
+			x, y, x, y, escape(a.YLabel),
+		)
+	}
+	// Clip the data to the plot area. The ID is unique per plot area geometry, so that IDs of identical clip paths
+	// in multiple SVG images embedded in the same document can collide harmlessly.
+	clipID := fmt.Sprintf("clip-%.0f-%.0f-%.0f-%.0f", r.left, r.top, r.width, r.height)
+	fmt.Fprintf(
+		b, `<clipPath id="%s"><rect x="%.1f" y="%.1f" width="%.1f" height="%.1f"/></clipPath>`+"\n",
+		clipID, r.left, r.top, r.width, r.height,
+	)
+	fmt.Fprintf(b, `<g clip-path="url(#%s)">`+"\n", clipID)
+	for _, line := range a.Lines {
+		if !isFinite(line.Y) {
+			continue
+		}
+		fmt.Fprintf(
+			b, `<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="%s" stroke-dasharray="2,3"/>`+"\n",
+			r.left, py(line.Y), r.left+r.width, py(line.Y), colorOr(line.Color, "gray"),
+		)
+	}
+	for i, series := range a.Series {
+		dash := ""
+		if series.Dashed {
+			dash = ` stroke-dasharray="6,3"`
+		}
+		fmt.Fprintf(
+			b, `<polyline fill="none" stroke="%s" stroke-width="1.5"%s points="`,
+			colorOr(series.Color, palette[i%len(palette)]), dash,
+		)
+		for j, k := range decimate(series.X, series.Y, xMin, xMax, int(r.width)) {
+			if j > 0 {
+				b.WriteByte(' ')
+			}
+			fmt.Fprintf(b, "%.1f,%.1f", px(series.X[k]), py(series.Y[k]))
+		}
+		b.WriteString(`"/>` + "\n")
+	}
+	b.WriteString("</g>\n")
+	for _, line := range a.Lines {
+		if line.Label != "" && isFinite(line.Y) {
+			fmt.Fprintf(
+				b, `<text x="%.1f" y="%.1f" fill="%s">%s</text>`+"\n",
+				r.left+r.width+4, py(line.Y)+4, colorOr(line.Color, "gray"), escape(line.Label),
+			)
+		}
+	}
+	for _, annotation := range a.Annotations {
+		if !isFinite(annotation.X) || !isFinite(annotation.Y) {
+			continue
+		}
+		x, y := px(annotation.X), py(annotation.Y)
+		fmt.Fprintf(b, `<circle cx="%.1f" cy="%.1f" r="3" fill="none" stroke="black"/>`+"\n", x, y)
+		fmt.Fprintf(b, `<text x="%.1f" y="%.1f">%s</text>`+"\n", x+5, y-5, escape(annotation.Text))
+	}
+	// Legend.
+	for i, series := range a.Series {
+		if series.Name == "" {
+			continue
+		}
+		x, y := r.left+r.width+10, r.top+12+float64(i)*15
+		fmt.Fprintf(
+			b, `<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="%s" stroke-width="2"/>`+"\n",
+			x, y-4, x+15, y-4, colorOr(series.Color, palette[i%len(palette)]),
+		)
+		fmt.Fprintf(b, `<text x="%.1f" y="%.1f">%s</text>`+"\n", x+20, y, escape(series.Name))
+	}
+}
+
+// xRange returns the range of the x coordinates of all series.
+func (f Figure) xRange() (float64, float64) {
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, axes := range f.Axes {
+		for _, series := range axes.Series {
+			for _, x := range series.X {
+				if isFinite(x) {
+					lo, hi = math.Min(lo, x), math.Max(hi, x)
+				}
+			}
+		}
+	}
+	return padRange(lo, hi)
+}
+
+// yRange returns the range of the y coordinates of the axes series, lines and annotations.
+func (a Axes) yRange() (float64, float64) {
+	lo, hi := math.Inf(1), math.Inf(-1)
+	include := func(y float64) {
+		if isFinite(y) {
+			lo, hi = math.Min(lo, y), math.Max(hi, y)
+		}
+	}
+	for _, series := range a.Series {
+		for _, y := range series.Y {
+			include(y)
+		}
+	}
+	for _, line := range a.Lines {
+		include(line.Y)
+	}
+	for _, annotation := range a.Annotations {
+		include(annotation.Y)
+	}
+	return padRange(lo, hi)
+}
+
+// padRange returns a non-empty range for empty and degenerate ranges.
+func padRange(lo, hi float64) (float64, float64) {
+	switch {
+	case lo > hi:
+		return 0, 1
+	case lo == hi:
+		return lo - 0.5, hi + 0.5
+	default:
+		return lo, hi
+	}
+}
+
+// ticks returns evenly spaced tick values with a step of 1, 2 or 5 times a power of 10, within [lo, hi] extended
+// to the nearest ticks.
+func ticks(lo, hi float64) []float64 {
+	rawStep := (hi - lo) / maxTicks
+	magnitude := math.Pow(10, math.Floor(math.Log10(rawStep)))
+	factor := 10.0
+	for _, f := range []float64{1, 2, 5} {
+		if f*magnitude >= rawStep {
+			factor = f
+			break
+		}
+	}
+	step := factor * magnitude
+	first, last := math.Floor(lo/step), math.Ceil(hi/step)
+	result := make([]float64, 0, int(last-first)+1)
+	for i := first; i <= last; i++ {
+		// Divide by the inverse of fractional magnitudes, which is exact, to get the nearest float to each tick.
+		if magnitude < 1 {
+			result = append(result, i*factor/math.Round(1/magnitude)+0)
+		} else {
+			result = append(result, i*factor*magnitude+0)
+		}
+	}
+	return result
+}
+
+func formatTick(x float64) string {
+	if x == 0 {
+		return "0"
+	}
+	return strconv.FormatFloat(x, 'g', 4, 64)
+}
+
+// decimate returns the indices of the data points to draw, keeping the min and max point of each pixel column
+// when there are many more points than pixels, and dropping non-finite points.
+func decimate(x, y []float64, xMin, xMax float64, pixels int) []int {
+	n := min(len(x), len(y))
+	result := make([]int, 0, min(n, 4*pixels))
+	if n <= 4*pixels {
+		for i := range n {
+			if isFinite(x[i]) && isFinite(y[i]) {
+				result = append(result, i)
+			}
+		}
+		return result
+	}
+	column, lo, hi := -1, -1, -1
+	flush := func() {
+		switch {
+		case lo < 0:
+		case lo == hi:
+			result = append(result, lo)
+		case lo < hi:
+			result = append(result, lo, hi)
+		default:
+			result = append(result, hi, lo)
+		}
+	}
+	for i := range n {
+		if !isFinite(x[i]) || !isFinite(y[i]) {
+			continue
+		}
+		c := int((x[i] - xMin) / (xMax - xMin) * float64(pixels))
+		if c != column {
+			flush()
+			column, lo, hi = c, i, i
+			continue
+		}
+		if y[i] < y[lo] {
+			lo = i
+		}
+		if y[i] > y[hi] {
+			hi = i
+		}
+	}
+	flush()
+	return result
+}
+
+func isFinite(x float64) bool {
+	return !math.IsNaN(x) && !math.IsInf(x, 0)
+}
+
+func colorOr(color, fallback string) string {
+	if color == "" {
+		return fallback
+	}
+	return escape(color)
+}
+
+func escape(s string) string {
+	var b strings.Builder
+	_ = xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
@@ -0,0 +1,104 @@
+package plot
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"math"
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+// countElements parses an SVG image and counts its elements by name.
+func countElements(t *testing.T, svg []byte) map[string]int {
+	t.Helper()
+	result := map[string]int{}
+	decoder := xml.NewDecoder(bytes.NewReader(svg))
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			return result
+		}
+		assert.NilError(t, err)
+		if start, ok := token.(xml.StartElement); ok {
+			result[start.Name.Local]++
+		}
+	}
+}
+
+func TestFigure_WriteSVG(t *testing.T) {
+	// Given a figure with two axes
+	f := Figure{
+		Title:  "step <response> & more",
+		XLabel: "time (s)",
+		Axes: []Axes{
+			{
+				YLabel: "signal",
+				Series: []Series{
+					{Name: "reference", X: []float64{0, 1, 2}, Y: []float64{0, 1, 1}, Dashed: true},
+					{Name: "actual", X: []float64{0, 1, 2}, Y: []float64{0, 0.5, math.NaN()}},
+				},
+				Annotations: []Annotation{{X: 1, Y: 0.5, Text: "overshoot"}},
+			},
+			{
+				YLabel: "control",
+				Series: []Series{{Name: "control", X: []float64{0, 1, 2}, Y: []float64{3, 2, 1}}},
+				Lines:  []Line{{Label: "max", Y: 4}, {Label: "min", Y: math.Inf(-1)}},
+			},
+		},
+	}
+	// When writing the figure as SVG
+	var b bytes.Buffer
+	assert.NilError(t, f.WriteSVG(&b))
+	// Then the SVG should be well-formed with the expected elements
+	elements := countElements(t, b.Bytes())
+	assert.Equal(t, 1, elements["svg"])
+	assert.Equal(t, 3, elements["polyline"])
+	assert.Equal(t, 2, elements["clipPath"])
+	assert.Equal(t, 1, elements["circle"])
+	assert.Assert(t, strings.Contains(b.String(), "step &lt;response&gt; &amp; more"))
+	// And non-finite points should be skipped
+	assert.Assert(t, !strings.Contains(b.String(), "NaN"))
+	assert.Assert(t, !strings.Contains(b.String(), "Inf"))
+}
+
+func TestFigure_WriteSVG_Empty(t *testing.T) {
+	var b bytes.Buffer
+	assert.NilError(t, Figure{Axes: []Axes{{}}}.WriteSVG(&b))
+	assert.Equal(t, 1, countElements(t, b.Bytes())["svg"])
+}
+
+func TestTicks(t *testing.T) {
+	for _, tt := range []struct {
+		lo, hi   float64
+		expected []float64
+	}{
+		{lo: 0, hi: 1, expected: []float64{0, 0.2, 0.4, 0.6, 0.8, 1}},
+		{lo: -3, hi: 7, expected: []float64{-4, -2, 0, 2, 4, 6, 8}},
+		{lo: 0.1, hi: 0.35, expected: []float64{0.1, 0.15, 0.2, 0.25, 0.3, 0.35}},
+		{lo: 0, hi: 60, expected: []float64{0, 10, 20, 30, 40, 50, 60}},
+	} {
+		assert.DeepEqual(t, tt.expected, ticks(tt.lo, tt.hi))
+	}
+}
+
+func TestDecimate(t *testing.T) {
+	// Given many more points than pixels
+	x, y := make([]float64, 1000), make([]float64, 1000)
+	for i := range x {
+		x[i] = float64(i)
+		y[i] = math.Sin(float64(i))
+	}
+	// When decimating
+	indices := decimate(x, y, 0, 999, 10)
+	// Then at most the min and max point of each pixel column should be kept
+	assert.Assert(t, len(indices) <= 2*11)
+	for i := 1; i < len(indices); i++ {
+		assert.Assert(t, indices[i] > indices[i-1])
+	}
+	// And few points should be kept as is
+	assert.DeepEqual(t, []int{0, 1, 2}, decimate(x[:3], y[:3], 0, 2, 10))
+}
@@ -0,0 +1,121 @@
+package plot
+
+import (
+	"fmt"
+	"math"
+
+	"go.einride.tech/pid/sim"
+)
+
+// TraceOptions contains options for TraceFigure.
+type TraceOptions struct {
+	// Title of the figure.
+	Title string
+	// MinOutput is the min output of the controller, drawn as a saturation limit.
+	// The limits are only drawn when MinOutput < MaxOutput, and each limit only when it is finite.
+	MinOutput float64
+	// MaxOutput is the max output of the controller, drawn as a saturation limit.
+	MaxOutput float64
+	// SettlingBand is the settling band of the step responses, relative to the step size. Defaults to 0.02.
+	SettlingBand float64
+}
+
+// TraceFigure returns a figure of a control loop trace, with three stacked axes showing the reference and actual
+// signals, the control signal with its saturation limits, and the P, I and D terms of the control signal.
+//
+// The overshoot and settling time of each reference step are annotated.
+func TraceFigure(trace sim.Trace, options TraceOptions) Figure {
+	settlingBand := options.SettlingBand
+	if settlingBand == 0 {
+		settlingBand = 0.02
+	}
+	n := len(trace.Samples)
+	t := make([]float64, n)
+	column := func(field func(sim.Sample) float64) []float64 {
+		result := make([]float64, n)
+		for i, sample := range trace.Samples {
+			result[i] = field(sample)
+		}
+		return result
+	}
+	for i, sample := range trace.Samples {
+		t[i] = sample.Time.Seconds()
+	}
+	signals := Axes{
+		YLabel: "signal",
+		Series: []Series{
+			{Name: "reference", X: t, Y: column(func(s sim.Sample) float64 { return s.ReferenceSignal }), Dashed: true},
+			{Name: "actual", X: t, Y: column(func(s sim.Sample) float64 { return s.ActualSignal })},
+		},
+	}
+	for _, response := range trace.StepResponses(settlingBand) {
+		if response.Overshoot > 0 {
+			peak := peakAfter(trace, response)
+			signals.Annotations = append(signals.Annotations, Annotation{
+				X:    peak.Time.Seconds(),
+				Y:    peak.ActualSignal,
+				Text: fmt.Sprintf("overshoot %.1f%%", 100*response.Overshoot),
+			})
+		}
+		if response.Settled {
+			settled := response.Time + response.SettlingTime
+			signals.Annotations = append(signals.Annotations, Annotation{
+				X:    settled.Seconds(),
+				Y:    response.To,
+				Text: fmt.Sprintf("settled %v", response.SettlingTime),
+			})
+		}
+	}
+	control := Axes{
+		YLabel: "control",
+		Series: []Series{
+			{Name: "control", X: t, Y: column(func(s sim.Sample) float64 { return s.ControlSignal })},
+			{
+				Name:   "unsaturated",
+				X:      t,
+				Y:      column(func(s sim.Sample) float64 { return s.UnsaturatedControlSignal }),
+				Dashed: true,
+			},
+		},
+	}
+	if options.MinOutput < options.MaxOutput {
+		if isFinite(options.MaxOutput) {
+			control.Lines = append(control.Lines, Line{Label: "max", Y: options.MaxOutput, Color: "#d62728"})
+		}
+		if isFinite(options.MinOutput) {
+			control.Lines = append(control.Lines, Line{Label: "min", Y: options.MinOutput, Color: "#d62728"})
+		}
+	}
+	terms := Axes{
+		YLabel: "terms",
+		Series: []Series{
+			{Name: "P", X: t, Y: column(func(s sim.Sample) float64 { return s.ProportionalTerm })},
+			{Name: "I", X: t, Y: column(func(s sim.Sample) float64 { return s.IntegralTerm })},
+			{Name: "D", X: t, Y: column(func(s sim.Sample) float64 { return s.DerivativeTerm })},
+			{Name: "FF", X: t, Y: column(func(s sim.Sample) float64 { return s.FeedForwardSignal })},
+		},
+	}
+	return Figure{
+		Title:  options.Title,
+		XLabel: "time (s)",
+		Axes:   []Axes{signals, control, terms},
+	}
+}
+
+// peakAfter returns the sample with the largest excursion in the direction of the step response.
+func peakAfter(trace sim.Trace, response sim.StepResponse) sim.Sample {
+	var peak sim.Sample
+	excursion := math.Inf(-1)
+	for _, sample := range trace.Samples {
+		if sample.Time < response.Time {
+			continue
+		}
+		if sample.ReferenceSignal != response.To {
+			break
+		}
+		if e := (sample.ActualSignal - response.To) / (response.To - response.From); e > excursion {
+			peak, excursion = sample, e
+		}
+	}
+	return peak
+}
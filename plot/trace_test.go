@@ -0,0 +1,49 @@
+package plot
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"go.einride.tech/pid"
+	"go.einride.tech/pid/model"
+	"go.einride.tech/pid/sim"
+	"gotest.tools/v3/assert"
+)
+
+func TestTraceFigure(t *testing.T) {
+	// Given a trace of a step response with overshoot
+	trace, err := sim.Simulate(sim.Config{
+		Controller: sim.NewAntiWindupController(pid.AntiWindupControllerConfig{
+			ProportionalGain:    2,
+			IntegralGain:        1,
+			AntiWindUpGain:      0.5,
+			LowPassTimeConstant: 100 * time.Millisecond,
+			MaxOutput:           2,
+			MinOutput:           -2,
+		}),
+		Plant:            model.FOPDT{Gain: 1, TimeConstant: 2 * time.Second, DeadTime: 500 * time.Millisecond},
+		SamplingInterval: 50 * time.Millisecond,
+		Duration:         30 * time.Second,
+		Reference:        []sim.Step{{Time: time.Second, Value: 1}},
+	})
+	assert.NilError(t, err)
+	// When creating a figure of the trace
+	f := TraceFigure(trace, TraceOptions{Title: "step", MinOutput: -2, MaxOutput: 2})
+	// Then the figure should have stacked axes for signals, control signal and terms
+	assert.Equal(t, 3, len(f.Axes))
+	assert.Equal(t, 2, len(f.Axes[1].Lines))
+	// And the overshoot and settling time should be annotated
+	assert.Equal(t, 2, len(f.Axes[0].Annotations))
+	assert.Assert(t, strings.HasPrefix(f.Axes[0].Annotations[0].Text, "overshoot"))
+	assert.Assert(t, strings.HasPrefix(f.Axes[0].Annotations[1].Text, "settled"))
+	var b bytes.Buffer
+	assert.NilError(t, f.WriteSVG(&b))
+	assert.Equal(t, 8, countElements(t, b.Bytes())["polyline"])
+}
+
+func TestTraceFigure_NoLimits(t *testing.T) {
+	f := TraceFigure(sim.Trace{}, TraceOptions{})
+	assert.Equal(t, 0, len(f.Axes[1].Lines))
+}
@@ -0,0 +1,239 @@
+package promexport
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ContentType is the content type of the Prometheus text exposition format.
+const ContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+const defaultNamespace = "pid"
+
+// Collector collects the metrics of registered controllers and serves them in the Prometheus text exposition
+// format. The zero value is ready to use.
+//
+// Every metric has a controller label with the name of the controller.
+type Collector struct {
+	// Namespace is the prefix of the metric names. Defaults to "pid" when empty.
+	Namespace string
+
+	mu          sync.Mutex
+	controllers []*ControllerMetrics
+}
+
+var _ http.Handler = &Collector{}
+
+// Register a controller with the name, and return its metrics to observe updates with.
+//
+// The period is the nominal sampling interval of the control loop, used to compute jitter. Jitter metrics are not
+// exported for controllers with a zero period.
+func (c *Collector) Register(name string, period time.Duration) (*ControllerMetrics, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	i, found := slices.BinarySearchFunc(c.controllers, name, func(m *ControllerMetrics, name string) int {
+		return strings.Compare(m.name, name)
+	})
+	if found {
+		return nil, fmt.Errorf("promexport: register %q: already registered", name)
+	}
+	m := &ControllerMetrics{name: name, period: period}
+	c.controllers = slices.Insert(c.controllers, i, m)
+	return m, nil
+}
+
+// Write the metrics of all registered controllers in the Prometheus text exposition format.
+func (c *Collector) Write(w io.Writer) error {
+	namespace := c.Namespace
+	if namespace == "" {
+		namespace = defaultNamespace
+	}
+	if !isValidMetricName(namespace) {
+		return fmt.Errorf("promexport: write: invalid namespace %q", namespace)
+	}
+	c.mu.Lock()
+	controllers := slices.Clone(c.controllers)
+	c.mu.Unlock()
+	snapshots := make([]metricsSnapshot, 0, len(controllers))
+	for _, m := range controllers {
+		snapshots = append(snapshots, m.load())
+	}
+	b := bufio.NewWriter(w)
+	for _, f := range families {
+		name := namespace + "_" + f.name
+		_, _ = fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s %s\n", name, f.help, name, f.kind)
+		for i, m := range controllers {
+			for _, s := range f.samples(snapshots[i], m.period) {
+				_, _ = fmt.Fprintf(b, "%s{controller=\"%s\"%s} %s\n", name, escape(m.name), s.labels, format(s.value))
+			}
+		}
+	}
+	return b.Flush()
+}
+
+// ServeHTTP implements http.Handler by writing the metrics.
+func (c *Collector) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	var b strings.Builder
+	if err := c.Write(&b); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", ContentType)
+	_, _ = io.WriteString(w, b.String())
+}
+
+// family is a metric family.
+type family struct {
+	name    string
+	help    string
+	kind    string
+	samples func(s metricsSnapshot, period time.Duration) []sample
+}
+
+// sample is a sample of a metric family, with labels in addition to the controller label.
+type sample struct {
+	labels string
+	value  float64
+}
+
+func single(value func(s metricsSnapshot) float64) func(metricsSnapshot, time.Duration) []sample {
+	return func(s metricsSnapshot, _ time.Duration) []sample {
+		return []sample{{value: value(s)}}
+	}
+}
+
+func withPeriod(value func(s metricsSnapshot) float64) func(metricsSnapshot, time.Duration) []sample {
+	return func(s metricsSnapshot, period time.Duration) []sample {
+		if period == 0 {
+			return nil
+		}
+		return []sample{{value: value(s)}}
+	}
+}
+
+var families = []family{
+	{
+		name:    "control_error",
+		help:    "Control error of the latest update.",
+		kind:    "gauge",
+		samples: single(func(s metricsSnapshot) float64 { return s.observation.ControlError }),
+	},
+	{
+		name:    "control_signal",
+		help:    "Control signal of the latest update.",
+		kind:    "gauge",
+		samples: single(func(s metricsSnapshot) float64 { return s.observation.ControlSignal }),
+	},
+	{
+		name:    "unsaturated_control_signal",
+		help:    "Control signal before saturation of the latest update.",
+		kind:    "gauge",
+		samples: single(func(s metricsSnapshot) float64 { return s.observation.UnsaturatedControlSignal }),
+	},
+	{
+		name: "term",
+		help: "PID terms of the control signal of the latest update.",
+		kind: "gauge",
+		samples: func(s metricsSnapshot, _ time.Duration) []sample {
+			return []sample{
+				{labels: `,term="proportional"`, value: s.observation.ProportionalTerm},
+				{labels: `,term="integral"`, value: s.observation.IntegralTerm},
+				{labels: `,term="derivative"`, value: s.observation.DerivativeTerm},
+			}
+		},
+	},
+	{
+		name:    "updates_total",
+		help:    "Number of updates.",
+		kind:    "counter",
+		samples: single(func(s metricsSnapshot) float64 { return float64(s.updates) }),
+	},
+	{
+		name:    "invalid_inputs_total",
+		help:    "Number of updates with inputs rejected by the controller.",
+		kind:    "counter",
+		samples: single(func(s metricsSnapshot) float64 { return float64(s.invalidInputs) }),
+	},
+	{
+		name:    "running_seconds_total",
+		help:    "Sum of the sampling intervals of all updates.",
+		kind:    "counter",
+		samples: single(func(s metricsSnapshot) float64 { return s.runningTime }),
+	},
+	{
+		name:    "saturated_seconds_total",
+		help:    "Sum of the sampling intervals of updates with a saturated control signal.",
+		kind:    "counter",
+		samples: single(func(s metricsSnapshot) float64 { return s.saturatedTime }),
+	},
+	{
+		name: "saturation_time_ratio",
+		help: "Ratio of the running time with a saturated control signal.",
+		kind: "gauge",
+		samples: single(func(s metricsSnapshot) float64 {
+			if s.runningTime == 0 {
+				return 0
+			}
+			return s.saturatedTime / s.runningTime
+		}),
+	},
+	{
+		name:    "sampling_interval_seconds",
+		help:    "Sampling interval of the latest update.",
+		kind:    "gauge",
+		samples: single(func(s metricsSnapshot) float64 { return s.samplingInterval }),
+	},
+	{
+		name:    "jitter_seconds",
+		help:    "Absolute deviation of the latest sampling interval from the nominal period.",
+		kind:    "gauge",
+		samples: withPeriod(func(s metricsSnapshot) float64 { return s.jitter }),
+	},
+	{
+		name:    "max_jitter_seconds",
+		help:    "Largest absolute deviation of a sampling interval from the nominal period.",
+		kind:    "gauge",
+		samples: withPeriod(func(s metricsSnapshot) float64 { return s.maxJitter }),
+	},
+}
+
+// format a sample value as in the Prometheus text exposition format.
+func format(x float64) string {
+	switch {
+	case math.IsNaN(x):
+		return "NaN"
+	case math.IsInf(x, 1):
+		return "+Inf"
+	case math.IsInf(x, -1):
+		return "-Inf"
+	default:
+		return strconv.FormatFloat(x, 'g', -1, 64)
+	}
+}
+
+var labelValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// escape a label value as in the Prometheus text exposition format.
+func escape(s string) string {
+	return labelValueEscaper.Replace(s)
+}
+
+func isValidMetricName(s string) bool {
+	for i, r := range s {
+		switch {
+		case r == '_' || r == ':' || 'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z':
+		case i > 0 && '0' <= r && r <= '9':
+		default:
+			return false
+		}
+	}
+	return s != ""
+}
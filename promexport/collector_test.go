@@ -0,0 +1,97 @@
+package promexport
+
+import (
+	"io"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestCollector_Write(t *testing.T) {
+	// Given a collector with two controllers
+	collector := Collector{Namespace: "vehicle_pid"}
+	speed, err := collector.Register("speed", 10*time.Millisecond)
+	assert.NilError(t, err)
+	steering, err := collector.Register(`steer"ing`, 0)
+	assert.NilError(t, err)
+	// When observing updates
+	speed.Observe(Observation{
+		ControlError:             0.5,
+		ControlSignal:            1,
+		UnsaturatedControlSignal: 1.5,
+		ProportionalTerm:         1,
+		IntegralTerm:             0.25,
+		DerivativeTerm:           0.25,
+		SamplingInterval:         12 * time.Millisecond,
+	})
+	speed.Observe(Observation{InvalidInput: true, SamplingInterval: 8 * time.Millisecond})
+	steering.Observe(Observation{ControlSignal: math.Inf(1), UnsaturatedControlSignal: math.Inf(1)})
+	var b strings.Builder
+	assert.NilError(t, collector.Write(&b))
+	// Then the metrics should be written in the text exposition format, sorted by controller name
+	expected := `# HELP vehicle_pid_control_error Control error of the latest update.
+# TYPE vehicle_pid_control_error gauge
+vehicle_pid_control_error{controller="speed"} 0
+vehicle_pid_control_error{controller="steer\"ing"} 0
+# HELP vehicle_pid_control_signal Control signal of the latest update.
+# TYPE vehicle_pid_control_signal gauge
+vehicle_pid_control_signal{controller="speed"} 0
+vehicle_pid_control_signal{controller="steer\"ing"} +Inf
+`
+	assert.Assert(t, strings.HasPrefix(b.String(), expected), b.String())
+	for _, line := range []string{
+		`vehicle_pid_term{controller="speed",term="proportional"} 0`,
+		`vehicle_pid_updates_total{controller="speed"} 2`,
+		`vehicle_pid_invalid_inputs_total{controller="speed"} 1`,
+		`vehicle_pid_invalid_inputs_total{controller="steer\"ing"} 0`,
+		`vehicle_pid_running_seconds_total{controller="speed"} 0.02`,
+		`vehicle_pid_saturated_seconds_total{controller="speed"} 0.012`,
+		`vehicle_pid_saturation_time_ratio{controller="speed"} 0.6`,
+		`vehicle_pid_saturation_time_ratio{controller="steer\"ing"} 0`,
+		`vehicle_pid_sampling_interval_seconds{controller="speed"} 0.008`,
+		`vehicle_pid_jitter_seconds{controller="speed"} 0.002`,
+		`vehicle_pid_max_jitter_seconds{controller="speed"} 0.002`,
+		"# TYPE vehicle_pid_updates_total counter",
+	} {
+		assert.Assert(t, strings.Contains(b.String(), line+"\n"), line)
+	}
+	// And jitter should only be exported for controllers with a period
+	assert.Assert(t, !strings.Contains(b.String(), `vehicle_pid_jitter_seconds{controller="steer\"ing"}`))
+}
+
+func TestCollector_Register_Duplicate(t *testing.T) {
+	var collector Collector
+	_, err := collector.Register("speed", 0)
+	assert.NilError(t, err)
+	_, err = collector.Register("speed", 0)
+	assert.ErrorContains(t, err, `promexport: register "speed": already registered`)
+}
+
+func TestCollector_Write_InvalidNamespace(t *testing.T) {
+	collector := Collector{Namespace: "1pid"}
+	assert.ErrorContains(t, collector.Write(io.Discard), `invalid namespace "1pid"`)
+}
+
+func TestCollector_ServeHTTP(t *testing.T) {
+	// Given a collector with a controller
+	var collector Collector
+	_, err := collector.Register("speed", 0)
+	assert.NilError(t, err)
+	server := httptest.NewServer(&collector)
+	defer server.Close()
+	// When scraping the metrics
+	response, err := http.Get(server.URL)
+	assert.NilError(t, err)
+	defer response.Body.Close()
+	body, err := io.ReadAll(response.Body)
+	assert.NilError(t, err)
+	// Then
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, ContentType, response.Header.Get("Content-Type"))
+	assert.Assert(t, strings.Contains(string(body), `pid_updates_total{controller="speed"} 0`))
+}
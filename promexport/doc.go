@@ -0,0 +1,7 @@
+// Package promexport exports the health of control loops as metrics in the Prometheus text exposition format.
+//
+// Each controller is registered with a Collector, and observed after every update. The Collector serves the
+// latest control error, control signal and PID terms of each controller, together with counters of updates,
+// invalid inputs and time spent in saturation, and the sampling interval jitter of the loop. The package
+// implements the text format directly and has no dependencies on Prometheus client libraries.
+package promexport
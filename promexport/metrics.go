@@ -0,0 +1,141 @@
+package promexport
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"go.einride.tech/pid"
+)
+
+// ControllerMetrics collects the metrics of a controller. It is safe for concurrent use.
+type ControllerMetrics struct {
+	name   string
+	period time.Duration
+
+	mu       sync.Mutex
+	snapshot metricsSnapshot
+}
+
+// metricsSnapshot holds the metric values of a controller.
+type metricsSnapshot struct {
+	observation      Observation
+	updates          uint64
+	invalidInputs    uint64
+	runningTime      float64
+	saturatedTime    float64
+	samplingInterval float64
+	jitter           float64
+	maxJitter        float64
+}
+
+// Observation is an observed controller update.
+type Observation struct {
+	// ControlError is the control error after the update.
+	ControlError float64
+	// ControlSignal is the control signal after the update.
+	ControlSignal float64
+	// UnsaturatedControlSignal is the control signal before saturation after the update.
+	UnsaturatedControlSignal float64
+	// ProportionalTerm is the P part of the control signal.
+	ProportionalTerm float64
+	// IntegralTerm is the I part of the control signal.
+	IntegralTerm float64
+	// DerivativeTerm is the D part of the control signal.
+	DerivativeTerm float64
+	// InvalidInput is true when the input of the update was rejected by the controller, for example because of a
+	// NaN or infinite signal.
+	InvalidInput bool
+	// SamplingInterval is the sampling interval of the update.
+	SamplingInterval time.Duration
+}
+
+// Saturated returns true when the control signal is saturated.
+func (o Observation) Saturated() bool {
+	return o.ControlSignal != o.UnsaturatedControlSignal
+}
+
+// Name returns the name of the controller.
+func (m *ControllerMetrics) Name() string {
+	return m.name
+}
+
+// Observe records an observed controller update.
+func (m *ControllerMetrics) Observe(o Observation) {
+	dt := o.SamplingInterval.Seconds()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.snapshot.observation = o
+	m.snapshot.updates++
+	if o.InvalidInput {
+		m.snapshot.invalidInputs++
+	}
+	m.snapshot.runningTime += dt
+	if o.Saturated() {
+		m.snapshot.saturatedTime += dt
+	}
+	m.snapshot.samplingInterval = dt
+	if m.period > 0 {
+		m.snapshot.jitter = math.Abs(dt - m.period.Seconds())
+		m.snapshot.maxJitter = math.Max(m.snapshot.maxJitter, m.snapshot.jitter)
+	}
+}
+
+// ObserveController records an update of a Controller with the input. Call it after each call to Update.
+func (m *ControllerMetrics) ObserveController(c *pid.Controller, input pid.ControllerInput) {
+	m.Observe(Observation{
+		ControlError:             c.State.ControlError,
+		ControlSignal:            c.State.ControlSignal,
+		UnsaturatedControlSignal: c.State.ControlSignal,
+		ProportionalTerm:         c.Config.ProportionalGain * c.State.ControlError,
+		IntegralTerm:             c.Config.IntegralGain * c.State.ControlErrorIntegral,
+		DerivativeTerm:           c.Config.DerivativeGain * c.State.ControlErrorDerivative,
+		InvalidInput:             isInvalid(input.ReferenceSignal, input.ActualSignal),
+		SamplingInterval:         input.SamplingInterval,
+	})
+}
+
+// ObserveAntiWindupController records an update of an AntiWindupController with the input. Call it after each
+// call to Update.
+func (m *ControllerMetrics) ObserveAntiWindupController(
+	c *pid.AntiWindupController,
+	input pid.AntiWindupControllerInput,
+) {
+	m.Observe(Observation{
+		ControlError:             c.State.ControlError,
+		ControlSignal:            c.State.ControlSignal,
+		UnsaturatedControlSignal: c.State.UnsaturatedControlSignal,
+		ProportionalTerm:         c.Config.ProportionalGain * c.State.ControlError,
+		IntegralTerm:             c.Config.IntegralGain * c.State.ControlErrorIntegral,
+		DerivativeTerm:           c.Config.DerivativeGain * c.State.ControlErrorDerivative,
+		InvalidInput:             isInvalid(input.ReferenceSignal, input.ActualSignal),
+		SamplingInterval:         input.SamplingInterval,
+	})
+}
+
+// ObserveTrackingController records an update of a TrackingController with the input. Call it after each call to
+// Update.
+func (m *ControllerMetrics) ObserveTrackingController(c *pid.TrackingController, input pid.TrackingControllerInput) {
+	m.Observe(Observation{
+		ControlError:             c.State.ControlError,
+		ControlSignal:            c.State.ControlSignal,
+		UnsaturatedControlSignal: c.State.UnsaturatedControlSignal,
+		ProportionalTerm:         c.Config.ProportionalGain * c.State.ControlError,
+		IntegralTerm:             c.Config.IntegralGain * c.State.ControlErrorIntegral,
+		DerivativeTerm:           c.Config.DerivativeGain * c.State.ControlErrorDerivative,
+		InvalidInput:             isInvalid(input.ReferenceSignal, input.ActualSignal),
+		SamplingInterval:         input.SamplingInterval,
+	})
+}
+
+func (m *ControllerMetrics) load() metricsSnapshot {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.snapshot
+}
+
+// isInvalid returns true when the controllers reject the input signals.
+func isInvalid(referenceSignal, actualSignal float64) bool {
+	return math.IsNaN(referenceSignal) || math.IsInf(referenceSignal, 0) ||
+		math.IsNaN(actualSignal) || math.IsInf(actualSignal, 0)
+}
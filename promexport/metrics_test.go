@@ -0,0 +1,87 @@
+package promexport
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"go.einride.tech/pid"
+	"gotest.tools/v3/assert"
+)
+
+func TestControllerMetrics_ObserveAntiWindupController(t *testing.T) {
+	// Given a saturating PID controller with metrics
+	var collector Collector
+	m, err := collector.Register("speed", 10*time.Millisecond)
+	assert.NilError(t, err)
+	c := &pid.AntiWindupController{
+		Config: pid.AntiWindupControllerConfig{
+			ProportionalGain:    2,
+			IntegralGain:        1,
+			DerivativeGain:      0.5,
+			LowPassTimeConstant: time.Second,
+			MinOutput:           -1,
+			MaxOutput:           1,
+		},
+	}
+	// When observing a saturated update
+	input := pid.AntiWindupControllerInput{ReferenceSignal: 1, SamplingInterval: 12 * time.Millisecond}
+	c.Update(input)
+	m.ObserveAntiWindupController(c, input)
+	// And an invalid update
+	invalid := pid.AntiWindupControllerInput{ReferenceSignal: math.NaN(), SamplingInterval: 9 * time.Millisecond}
+	c.Update(invalid)
+	m.ObserveAntiWindupController(c, invalid)
+	// Then the terms should sum to the unsaturated control signal
+	s := m.load()
+	assert.Equal(
+		t,
+		c.State.UnsaturatedControlSignal,
+		s.observation.ProportionalTerm+s.observation.IntegralTerm+s.observation.DerivativeTerm,
+	)
+	assert.Equal(t, 1.0, s.observation.ControlSignal)
+	// And the counters should be updated
+	assert.Equal(t, uint64(2), s.updates)
+	assert.Equal(t, uint64(1), s.invalidInputs)
+	assert.Assert(t, math.Abs(s.runningTime-0.021) < 1e-12)
+	assert.Assert(t, math.Abs(s.saturatedTime-0.021) < 1e-12)
+	// And the jitter should be the deviation from the period
+	assert.Assert(t, math.Abs(s.jitter-0.001) < 1e-12)
+	assert.Assert(t, math.Abs(s.maxJitter-0.002) < 1e-12)
+}
+
+func TestControllerMetrics_ObserveController(t *testing.T) {
+	// Given a PI controller with metrics
+	var collector Collector
+	m, err := collector.Register("position", 0)
+	assert.NilError(t, err)
+	c := &pid.Controller{Config: pid.ControllerConfig{ProportionalGain: 1, IntegralGain: 1}}
+	// When observing an update
+	input := pid.ControllerInput{ReferenceSignal: 1, SamplingInterval: time.Second}
+	c.Update(input)
+	m.ObserveController(c, input)
+	// Then the controller should never be saturated
+	s := m.load()
+	assert.Equal(t, 2.0, s.observation.ControlSignal)
+	assert.Equal(t, 0.0, s.saturatedTime)
+	// And jitter should not be measured without a period
+	assert.Equal(t, 0.0, s.jitter)
+}
+
+func TestControllerMetrics_ObserveTrackingController(t *testing.T) {
+	// Given a P controller with metrics
+	var collector Collector
+	m, err := collector.Register("steering", 0)
+	assert.NilError(t, err)
+	c := &pid.TrackingController{
+		Config: pid.TrackingControllerConfig{ProportionalGain: 3, LowPassTimeConstant: time.Second, MaxOutput: 10},
+	}
+	// When observing an update
+	input := pid.TrackingControllerInput{ReferenceSignal: 1, SamplingInterval: time.Second}
+	c.Update(input)
+	m.ObserveTrackingController(c, input)
+	// Then the P term should be observed
+	s := m.load()
+	assert.Equal(t, 3.0, s.observation.ProportionalTerm)
+	assert.Equal(t, "steering", m.Name())
+}
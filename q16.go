@@ -0,0 +1,105 @@
+package pid
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// Q16 is a signed fixed-point number in Q15.16 format, with 16 integer bits including the sign bit and 16
+// fractional bits, giving a range of [-32768, 32768) with a resolution of 2^-16.
+//
+// All arithmetic saturates to the range of Q16 instead of overflowing, and rounds to the nearest representable
+// value with ties rounded away from zero, so that results are deterministic across platforms. No floating-point
+// operations are used, which makes Q16 suitable for microcontrollers without an FPU.
+type Q16 int32
+
+const (
+	// Q16FractionalBits is the number of fractional bits of a Q16.
+	Q16FractionalBits = 16
+	// Q16One is the Q16 representation of 1.
+	Q16One Q16 = 1 << Q16FractionalBits
+	// Q16Max is the largest Q16.
+	Q16Max Q16 = math.MaxInt32
+	// Q16Min is the smallest Q16.
+	Q16Min Q16 = math.MinInt32
+)
+
+// Q16FromFloat converts a float to the nearest Q16, saturating values outside of the Q16 range.
+func Q16FromFloat(x float64) Q16 {
+	if math.IsNaN(x) {
+		return 0
+	}
+	// Clamp before converting to an integer, since converting out of range floats is implementation-defined.
+	const limit = 1 << 40
+	return saturateQ16(int64(math.Round(math.Max(-limit, math.Min(limit, x*float64(Q16One))))))
+}
+
+// Q16FromInt converts an integer to a Q16, saturating values outside of the Q16 range.
+func Q16FromInt(x int) Q16 {
+	return saturateQ16(int64(max(min(x, math.MaxInt32), math.MinInt32)) << Q16FractionalBits)
+}
+
+// Q16FromDuration converts a duration to a Q16 number of seconds.
+func Q16FromDuration(d time.Duration) Q16 {
+	seconds := max(min(int64(d/time.Second), math.MaxInt32), math.MinInt32)
+	fraction := divRound(int64(d%time.Second)<<Q16FractionalBits, int64(time.Second))
+	return saturateQ16(seconds<<Q16FractionalBits + fraction)
+}
+
+// Float returns the float representation of q.
+func (q Q16) Float() float64 {
+	return float64(q) / float64(Q16One)
+}
+
+// String implements fmt.Stringer.
+func (q Q16) String() string {
+	return fmt.Sprint(q.Float())
+}
+
+// Add returns the saturated sum q+r.
+func (q Q16) Add(r Q16) Q16 {
+	return saturateQ16(int64(q) + int64(r))
+}
+
+// Sub returns the saturated difference q-r.
+func (q Q16) Sub(r Q16) Q16 {
+	return saturateQ16(int64(q) - int64(r))
+}
+
+// Mul returns the saturated and rounded product q*r.
+func (q Q16) Mul(r Q16) Q16 {
+	return saturateQ16(divRound(int64(q)*int64(r), int64(Q16One)))
+}
+
+// Div returns the saturated and rounded quotient q/r. Division by zero saturates to Q16Max or Q16Min, or
+// returns zero when q is zero.
+func (q Q16) Div(r Q16) Q16 {
+	switch {
+	case r != 0:
+		return saturateQ16(divRound(int64(q)<<Q16FractionalBits, int64(r)))
+	case q > 0:
+		return Q16Max
+	case q < 0:
+		return Q16Min
+	default:
+		return 0
+	}
+}
+
+// Clamp returns q clamped to the range [lo, hi].
+func (q Q16) Clamp(lo, hi Q16) Q16 {
+	return max(lo, min(hi, q))
+}
+
+// divRound returns a/b rounded to the nearest integer with ties rounded away from zero.
+func divRound(a, b int64) int64 {
+	if (a < 0) != (b < 0) {
+		return (a - b/2) / b
+	}
+	return (a + b/2) / b
+}
+
+func saturateQ16(x int64) Q16 {
+	return Q16(max(min(x, math.MaxInt32), math.MinInt32))
+}
@@ -0,0 +1,76 @@
+package pid
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestQ16FromFloat(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		x        float64
+		expected Q16
+	}{
+		{name: "zero", x: 0, expected: 0},
+		{name: "one", x: 1, expected: Q16One},
+		{name: "negative", x: -1.5, expected: -3 * Q16One / 2},
+		{name: "tie rounded away from zero", x: 0.5 / float64(Q16One), expected: 1},
+		{name: "negative tie rounded away from zero", x: -0.5 / float64(Q16One), expected: -1},
+		{name: "saturated max", x: 1e6, expected: Q16Max},
+		{name: "saturated min", x: -1e6, expected: Q16Min},
+		{name: "inf", x: math.Inf(1), expected: Q16Max},
+		{name: "nan", x: math.NaN(), expected: 0},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, Q16FromFloat(tt.x))
+		})
+	}
+}
+
+func TestQ16FromInt(t *testing.T) {
+	assert.Equal(t, 3*Q16One, Q16FromInt(3))
+	assert.Equal(t, -3*Q16One, Q16FromInt(-3))
+	assert.Equal(t, Q16Max, Q16FromInt(1<<20))
+	assert.Equal(t, Q16Min, Q16FromInt(-1<<20))
+}
+
+func TestQ16FromDuration(t *testing.T) {
+	assert.Equal(t, Q16One, Q16FromDuration(time.Second))
+	assert.Equal(t, Q16FromFloat(0.01), Q16FromDuration(10*time.Millisecond))
+	assert.Equal(t, Q16FromFloat(-2.25), Q16FromDuration(-2250*time.Millisecond))
+	assert.Equal(t, Q16Max, Q16FromDuration(24*time.Hour))
+}
+
+func TestQ16_Arithmetic(t *testing.T) {
+	a, b := Q16FromFloat(2.5), Q16FromFloat(-0.75)
+	assert.Equal(t, Q16FromFloat(1.75), a.Add(b))
+	assert.Equal(t, Q16FromFloat(3.25), a.Sub(b))
+	assert.Equal(t, Q16FromFloat(-1.875), a.Mul(b))
+	assert.Equal(t, Q16FromFloat(-2.5/0.75), a.Div(b))
+	assert.Equal(t, Q16FromFloat(1), a.Clamp(-1, Q16One))
+	assert.Equal(t, "2.5", a.String())
+}
+
+func TestQ16_Saturation(t *testing.T) {
+	assert.Equal(t, Q16Max, Q16Max.Add(1))
+	assert.Equal(t, Q16Min, Q16Min.Sub(1))
+	assert.Equal(t, Q16Max, Q16FromInt(1000).Mul(Q16FromInt(1000)))
+	assert.Equal(t, Q16Min, Q16FromInt(-1000).Mul(Q16FromInt(1000)))
+	assert.Equal(t, Q16Max, Q16FromInt(1000).Div(Q16FromFloat(0.001)))
+}
+
+func TestQ16_DivisionByZero(t *testing.T) {
+	assert.Equal(t, Q16Max, Q16One.Div(0))
+	assert.Equal(t, Q16Min, (-Q16One).Div(0))
+	assert.Equal(t, Q16(0), Q16(0).Div(0))
+}
+
+func TestQ16_MulRounding(t *testing.T) {
+	// Given the smallest positive Q16, halving it is a tie that rounds away from zero.
+	half := Q16One / 2
+	assert.Equal(t, Q16(1), Q16(1).Mul(half))
+	assert.Equal(t, Q16(-1), Q16(-1).Mul(half))
+}
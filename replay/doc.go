@@ -0,0 +1,8 @@
+// Package replay records controller updates to a compact binary log, and replays recorded inputs through
+// controllers with different configs.
+//
+// A log records the input and resulting state of every update, so that questions such as what a new tuning would
+// have done on a recorded drive can be answered offline and deterministically. Replay is open loop: the recorded
+// actual signal is the response of the process to the recorded controller, and is not affected by the replayed
+// controller. Closed-loop what-if analysis requires a process model, see package sim.
+package replay
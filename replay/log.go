@@ -0,0 +1,361 @@
+package replay
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"math"
+	"time"
+
+	"go.einride.tech/pid"
+)
+
+// logVersion is the version of the binary log format.
+//
+// The format is fixed-layout and little-endian. A log starts with a header:
+//
+//	[0:4]    magic "PIDL"
+//	[4]      format version
+//	[5]      controller kind
+//	[6:54]   6 IEEE 754 float64 fields of the initial state, in AntiWindupControllerState declaration order
+//	[54:58]  CRC-32 (IEEE) checksum of the preceding header bytes
+//
+// followed by a record for every update:
+//
+//	[0:32]   4 IEEE 754 float64 input signals, in Input declaration order
+//	[32:40]  int64 sampling interval (ns)
+//	[40:88]  6 IEEE 754 float64 fields of the resulting state, in AntiWindupControllerState declaration order
+//	[88:92]  CRC-32 (IEEE) checksum of the preceding record bytes
+const logVersion = 1
+
+const (
+	logMagic        = "PIDL"
+	headerSize      = 58
+	recordSize      = 92
+	checksumSize    = 4
+	stateFieldCount = 6
+)
+
+// Kind is the kind of controller recorded in a log.
+type Kind uint8
+
+const (
+	// KindController is a pid.Controller.
+	KindController Kind = iota + 1
+	// KindAntiWindupController is a pid.AntiWindupController.
+	KindAntiWindupController
+	// KindTrackingController is a pid.TrackingController.
+	KindTrackingController
+)
+
+// String returns the name of the controller kind.
+func (k Kind) String() string {
+	switch k {
+	case KindController:
+		return "Controller"
+	case KindAntiWindupController:
+		return "AntiWindupController"
+	case KindTrackingController:
+		return "TrackingController"
+	default:
+		return fmt.Sprintf("Kind(%d)", uint8(k))
+	}
+}
+
+// Log is a recorded sequence of controller updates.
+type Log struct {
+	// Kind of the recorded controller.
+	Kind Kind
+	// InitialState is the controller state before the first update.
+	//
+	// The states of all controller kinds are recorded as an AntiWindupControllerState. The state of a
+	// pid.Controller has no integrand, and its unsaturated control signal equals its control signal.
+	InitialState pid.AntiWindupControllerState
+	// Records of the updates.
+	Records []Record
+}
+
+// Record is a recorded controller update.
+type Record struct {
+	// Input of the update.
+	Input Input
+	// State after the update.
+	State pid.AntiWindupControllerState
+}
+
+// Input is the input of a recorded update, with the union of the input signals of all controller kinds.
+type Input struct {
+	// ReferenceSignal is the reference value for the signal to control.
+	ReferenceSignal float64
+	// ActualSignal is the actual value of the signal to control.
+	ActualSignal float64
+	// FeedForwardSignal is the contribution of the feed-forward control loop in the controller output.
+	FeedForwardSignal float64
+	// AppliedControlSignal is the actual control command applied by the actuator. For controllers without an
+	// applied control signal input it is recorded as the resulting control signal.
+	AppliedControlSignal float64
+	// SamplingInterval is the time interval elapsed since the previous update.
+	SamplingInterval time.Duration
+}
+
+// Recorder writes controller updates to a binary log.
+type Recorder struct {
+	w    *bufio.Writer
+	kind Kind
+	buf  []byte
+}
+
+// NewRecorder creates a Recorder of a controller of the kind, and writes the log header with the initial state of
+// the controller.
+func NewRecorder(w io.Writer, kind Kind, initialState pid.AntiWindupControllerState) (*Recorder, error) {
+	if kind < KindController || kind > KindTrackingController {
+		return nil, fmt.Errorf("replay: new recorder: invalid kind %v", kind)
+	}
+	r := &Recorder{w: bufio.NewWriter(w), kind: kind, buf: make([]byte, 0, recordSize)}
+	data := make([]byte, 0, headerSize)
+	data = append(data, logMagic...)
+	data = append(data, logVersion, byte(kind))
+	data = appendState(data, initialState)
+	data = binary.LittleEndian.AppendUint32(data, crc32.ChecksumIEEE(data))
+	if _, err := r.w.Write(data); err != nil {
+		return nil, fmt.Errorf("replay: new recorder: %w", err)
+	}
+	return r, nil
+}
+
+// Write a record to the log.
+func (r *Recorder) Write(record Record) error {
+	data := r.buf[:0]
+	for _, field := range [...]float64{
+		record.Input.ReferenceSignal,
+		record.Input.ActualSignal,
+		record.Input.FeedForwardSignal,
+		record.Input.AppliedControlSignal,
+	} {
+		data = binary.LittleEndian.AppendUint64(data, math.Float64bits(field))
+	}
+	data = binary.LittleEndian.AppendUint64(data, uint64(record.Input.SamplingInterval))
+	data = appendState(data, record.State)
+	data = binary.LittleEndian.AppendUint32(data, crc32.ChecksumIEEE(data))
+	if _, err := r.w.Write(data); err != nil {
+		return fmt.Errorf("replay: write record: %w", err)
+	}
+	return nil
+}
+
+// RecordController records an update of a Controller with the input. Call it after each call to Update.
+func (r *Recorder) RecordController(c *pid.Controller, input pid.ControllerInput) error {
+	if r.kind != KindController {
+		return fmt.Errorf("replay: record: Controller in log of %v", r.kind)
+	}
+	return r.Write(Record{
+		Input: Input{
+			ReferenceSignal:      input.ReferenceSignal,
+			ActualSignal:         input.ActualSignal,
+			AppliedControlSignal: c.State.ControlSignal,
+			SamplingInterval:     input.SamplingInterval,
+		},
+		State: stateOfController(c.State),
+	})
+}
+
+// RecordAntiWindupController records an update of an AntiWindupController with the input. Call it after each call
+// to Update.
+func (r *Recorder) RecordAntiWindupController(c *pid.AntiWindupController, input pid.AntiWindupControllerInput) error {
+	if r.kind != KindAntiWindupController {
+		return fmt.Errorf("replay: record: AntiWindupController in log of %v", r.kind)
+	}
+	return r.Write(Record{
+		Input: Input{
+			ReferenceSignal:      input.ReferenceSignal,
+			ActualSignal:         input.ActualSignal,
+			FeedForwardSignal:    input.FeedForwardSignal,
+			AppliedControlSignal: c.State.ControlSignal,
+			SamplingInterval:     input.SamplingInterval,
+		},
+		State: c.State,
+	})
+}
+
+// RecordTrackingController records an update of a TrackingController with the input. Call it after each call to
+// Update.
+func (r *Recorder) RecordTrackingController(c *pid.TrackingController, input pid.TrackingControllerInput) error {
+	if r.kind != KindTrackingController {
+		return fmt.Errorf("replay: record: TrackingController in log of %v", r.kind)
+	}
+	return r.Write(Record{
+		Input: Input{
+			ReferenceSignal:      input.ReferenceSignal,
+			ActualSignal:         input.ActualSignal,
+			FeedForwardSignal:    input.FeedForwardSignal,
+			AppliedControlSignal: input.AppliedControlSignal,
+			SamplingInterval:     input.SamplingInterval,
+		},
+		State: pid.AntiWindupControllerState(c.State),
+	})
+}
+
+// Flush buffered records to the underlying writer.
+func (r *Recorder) Flush() error {
+	if err := r.w.Flush(); err != nil {
+		return fmt.Errorf("replay: flush: %w", err)
+	}
+	return nil
+}
+
+// Reader reads controller updates from a binary log.
+type Reader struct {
+	r            *bufio.Reader
+	kind         Kind
+	initialState pid.AntiWindupControllerState
+	buf          [recordSize]byte
+}
+
+// NewReader creates a Reader and reads the log header.
+func NewReader(r io.Reader) (*Reader, error) {
+	result := &Reader{r: bufio.NewReader(r)}
+	var header [headerSize]byte
+	if _, err := io.ReadFull(result.r, header[:]); err != nil {
+		return nil, fmt.Errorf("replay: read header: %w", err)
+	}
+	if string(header[:len(logMagic)]) != logMagic {
+		return nil, fmt.Errorf("replay: read header: not a log")
+	}
+	if err := verifyChecksum(header[:]); err != nil {
+		return nil, fmt.Errorf("replay: read header: %w", err)
+	}
+	if version := header[4]; version != logVersion {
+		return nil, fmt.Errorf("replay: read header: unsupported version %d", version)
+	}
+	result.kind = Kind(header[5])
+	if result.kind < KindController || result.kind > KindTrackingController {
+		return nil, fmt.Errorf("replay: read header: invalid kind %v", result.kind)
+	}
+	result.initialState = decodeState(header[6:])
+	return result, nil
+}
+
+// Kind returns the kind of the recorded controller.
+func (r *Reader) Kind() Kind {
+	return r.kind
+}
+
+// InitialState returns the controller state before the first update.
+func (r *Reader) InitialState() pid.AntiWindupControllerState {
+	return r.initialState
+}
+
+// Read the next record. Returns io.EOF at the end of the log, and io.ErrUnexpectedEOF for a truncated record.
+func (r *Reader) Read() (Record, error) {
+	if _, err := io.ReadFull(r.r, r.buf[:]); err != nil {
+		if errors.Is(err, io.EOF) {
+			return Record{}, io.EOF
+		}
+		return Record{}, fmt.Errorf("replay: read record: %w", err)
+	}
+	if err := verifyChecksum(r.buf[:]); err != nil {
+		return Record{}, fmt.Errorf("replay: read record: %w", err)
+	}
+	return Record{
+		Input: Input{
+			ReferenceSignal:      decodeFloat(r.buf[0:]),
+			ActualSignal:         decodeFloat(r.buf[8:]),
+			FeedForwardSignal:    decodeFloat(r.buf[16:]),
+			AppliedControlSignal: decodeFloat(r.buf[24:]),
+			SamplingInterval:     time.Duration(binary.LittleEndian.Uint64(r.buf[32:])),
+		},
+		State: decodeState(r.buf[40:]),
+	}, nil
+}
+
+// ReadLog reads a complete binary log.
+func ReadLog(r io.Reader) (Log, error) {
+	reader, err := NewReader(r)
+	if err != nil {
+		return Log{}, err
+	}
+	result := Log{Kind: reader.Kind(), InitialState: reader.InitialState()}
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return result, nil
+		}
+		if err != nil {
+			return Log{}, err
+		}
+		result.Records = append(result.Records, record)
+	}
+}
+
+// Write the log in the binary format.
+func (l Log) Write(w io.Writer) error {
+	recorder, err := NewRecorder(w, l.Kind, l.InitialState)
+	if err != nil {
+		return err
+	}
+	for _, record := range l.Records {
+		if err := recorder.Write(record); err != nil {
+			return err
+		}
+	}
+	return recorder.Flush()
+}
+
+func appendState(data []byte, s pid.AntiWindupControllerState) []byte {
+	for _, field := range [stateFieldCount]float64{
+		s.ControlError,
+		s.ControlErrorIntegrand,
+		s.ControlErrorIntegral,
+		s.ControlErrorDerivative,
+		s.ControlSignal,
+		s.UnsaturatedControlSignal,
+	} {
+		data = binary.LittleEndian.AppendUint64(data, math.Float64bits(field))
+	}
+	return data
+}
+
+func decodeState(data []byte) pid.AntiWindupControllerState {
+	return pid.AntiWindupControllerState{
+		ControlError:             decodeFloat(data[0:]),
+		ControlErrorIntegrand:    decodeFloat(data[8:]),
+		ControlErrorIntegral:     decodeFloat(data[16:]),
+		ControlErrorDerivative:   decodeFloat(data[24:]),
+		ControlSignal:            decodeFloat(data[32:]),
+		UnsaturatedControlSignal: decodeFloat(data[40:]),
+	}
+}
+
+func decodeFloat(data []byte) float64 {
+	return math.Float64frombits(binary.LittleEndian.Uint64(data))
+}
+
+func verifyChecksum(data []byte) error {
+	payload, checksum := data[:len(data)-checksumSize], data[len(data)-checksumSize:]
+	if crc32.ChecksumIEEE(payload) != binary.LittleEndian.Uint32(checksum) {
+		return fmt.Errorf("checksum mismatch")
+	}
+	return nil
+}
+
+func stateOfController(s pid.ControllerState) pid.AntiWindupControllerState {
+	return pid.AntiWindupControllerState{
+		ControlError:             s.ControlError,
+		ControlErrorIntegral:     s.ControlErrorIntegral,
+		ControlErrorDerivative:   s.ControlErrorDerivative,
+		ControlSignal:            s.ControlSignal,
+		UnsaturatedControlSignal: s.ControlSignal,
+	}
+}
+
+func controllerState(s pid.AntiWindupControllerState) pid.ControllerState {
+	return pid.ControllerState{
+		ControlError:           s.ControlError,
+		ControlErrorIntegral:   s.ControlErrorIntegral,
+		ControlErrorDerivative: s.ControlErrorDerivative,
+		ControlSignal:          s.ControlSignal,
+	}
+}
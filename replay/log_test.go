@@ -0,0 +1,120 @@
+package replay
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"math"
+	"testing"
+	"time"
+
+	"go.einride.tech/pid"
+	"gotest.tools/v3/assert"
+)
+
+func testInput(i int) pid.AntiWindupControllerInput {
+	return pid.AntiWindupControllerInput{
+		ReferenceSignal:   1,
+		ActualSignal:      math.Sin(float64(i) / 20),
+		FeedForwardSignal: 0.1,
+		SamplingInterval:  10 * time.Millisecond,
+	}
+}
+
+// recordTestLog records n updates of the test controller, with an invalid input halfway.
+func recordTestLog(t *testing.T, n int) (*bytes.Buffer, *pid.AntiWindupController) {
+	t.Helper()
+	c := &pid.AntiWindupController{
+		Config: pid.AntiWindupControllerConfig{
+			ProportionalGain:    2,
+			IntegralGain:        1,
+			DerivativeGain:      0.1,
+			AntiWindUpGain:      0.5,
+			LowPassTimeConstant: 100 * time.Millisecond,
+			MinOutput:           -1,
+			MaxOutput:           1,
+		},
+		State: pid.AntiWindupControllerState{ControlErrorIntegral: 0.25},
+	}
+	var b bytes.Buffer
+	recorder, err := NewRecorder(&b, KindAntiWindupController, c.State)
+	assert.NilError(t, err)
+	for i := range n {
+		input := testInput(i)
+		if i == n/2 {
+			input.ActualSignal = math.NaN()
+		}
+		c.Update(input)
+		assert.NilError(t, recorder.RecordAntiWindupController(c, input))
+	}
+	assert.NilError(t, recorder.Flush())
+	return &b, c
+}
+
+func TestRecorder_ReadLog(t *testing.T) {
+	// Given a recorded log
+	b, c := recordTestLog(t, 100)
+	// Then the log should be compact
+	assert.Equal(t, headerSize+100*recordSize, b.Len())
+	// When reading the log
+	log, err := ReadLog(b)
+	assert.NilError(t, err)
+	// Then the inputs and states should be recorded
+	assert.Equal(t, KindAntiWindupController, log.Kind)
+	assert.Equal(t, pid.AntiWindupControllerState{ControlErrorIntegral: 0.25}, log.InitialState)
+	assert.Equal(t, 100, len(log.Records))
+	assert.Equal(t, testInput(1).ActualSignal, log.Records[1].Input.ActualSignal)
+	assert.Equal(t, 0.1, log.Records[1].Input.FeedForwardSignal)
+	assert.Equal(t, 10*time.Millisecond, log.Records[1].Input.SamplingInterval)
+	assert.Assert(t, math.IsNaN(log.Records[50].Input.ActualSignal))
+	assert.Equal(t, c.State, log.Records[99].State)
+	assert.Equal(t, c.State.ControlSignal, log.Records[99].Input.AppliedControlSignal)
+	// And writing the log should reproduce the recording
+	var written bytes.Buffer
+	assert.NilError(t, log.Write(&written))
+	expected, _ := recordTestLog(t, 100)
+	assert.DeepEqual(t, expected.Bytes(), written.Bytes())
+}
+
+func TestReader_Truncated(t *testing.T) {
+	// Given a log with a truncated last record
+	b, _ := recordTestLog(t, 3)
+	data := b.Bytes()[:b.Len()-10]
+	reader, err := NewReader(bytes.NewReader(data))
+	assert.NilError(t, err)
+	// When reading the records
+	for range 2 {
+		_, err := reader.Read()
+		assert.NilError(t, err)
+	}
+	_, err = reader.Read()
+	// Then the truncation should be detected
+	assert.Assert(t, errors.Is(err, io.ErrUnexpectedEOF))
+}
+
+func TestReader_Corrupted(t *testing.T) {
+	// Given a log with a corrupted record
+	b, _ := recordTestLog(t, 3)
+	data := b.Bytes()
+	data[headerSize+recordSize+5] ^= 1
+	// When reading the log
+	_, err := ReadLog(bytes.NewReader(data))
+	// Then the corruption should be detected
+	assert.ErrorContains(t, err, "replay: read record: checksum mismatch")
+}
+
+func TestNewReader_Invalid(t *testing.T) {
+	_, err := NewReader(bytes.NewReader([]byte("not a log, but long enough to be mistaken for one if unchecked.")))
+	assert.ErrorContains(t, err, "replay: read header: not a log")
+	_, err = NewReader(bytes.NewReader(nil))
+	assert.Assert(t, errors.Is(err, io.EOF))
+}
+
+func TestRecorder_KindMismatch(t *testing.T) {
+	recorder, err := NewRecorder(io.Discard, KindTrackingController, pid.AntiWindupControllerState{})
+	assert.NilError(t, err)
+	err = recorder.RecordAntiWindupController(&pid.AntiWindupController{}, testInput(0))
+	assert.ErrorContains(t, err, "replay: record: AntiWindupController in log of TrackingController")
+	_, err = NewRecorder(io.Discard, 0, pid.AntiWindupControllerState{})
+	assert.ErrorContains(t, err, "replay: new recorder: invalid kind Kind(0)")
+}
@@ -0,0 +1,116 @@
+package replay
+
+import (
+	"go.einride.tech/pid"
+)
+
+// AppliedControlSignalMode determines the applied control signal input of a TrackingController in open-loop
+// replay.
+//
+// The recorded applied control signal is the actuator command that followed the recorded controller. When the
+// replayed controller has a different config, feeding it the recorded applied control signal makes its integral
+// track the output of the recorded controller instead of its own.
+type AppliedControlSignalMode int
+
+const (
+	// AppliedControlSignalReplayed applies the control signal of the replayed controller, as if the actuator had
+	// followed the replayed controller. This is the default.
+	AppliedControlSignalReplayed AppliedControlSignalMode = iota
+	// AppliedControlSignalRecorded applies the recorded applied control signal, as if the actuator had been
+	// driven the same way as in the recording, for example in manual mode.
+	AppliedControlSignalRecorded
+	// AppliedControlSignalOffset applies the control signal of the replayed controller plus the recorded
+	// difference between the applied and the recorded control signal, which preserves deviations of the actuator
+	// from the controller command such as un-modeled saturations.
+	AppliedControlSignalOffset
+)
+
+// ReplayController replays the recorded inputs through a Controller with the config, starting from the recorded
+// initial state, and returns the log of the replayed updates.
+func (l Log) ReplayController(config pid.ControllerConfig) Log {
+	c := pid.Controller{Config: config, State: controllerState(l.InitialState)}
+	result := Log{
+		Kind:         KindController,
+		InitialState: stateOfController(c.State),
+		Records:      make([]Record, 0, len(l.Records)),
+	}
+	for _, record := range l.Records {
+		c.Update(pid.ControllerInput{
+			ReferenceSignal:  record.Input.ReferenceSignal,
+			ActualSignal:     record.Input.ActualSignal,
+			SamplingInterval: record.Input.SamplingInterval,
+		})
+		input := record.Input
+		input.FeedForwardSignal = 0
+		input.AppliedControlSignal = c.State.ControlSignal
+		result.Records = append(result.Records, Record{Input: input, State: stateOfController(c.State)})
+	}
+	return result
+}
+
+// ReplayAntiWindupController replays the recorded inputs through an AntiWindupController with the config,
+// starting from the recorded initial state, and returns the log of the replayed updates.
+func (l Log) ReplayAntiWindupController(config pid.AntiWindupControllerConfig) Log {
+	c := pid.AntiWindupController{Config: config, State: l.InitialState}
+	result := Log{
+		Kind:         KindAntiWindupController,
+		InitialState: c.State,
+		Records:      make([]Record, 0, len(l.Records)),
+	}
+	for _, record := range l.Records {
+		c.Update(pid.AntiWindupControllerInput{
+			ReferenceSignal:   record.Input.ReferenceSignal,
+			ActualSignal:      record.Input.ActualSignal,
+			FeedForwardSignal: record.Input.FeedForwardSignal,
+			SamplingInterval:  record.Input.SamplingInterval,
+		})
+		input := record.Input
+		input.AppliedControlSignal = c.State.ControlSignal
+		result.Records = append(result.Records, Record{Input: input, State: c.State})
+	}
+	return result
+}
+
+// ReplayTrackingController replays the recorded inputs through a TrackingController with the config, starting
+// from the recorded initial state, and returns the log of the replayed updates.
+//
+// The applied control signal input of each update is determined by the mode, and recorded in the returned log.
+func (l Log) ReplayTrackingController(config pid.TrackingControllerConfig, mode AppliedControlSignalMode) Log {
+	c := pid.TrackingController{Config: config, State: pid.TrackingControllerState(l.InitialState)}
+	result := Log{
+		Kind:         KindTrackingController,
+		InitialState: l.InitialState,
+		Records:      make([]Record, 0, len(l.Records)),
+	}
+	for _, record := range l.Records {
+		input := pid.TrackingControllerInput{
+			ReferenceSignal:      record.Input.ReferenceSignal,
+			ActualSignal:         record.Input.ActualSignal,
+			FeedForwardSignal:    record.Input.FeedForwardSignal,
+			AppliedControlSignal: record.Input.AppliedControlSignal,
+			SamplingInterval:     record.Input.SamplingInterval,
+		}
+		if mode != AppliedControlSignalRecorded {
+			// The control signal of an update does not depend on the applied control signal, which only affects
+			// the integrand of the next update, so a trial update gives the control signal to apply.
+			trial := c
+			trial.Update(input)
+			input.AppliedControlSignal = trial.State.ControlSignal
+			if mode == AppliedControlSignalOffset {
+				input.AppliedControlSignal += record.Input.AppliedControlSignal - record.State.ControlSignal
+			}
+		}
+		c.Update(input)
+		result.Records = append(result.Records, Record{
+			Input: Input{
+				ReferenceSignal:      input.ReferenceSignal,
+				ActualSignal:         input.ActualSignal,
+				FeedForwardSignal:    input.FeedForwardSignal,
+				AppliedControlSignal: input.AppliedControlSignal,
+				SamplingInterval:     input.SamplingInterval,
+			},
+			State: pid.AntiWindupControllerState(c.State),
+		})
+	}
+	return result
+}
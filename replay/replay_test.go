@@ -0,0 +1,119 @@
+package replay
+
+import (
+	"bytes"
+	"math"
+	"testing"
+	"time"
+
+	"go.einride.tech/pid"
+	"gotest.tools/v3/assert"
+)
+
+func TestLog_ReplayAntiWindupController(t *testing.T) {
+	// Given a recorded log
+	b, c := recordTestLog(t, 200)
+	log, err := ReadLog(b)
+	assert.NilError(t, err)
+	// When replaying with the recorded config
+	replayed := log.ReplayAntiWindupController(c.Config)
+	// Then the replay should be deterministic and reproduce the recording
+	assertEqualLogs(t, log, replayed)
+	// When replaying with a different config
+	config := c.Config
+	config.ProportionalGain = 0.5
+	retuned := log.ReplayAntiWindupController(config)
+	// Then the inputs should be the same, but the control signals differ
+	assert.Equal(t, len(log.Records), len(retuned.Records))
+	assert.Equal(t, log.Records[10].Input.ActualSignal, retuned.Records[10].Input.ActualSignal)
+	assert.Assert(t, log.Records[10].State.ControlSignal != retuned.Records[10].State.ControlSignal)
+	assert.Equal(t, retuned.Records[10].State.ControlSignal, retuned.Records[10].Input.AppliedControlSignal)
+}
+
+func TestLog_ReplayController(t *testing.T) {
+	// Given a recorded log of a PI controller
+	c := &pid.Controller{Config: pid.ControllerConfig{ProportionalGain: 1, IntegralGain: 0.5}}
+	var b bytes.Buffer
+	recorder, err := NewRecorder(&b, KindController, pid.AntiWindupControllerState{})
+	assert.NilError(t, err)
+	for i := range 50 {
+		input := pid.ControllerInput{ReferenceSignal: 1, ActualSignal: float64(i) / 50, SamplingInterval: time.Second}
+		c.Update(input)
+		assert.NilError(t, recorder.RecordController(c, input))
+	}
+	assert.NilError(t, recorder.Flush())
+	log, err := ReadLog(&b)
+	assert.NilError(t, err)
+	// When replaying with the recorded config
+	replayed := log.ReplayController(c.Config)
+	// Then the replay should reproduce the recording
+	assertEqualLogs(t, log, replayed)
+}
+
+func TestLog_ReplayTrackingController(t *testing.T) {
+	// Given a recorded log of a tracking controller, with the actuator overridden to zero halfway
+	c := &pid.TrackingController{
+		Config: pid.TrackingControllerConfig{
+			ProportionalGain:    2,
+			IntegralGain:        1,
+			DerivativeGain:      0.1,
+			AntiWindUpGain:      0.5,
+			LowPassTimeConstant: 100 * time.Millisecond,
+			MinOutput:           -1,
+			MaxOutput:           1,
+		},
+	}
+	var b bytes.Buffer
+	recorder, err := NewRecorder(&b, KindTrackingController, pid.AntiWindupControllerState(c.State))
+	assert.NilError(t, err)
+	for i := range 200 {
+		input := testInput(i)
+		applied := c.State.ControlSignal
+		if i >= 100 {
+			applied = 0
+		}
+		trackingInput := pid.TrackingControllerInput{
+			ReferenceSignal:      input.ReferenceSignal,
+			ActualSignal:         input.ActualSignal,
+			FeedForwardSignal:    input.FeedForwardSignal,
+			AppliedControlSignal: applied,
+			SamplingInterval:     input.SamplingInterval,
+		}
+		c.Update(trackingInput)
+		assert.NilError(t, recorder.RecordTrackingController(c, trackingInput))
+	}
+	assert.NilError(t, recorder.Flush())
+	log, err := ReadLog(&b)
+	assert.NilError(t, err)
+	// When replaying with the recorded applied control signal
+	recorded := log.ReplayTrackingController(c.Config, AppliedControlSignalRecorded)
+	// Then the replay should reproduce the recording
+	assertEqualLogs(t, log, recorded)
+	// When replaying with the replayed control signal applied
+	replayed := log.ReplayTrackingController(c.Config, AppliedControlSignalReplayed)
+	// Then the controller should behave as an AntiWindupController that was never overridden
+	antiWindup := log.ReplayAntiWindupController(pid.AntiWindupControllerConfig(c.Config))
+	for i := range replayed.Records {
+		assert.Equal(t, antiWindup.Records[i].State, replayed.Records[i].State)
+		assert.Equal(t, replayed.Records[i].State.ControlSignal, replayed.Records[i].Input.AppliedControlSignal)
+	}
+	// When replaying with the recorded deviation of the actuator applied as offset
+	offset := log.ReplayTrackingController(c.Config, AppliedControlSignalOffset)
+	// Then the override should be preserved as the deviation from the replayed control signal
+	for i, record := range offset.Records {
+		deviation := log.Records[i].Input.AppliedControlSignal - log.Records[i].State.ControlSignal
+		expected := record.State.ControlSignal + deviation
+		assert.Assert(t, math.Abs(expected-record.Input.AppliedControlSignal) < 1e-12)
+	}
+}
+
+func assertEqualLogs(t *testing.T, expected, actual Log) {
+	t.Helper()
+	assert.Equal(t, expected.Kind, actual.Kind)
+	assert.Equal(t, expected.InitialState, actual.InitialState)
+	assert.Equal(t, len(expected.Records), len(actual.Records))
+	for i := range expected.Records {
+		assert.Equal(t, expected.Records[i].State, actual.Records[i].State, "record %d", i)
+		assert.Equal(t, expected.Records[i].Input.AppliedControlSignal, actual.Records[i].Input.AppliedControlSignal)
+	}
+}
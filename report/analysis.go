@@ -0,0 +1,174 @@
+package report
+
+import (
+	"math"
+	"time"
+
+	"go.einride.tech/pid"
+	"go.einride.tech/pid/model"
+	"go.einride.tech/pid/sim"
+	"go.einride.tech/pid/tuning"
+)
+
+// Config contains the parameters of a report.
+type Config struct {
+	// Title of the report.
+	Title string
+	// Controller is the config of the controller of the loop.
+	Controller pid.AntiWindupControllerConfig
+	// Model is an optional process model of the loop, for frequency-domain analysis.
+	Model model.Model
+	// SettlingBand is the settling band of the step responses, relative to the step size. Defaults to 0.02.
+	SettlingBand float64
+	// Oscillation is the config of the oscillation detection. Defaults to an ultimate period equal to the integral
+	// time of the controller, a min amplitude of 1% of the reference range, a supervision time of 50 ultimate
+	// periods and a detection threshold of 10.
+	Oscillation pid.OscillationDetectorConfig
+}
+
+// Analysis is the analysis of a trace presented in a report.
+type Analysis struct {
+	// StepResponses are the metrics of the responses to the reference steps.
+	StepResponses []sim.StepResponse
+	// IntegralIndices are the integral performance indices of the trace.
+	IntegralIndices sim.IntegralIndices
+	// Saturation is the saturation analysis of the control signal.
+	Saturation Saturation
+	// Oscillation is the oscillation analysis of the control error.
+	Oscillation Oscillation
+	// Stiction is the actuator stiction analysis, set when the control signal oscillates.
+	Stiction *pid.StictionAnalysis
+	// Margins are the robustness margins of the loop, set when a model is given.
+	Margins *tuning.Margins
+}
+
+// Saturation holds a saturation analysis of a control signal.
+type Saturation struct {
+	// Ratio is the fraction of time the control signal was saturated.
+	Ratio float64
+	// HighRatio is the fraction of time the control signal was saturated at the max output.
+	HighRatio float64
+	// LowRatio is the fraction of time the control signal was saturated at the min output.
+	LowRatio float64
+	// Episodes is the number of times the control signal entered saturation.
+	Episodes int
+	// LongestEpisode is the duration of the longest saturation episode.
+	LongestEpisode time.Duration
+}
+
+// Oscillation holds an oscillation analysis of a control error.
+type Oscillation struct {
+	// Detected is true when an oscillation was detected.
+	Detected bool
+	// DetectedAt is the time of the first detection.
+	DetectedAt time.Duration
+	// Period is the estimated period of the oscillation.
+	Period time.Duration
+	// Amplitude is the estimated amplitude of the oscillation.
+	Amplitude float64
+}
+
+// Analyze the trace of a loop.
+func Analyze(trace sim.Trace, config Config) Analysis {
+	config = config.withDefaults(trace)
+	result := Analysis{
+		StepResponses:   trace.StepResponses(config.SettlingBand),
+		IntegralIndices: trace.IntegralIndices(),
+		Saturation:      analyzeSaturation(trace),
+		Oscillation:     analyzeOscillation(trace, config.Oscillation),
+	}
+	if result.Oscillation.Detected {
+		u, y := make([]float64, len(trace.Samples)), make([]float64, len(trace.Samples))
+		for i, sample := range trace.Samples {
+			u[i], y[i] = sample.ControlSignal, sample.ActualSignal
+		}
+		if stiction, err := pid.AnalyzeStiction(u, y); err == nil && stiction.Oscillating {
+			result.Stiction = &stiction
+		}
+	}
+	if config.Model != nil {
+		margins := tuning.ComputeMargins(
+			tuning.GainsOfAntiWindupControllerConfig(config.Controller), config.Model.TransferFunction(),
+		)
+		result.Margins = &margins
+	}
+	return result
+}
+
+func (c Config) withDefaults(trace sim.Trace) Config {
+	if c.SettlingBand == 0 {
+		c.SettlingBand = 0.02
+	}
+	if c.Oscillation == (pid.OscillationDetectorConfig{}) {
+		ultimatePeriod := trace.Duration() / 10
+		if c.Controller.IntegralGain != 0 {
+			if ti := c.Controller.ProportionalGain / c.Controller.IntegralGain; ti > 0 {
+				ultimatePeriod = time.Duration(ti * float64(time.Second))
+			}
+		}
+		referenceRange := 0.0
+		if len(trace.Samples) > 0 {
+			lo, hi := math.Inf(1), math.Inf(-1)
+			for _, sample := range trace.Samples {
+				lo, hi = math.Min(lo, sample.ReferenceSignal), math.Max(hi, sample.ReferenceSignal)
+			}
+			referenceRange = hi - lo
+		}
+		c.Oscillation = pid.OscillationDetectorConfig{
+			MinAmplitude:       math.Max(0.01*referenceRange, math.SmallestNonzeroFloat64),
+			UltimatePeriod:     ultimatePeriod,
+			SupervisionTime:    50 * ultimatePeriod,
+			DetectionThreshold: 10,
+		}
+	}
+	return c
+}
+
+func analyzeSaturation(trace sim.Trace) Saturation {
+	var result Saturation
+	var saturated, high, low, episode time.Duration
+	for i := 0; i+1 < len(trace.Samples); i++ {
+		s := trace.Samples[i]
+		dt := trace.Samples[i+1].Time - s.Time
+		switch {
+		case s.UnsaturatedControlSignal > s.ControlSignal:
+			high += dt
+		case s.UnsaturatedControlSignal < s.ControlSignal:
+			low += dt
+		default:
+			episode = 0
+			continue
+		}
+		if episode == 0 {
+			result.Episodes++
+		}
+		saturated += dt
+		episode += dt
+		result.LongestEpisode = max(result.LongestEpisode, episode)
+	}
+	if duration := trace.Duration(); duration > 0 {
+		result.Ratio = saturated.Seconds() / duration.Seconds()
+		result.HighRatio = high.Seconds() / duration.Seconds()
+		result.LowRatio = low.Seconds() / duration.Seconds()
+	}
+	return result
+}
+
+func analyzeOscillation(trace sim.Trace, config pid.OscillationDetectorConfig) Oscillation {
+	var result Oscillation
+	detector := pid.OscillationDetector{Config: config}
+	for i := 1; i < len(trace.Samples); i++ {
+		s := trace.Samples[i]
+		detector.Update(pid.OscillationDetectorInput{
+			ControlError:     s.ReferenceSignal - s.ActualSignal,
+			SamplingInterval: s.Time - trace.Samples[i-1].Time,
+		})
+		if detector.State.Oscillating {
+			if !result.Detected {
+				result.Detected, result.DetectedAt = true, s.Time
+			}
+			result.Period, result.Amplitude = detector.State.Period, detector.State.Amplitude
+		}
+	}
+	return result
+}
@@ -0,0 +1,79 @@
+package report
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"go.einride.tech/pid"
+	"go.einride.tech/pid/model"
+	"go.einride.tech/pid/sim"
+	"gotest.tools/v3/assert"
+)
+
+var testController = pid.AntiWindupControllerConfig{
+	ProportionalGain:              1.5,
+	IntegralGain:                  0.5,
+	AntiWindUpGain:                0.5,
+	IntegralDischargeTimeConstant: 3,
+	LowPassTimeConstant:           100 * time.Millisecond,
+	MaxOutput:                     1,
+	MinOutput:                     -1,
+}
+
+var testModel = model.FOPDT{Gain: 2, TimeConstant: 3 * time.Second, DeadTime: 500 * time.Millisecond}
+
+func simulate(t *testing.T, controller pid.AntiWindupControllerConfig, reference []sim.Step) sim.Trace {
+	t.Helper()
+	trace, err := sim.Simulate(sim.Config{
+		Controller:       sim.NewAntiWindupController(controller),
+		Plant:            testModel,
+		SamplingInterval: 50 * time.Millisecond,
+		Duration:         60 * time.Second,
+		Reference:        reference,
+	})
+	assert.NilError(t, err)
+	return trace
+}
+
+func TestAnalyze(t *testing.T) {
+	// Given a trace with a reference step large enough to saturate the control signal
+	trace := simulate(t, testController, []sim.Step{{Time: time.Second, Value: 1.5}})
+	// When analyzing the trace with a model
+	analysis := Analyze(trace, Config{Controller: testController, Model: testModel})
+	// Then the step response should be analyzed
+	assert.Equal(t, 1, len(analysis.StepResponses))
+	assert.Assert(t, analysis.IntegralIndices.IAE > 0)
+	// And the saturation at the max output should be detected
+	assert.Equal(t, 1, analysis.Saturation.Episodes)
+	assert.Assert(t, analysis.Saturation.LongestEpisode > 0)
+	assert.Equal(t, analysis.Saturation.Ratio, analysis.Saturation.HighRatio)
+	assert.Equal(t, 0.0, analysis.Saturation.LowRatio)
+	// And no oscillation should be detected
+	assert.Assert(t, !analysis.Oscillation.Detected)
+	assert.Assert(t, analysis.Stiction == nil)
+	// And the margins should be computed from the model
+	assert.Assert(t, analysis.Margins != nil)
+	assert.Assert(t, analysis.Margins.GainMargin > 1)
+}
+
+func TestAnalyze_Oscillation(t *testing.T) {
+	// Given a trace of an aggressively tuned loop close to instability
+	controller := testController
+	controller.ProportionalGain, controller.IntegralGain = 4.5, 4
+	controller.MaxOutput, controller.MinOutput = math.Inf(1), math.Inf(-1)
+	trace := simulate(t, controller, []sim.Step{{Time: time.Second, Value: 1}})
+	// When analyzing the trace
+	analysis := Analyze(trace, Config{Controller: controller, Model: testModel})
+	// Then an oscillation should be detected
+	assert.Assert(t, analysis.Oscillation.Detected)
+	assert.Assert(t, analysis.Oscillation.Period > time.Second && analysis.Oscillation.Period < 5*time.Second)
+	assert.Assert(t, analysis.Stiction != nil)
+	assert.Assert(t, analysis.Margins.MaxSensitivity > 2)
+}
+
+func TestAnalyze_WithoutModel(t *testing.T) {
+	analysis := Analyze(sim.Trace{}, Config{})
+	assert.Assert(t, analysis.Margins == nil)
+	assert.Equal(t, Saturation{}, analysis.Saturation)
+}
@@ -0,0 +1,6 @@
+// Package report generates self-contained HTML performance reports of control loops from traces.
+//
+// A report contains plots of the trace, step-response metrics, integral indices, saturation and oscillation
+// analyses, and frequency-domain robustness margins when a process model is given. All plots are inline SVG,
+// so a report is a single file without external dependencies.
+package report
@@ -0,0 +1,128 @@
+package report
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+	"html/template"
+	"io"
+	"math"
+	"math/cmplx"
+	"time"
+
+	"go.einride.tech/pid/plot"
+	"go.einride.tech/pid/sim"
+	"go.einride.tech/pid/tuning"
+)
+
+//go:embed report.html.tmpl
+var reportTemplateText string
+
+var reportTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"percent": func(x float64) string { return fmt.Sprintf("%.1f%%", 100*x) },
+	"number":  func(x float64) string { return fmt.Sprintf("%.4g", x) },
+	"margin": func(x float64, unit string) string {
+		if math.IsInf(x, 0) {
+			return "∞"
+		}
+		return fmt.Sprintf("%.3g%s", x, unit)
+	},
+	"duration": func(d time.Duration) string { return d.Round(time.Millisecond).String() },
+}).Parse(reportTemplateText))
+
+// Write a self-contained HTML report of the trace of a loop.
+func Write(w io.Writer, trace sim.Trace, config Config) error {
+	config = config.withDefaults(trace)
+	analysis := Analyze(trace, config)
+	traceSVG, err := svg(plot.TraceFigure(trace, plot.TraceOptions{
+		MinOutput:    config.Controller.MinOutput,
+		MaxOutput:    config.Controller.MaxOutput,
+		SettlingBand: config.SettlingBand,
+	}))
+	if err != nil {
+		return fmt.Errorf("report: write: %w", err)
+	}
+	var bodeSVG template.HTML
+	if analysis.Margins != nil {
+		if bodeSVG, err = svg(bodeFigure(config, *analysis.Margins)); err != nil {
+			return fmt.Errorf("report: write: %w", err)
+		}
+	}
+	if err := reportTemplate.Execute(w, struct {
+		Config   Config
+		Trace    sim.Trace
+		Analysis Analysis
+		TraceSVG template.HTML
+		BodeSVG  template.HTML
+	}{
+		Config:   config,
+		Trace:    trace,
+		Analysis: analysis,
+		TraceSVG: traceSVG,
+		BodeSVG:  bodeSVG,
+	}); err != nil {
+		return fmt.Errorf("report: write: %w", err)
+	}
+	return nil
+}
+
+// svg renders the figure as SVG for inclusion in the report. The SVG is generated by package plot, which escapes
+// all text, so it is safe to include as HTML.
+func svg(f plot.Figure) (template.HTML, error) {
+	var b bytes.Buffer
+	if err := f.WriteSVG(&b); err != nil {
+		return "", err
+	}
+	return template.HTML(b.String()), nil //nolint:gosec // the SVG is generated with escaped text
+}
+
+// bodeFigure returns a Bode plot of the loop transfer function, two decades around the gain crossover frequency.
+func bodeFigure(config Config, margins tuning.Margins) plot.Figure {
+	center := margins.GainCrossoverFrequency
+	if center == 0 {
+		center = 1
+	}
+	const points = 400
+	logOmega, magnitude, phase := make([]float64, points), make([]float64, points), make([]float64, points)
+	controller := tuning.GainsOfAntiWindupControllerConfig(config.Controller)
+	process := config.Model.TransferFunction()
+	for i := range points {
+		logOmega[i] = math.Log10(center) - 2 + 4*float64(i)/(points-1)
+		omega := math.Pow(10, logOmega[i])
+		l := controller.FrequencyResponse(omega) * process.FrequencyResponse(omega)
+		magnitude[i] = 20 * math.Log10(cmplx.Abs(l))
+		phase[i] = cmplx.Phase(l) * 180 / math.Pi
+		if i > 0 {
+			phase[i] += 360 * math.Round((phase[i-1]-phase[i])/360)
+		}
+	}
+	magnitudeAxes := plot.Axes{
+		YLabel: "|L| (dB)",
+		Series: []plot.Series{{Name: "|L(jω)|", X: logOmega, Y: magnitude}},
+		Lines:  []plot.Line{{Label: "0 dB", Y: 0}},
+	}
+	phaseAxes := plot.Axes{
+		YLabel: "arg L (°)",
+		Series: []plot.Series{{Name: "arg L(jω)", X: logOmega, Y: phase, Color: "#d62728"}},
+		Lines:  []plot.Line{{Label: "-180°", Y: -180}},
+	}
+	if margins.GainCrossoverFrequency > 0 {
+		phaseAxes.Annotations = append(phaseAxes.Annotations, plot.Annotation{
+			X:    math.Log10(margins.GainCrossoverFrequency),
+			Y:    margins.PhaseMargin - 180,
+			Text: fmt.Sprintf("PM %.1f°", margins.PhaseMargin),
+		})
+	}
+	if margins.PhaseCrossoverFrequency > 0 {
+		magnitudeAxes.Annotations = append(magnitudeAxes.Annotations, plot.Annotation{
+			X:    math.Log10(margins.PhaseCrossoverFrequency),
+			Y:    -20 * math.Log10(margins.GainMargin),
+			Text: fmt.Sprintf("GM %.2f", margins.GainMargin),
+		})
+	}
+	return plot.Figure{
+		XLabel:     "log10 ω (rad/s)",
+		AxesHeight: 160,
+		Axes:       []plot.Axes{magnitudeAxes, phaseAxes},
+	}
+}
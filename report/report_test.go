@@ -0,0 +1,40 @@
+package report
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"go.einride.tech/pid/sim"
+	"gotest.tools/v3/assert"
+)
+
+func TestWrite(t *testing.T) {
+	// Given a trace of a loop
+	trace := simulate(t, testController, []sim.Step{{Time: time.Second, Value: 1.5}})
+	// When writing a report with a model
+	var b bytes.Buffer
+	assert.NilError(t, Write(&b, trace, Config{Title: "Flow <loop>", Controller: testController, Model: testModel}))
+	// Then the report should be a self-contained HTML document with plots and analyses
+	html := b.String()
+	assert.Assert(t, strings.HasPrefix(html, "<!DOCTYPE html>"))
+	assert.Assert(t, strings.Contains(html, "<title>Flow &lt;loop&gt;</title>"))
+	assert.Equal(t, 2, strings.Count(html, "<svg "))
+	for _, section := range []string{"Step responses", "Integral indices", "Saturation", "Oscillation", "Robustness"} {
+		assert.Assert(t, strings.Contains(html, section), section)
+	}
+	// And the report should not load external resources
+	assert.Assert(t, !strings.Contains(html, "src="))
+	assert.Assert(t, !strings.Contains(html, "<link"))
+}
+
+func TestWrite_WithoutModel(t *testing.T) {
+	trace := simulate(t, testController, nil)
+	var b bytes.Buffer
+	assert.NilError(t, Write(&b, trace, Config{Controller: testController}))
+	html := b.String()
+	assert.Equal(t, 1, strings.Count(html, "<svg "))
+	assert.Assert(t, strings.Contains(html, "No reference steps."))
+	assert.Assert(t, !strings.Contains(html, "Robustness"))
+}
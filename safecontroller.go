@@ -0,0 +1,95 @@
+package pid
+
+import "sync"
+
+// SafeController wraps a controller to make it safe for concurrent use.
+//
+// Updates, resets and config swaps are serialized, so that a config swapped in by one goroutine takes effect
+// atomically between two updates performed by another goroutine, and state snapshots are always consistent.
+//
+// Once wrapped, the underlying controller must only be accessed through the SafeController.
+type SafeController[Config, State, Input any] struct {
+	mu     sync.RWMutex
+	config *Config
+	state  *State
+	update func(Input)
+	reset  func()
+}
+
+// NewSafeController wraps a Controller to make it safe for concurrent use.
+func NewSafeController(c *Controller) *SafeController[ControllerConfig, ControllerState, ControllerInput] {
+	return &SafeController[ControllerConfig, ControllerState, ControllerInput]{
+		config: &c.Config,
+		state:  &c.State,
+		update: c.Update,
+		reset:  c.Reset,
+	}
+}
+
+// NewSafeAntiWindupController wraps an AntiWindupController to make it safe for concurrent use.
+func NewSafeAntiWindupController(
+	c *AntiWindupController,
+) *SafeController[AntiWindupControllerConfig, AntiWindupControllerState, AntiWindupControllerInput] {
+	return &SafeController[AntiWindupControllerConfig, AntiWindupControllerState, AntiWindupControllerInput]{
+		config: &c.Config,
+		state:  &c.State,
+		update: c.Update,
+		reset:  c.Reset,
+	}
+}
+
+// NewSafeTrackingController wraps a TrackingController to make it safe for concurrent use.
+func NewSafeTrackingController(
+	c *TrackingController,
+) *SafeController[TrackingControllerConfig, TrackingControllerState, TrackingControllerInput] {
+	return &SafeController[TrackingControllerConfig, TrackingControllerState, TrackingControllerInput]{
+		config: &c.Config,
+		state:  &c.State,
+		update: c.Update,
+		reset:  c.Reset,
+	}
+}
+
+// Update the controller state and return a snapshot of the updated state.
+func (c *SafeController[Config, State, Input]) Update(input Input) State {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.update(input)
+	return *c.state
+}
+
+// Reset the controller state.
+func (c *SafeController[Config, State, Input]) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.reset()
+}
+
+// State returns a consistent snapshot of the controller state.
+func (c *SafeController[Config, State, Input]) State() State {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return *c.state
+}
+
+// Config returns a snapshot of the controller config.
+func (c *SafeController[Config, State, Input]) Config() Config {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return *c.config
+}
+
+// SetConfig atomically swaps the controller config. The new config takes effect from the next update.
+func (c *SafeController[Config, State, Input]) SetConfig(config Config) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	*c.config = config
+}
+
+// Do calls f with exclusive access to the underlying controller, for operations such as DischargeIntegral that
+// are not covered by the SafeController methods.
+func (c *SafeController[Config, State, Input]) Do(f func()) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	f()
+}
@@ -0,0 +1,111 @@
+package pid
+
+import (
+	"math"
+	"sync"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestSafeController_Update(t *testing.T) {
+	// Given a safe controller wrapping a basic PID controller
+	c := NewSafeController(&Controller{
+		Config: ControllerConfig{
+			ProportionalGain: 2.0,
+			IntegralGain:     1.0,
+			DerivativeGain:   1.0,
+		},
+	})
+	// When updating
+	state := c.Update(ControllerInput{
+		ReferenceSignal:  10,
+		ActualSignal:     0,
+		SamplingInterval: 100 * time.Millisecond,
+	})
+	// Then the returned snapshot should match the controller state
+	assert.Equal(t, float64(121), state.ControlSignal)
+	assert.Equal(t, state, c.State())
+	// And resetting should clear the state
+	c.Reset()
+	assert.Equal(t, ControllerState{}, c.State())
+}
+
+func TestSafeAntiWindupController_SetConfig(t *testing.T) {
+	// Given a safe P controller
+	c := NewSafeAntiWindupController(&AntiWindupController{
+		Config: AntiWindupControllerConfig{
+			LowPassTimeConstant: 1 * time.Second,
+			ProportionalGain:    1,
+			MinOutput:           -10,
+			MaxOutput:           10,
+		},
+	})
+	input := AntiWindupControllerInput{ReferenceSignal: 1, SamplingInterval: dtTest}
+	assert.Equal(t, 1.0, c.Update(input).ControlSignal)
+	// When swapping the config
+	config := c.Config()
+	config.ProportionalGain = 2
+	c.SetConfig(config)
+	// Then the new config should take effect from the next update
+	assert.Equal(t, config, c.Config())
+	assert.Equal(t, 2.0, c.Update(input).ControlSignal)
+}
+
+func TestSafeTrackingController_Do(t *testing.T) {
+	// Given a safe tracking controller with a charged integral
+	tc := &TrackingController{
+		Config: TrackingControllerConfig{IntegralDischargeTimeConstant: 10},
+		State:  TrackingControllerState{ControlErrorIntegral: 100000, ControlErrorIntegrand: 10},
+	}
+	c := NewSafeTrackingController(tc)
+	// When discharging the integral with exclusive access
+	c.Do(func() {
+		tc.DischargeIntegral(dtTest)
+	})
+	// Then
+	assert.Equal(t, TrackingControllerState{ControlErrorIntegral: 99900}, c.State())
+}
+
+func TestSafeAntiWindupController_Concurrent(t *testing.T) {
+	// Given a safe PI controller
+	c := NewSafeAntiWindupController(&AntiWindupController{
+		Config: AntiWindupControllerConfig{
+			LowPassTimeConstant: 1 * time.Second,
+			ProportionalGain:    1,
+			IntegralGain:        1,
+			MinOutput:           -10,
+			MaxOutput:           10,
+		},
+	})
+	// When concurrently updating, tuning and reading state
+	var wg sync.WaitGroup
+	wg.Add(3)
+	states := make([]AntiWindupControllerState, 0, 1000)
+	go func() {
+		defer wg.Done()
+		for range 1000 {
+			c.Update(AntiWindupControllerInput{ReferenceSignal: 1, SamplingInterval: dtTest})
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for i := range 1000 {
+			config := c.Config()
+			config.ProportionalGain = float64(i % 2)
+			c.SetConfig(config)
+		}
+	}()
+	go func() {
+		defer wg.Done()
+		for range 1000 {
+			states = append(states, c.State())
+		}
+	}()
+	wg.Wait()
+	// Then every snapshot should be internally consistent
+	for _, state := range states {
+		assert.Equal(t, math.Max(-10, math.Min(10, state.UnsaturatedControlSignal)), state.ControlSignal)
+	}
+}
@@ -0,0 +1,82 @@
+package sim
+
+import (
+	"go.einride.tech/pid"
+)
+
+// Controller is a controller in a simulated loop.
+type Controller interface {
+	// Update the controller and return the resulting control signal and its terms.
+	Update(input pid.AntiWindupControllerInput) Terms
+	// Reset the controller state.
+	Reset()
+}
+
+// Terms holds the control signal of a controller and its terms.
+type Terms struct {
+	// ControlSignal is the control signal output of the controller.
+	ControlSignal float64
+	// UnsaturatedControlSignal is the control signal before saturation.
+	UnsaturatedControlSignal float64
+	// ProportionalTerm is the contribution of the P part to the control signal.
+	ProportionalTerm float64
+	// IntegralTerm is the contribution of the I part to the control signal.
+	IntegralTerm float64
+	// DerivativeTerm is the contribution of the D part to the control signal.
+	DerivativeTerm float64
+}
+
+// NewAntiWindupController returns a Controller that simulates a pid.AntiWindupController.
+func NewAntiWindupController(config pid.AntiWindupControllerConfig) Controller {
+	return &antiWindupController{c: pid.AntiWindupController{Config: config}}
+}
+
+type antiWindupController struct {
+	c pid.AntiWindupController
+}
+
+func (a *antiWindupController) Update(input pid.AntiWindupControllerInput) Terms {
+	a.c.Update(input)
+	return Terms{
+		ControlSignal:            a.c.State.ControlSignal,
+		UnsaturatedControlSignal: a.c.State.UnsaturatedControlSignal,
+		ProportionalTerm:         a.c.Config.ProportionalGain * a.c.State.ControlError,
+		IntegralTerm:             a.c.Config.IntegralGain * a.c.State.ControlErrorIntegral,
+		DerivativeTerm:           a.c.Config.DerivativeGain * a.c.State.ControlErrorDerivative,
+	}
+}
+
+func (a *antiWindupController) Reset() {
+	a.c.Reset()
+}
+
+// NewController returns a Controller that simulates a pid.Controller.
+//
+// The pid.Controller has no feed forward and no saturation, so the feed forward signal is ignored and the
+// unsaturated control signal equals the control signal.
+func NewController(config pid.ControllerConfig) Controller {
+	return &controller{c: pid.Controller{Config: config}}
+}
+
+type controller struct {
+	c pid.Controller
+}
+
+func (c *controller) Update(input pid.AntiWindupControllerInput) Terms {
+	c.c.Update(pid.ControllerInput{
+		ReferenceSignal:  input.ReferenceSignal,
+		ActualSignal:     input.ActualSignal,
+		SamplingInterval: input.SamplingInterval,
+	})
+	return Terms{
+		ControlSignal:            c.c.State.ControlSignal,
+		UnsaturatedControlSignal: c.c.State.ControlSignal,
+		ProportionalTerm:         c.c.Config.ProportionalGain * c.c.State.ControlError,
+		IntegralTerm:             c.c.Config.IntegralGain * c.c.State.ControlErrorIntegral,
+		DerivativeTerm:           c.c.Config.DerivativeGain * c.c.State.ControlErrorDerivative,
+	}
+}
+
+func (c *controller) Reset() {
+	c.c.Reset()
+}
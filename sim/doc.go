@@ -0,0 +1,3 @@
+// Package sim provides closed-loop simulation of the controllers in package pid, traces of simulated or recorded
+// loops, and step-response and integral performance metrics of traces.
+package sim
@@ -0,0 +1,116 @@
+package sim
+
+import (
+	"math"
+	"time"
+)
+
+// StepResponse holds performance metrics of the response to a step of the reference signal.
+type StepResponse struct {
+	// Time of the step.
+	Time time.Duration
+	// From is the reference signal before the step.
+	From float64
+	// To is the reference signal after the step.
+	To float64
+	// RiseTime is the time for the actual signal to go from 10% to 90% of the step. Zero if the actual signal
+	// did not reach 90% of the step before the next step or the end of the trace.
+	RiseTime time.Duration
+	// Settled is true when the actual signal settled within the settling band of the reference signal.
+	Settled bool
+	// SettlingTime is the time after the step after which the actual signal stayed within the settling band.
+	SettlingTime time.Duration
+	// Overshoot is the max excursion of the actual signal beyond the reference signal, relative to the step size.
+	Overshoot float64
+	// SteadyStateError is the difference between the reference signal and the actual signal at the end of the
+	// response.
+	SteadyStateError float64
+}
+
+// StepResponses returns the metrics of the responses to all steps of the reference signal of the trace.
+//
+// Each response lasts until the next step or the end of the trace. The settling band is relative to the step
+// size, a typical value is 0.02.
+func (t Trace) StepResponses(settlingBand float64) []StepResponse {
+	var result []StepResponse
+	for start := 1; start < len(t.Samples); start++ {
+		if t.Samples[start].ReferenceSignal == t.Samples[start-1].ReferenceSignal {
+			continue
+		}
+		end := start + 1
+		for end < len(t.Samples) && t.Samples[end].ReferenceSignal == t.Samples[start].ReferenceSignal {
+			end++
+		}
+		result = append(result, t.stepResponse(start, end, settlingBand))
+	}
+	return result
+}
+
+func (t Trace) stepResponse(start, end int, settlingBand float64) StepResponse {
+	samples := t.Samples[start:end]
+	result := StepResponse{
+		Time: samples[0].Time,
+		From: t.Samples[start-1].ReferenceSignal,
+		To:   samples[0].ReferenceSignal,
+	}
+	size := result.To - result.From
+	// Normalize the actual signal to the fraction of the step, with the actual signal before the step as origin.
+	origin := t.Samples[start-1].ActualSignal
+	progress := func(s Sample) float64 {
+		return (s.ActualSignal - origin) / size
+	}
+	var rise10 time.Duration
+	var reached10 bool
+	for _, s := range samples {
+		p := progress(s)
+		if !reached10 && p >= 0.1 {
+			rise10, reached10 = s.Time, true
+		}
+		if reached10 && p >= 0.9 {
+			result.RiseTime = s.Time - rise10
+			break
+		}
+	}
+	for _, s := range samples {
+		result.Overshoot = math.Max(result.Overshoot, (s.ActualSignal-result.To)/size)
+	}
+	result.Settled = true
+	result.SettlingTime = 0
+	for i := len(samples) - 1; i >= 0; i-- {
+		if math.Abs(samples[i].ActualSignal-result.To) > settlingBand*math.Abs(size) {
+			if i == len(samples)-1 {
+				result.Settled = false
+			} else {
+				result.SettlingTime = samples[i+1].Time - result.Time
+			}
+			break
+		}
+	}
+	last := samples[len(samples)-1]
+	result.SteadyStateError = last.ReferenceSignal - last.ActualSignal
+	return result
+}
+
+// IntegralIndices holds integral performance indices of a trace.
+type IntegralIndices struct {
+	// IAE is the integrated absolute error.
+	IAE float64
+	// ISE is the integrated squared error.
+	ISE float64
+	// ITAE is the integrated time-weighted absolute error, with time relative to the start of the trace.
+	ITAE float64
+}
+
+// IntegralIndices returns the integral performance indices of the trace, integrated with the rectangle rule.
+func (t Trace) IntegralIndices() IntegralIndices {
+	var result IntegralIndices
+	for i := 0; i+1 < len(t.Samples); i++ {
+		s := t.Samples[i]
+		dt := (t.Samples[i+1].Time - s.Time).Seconds()
+		e := math.Abs(s.ReferenceSignal - s.ActualSignal)
+		result.IAE += e * dt
+		result.ISE += e * e * dt
+		result.ITAE += (s.Time - t.Samples[0].Time).Seconds() * e * dt
+	}
+	return result
+}
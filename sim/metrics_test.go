@@ -0,0 +1,66 @@
+package sim
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestTrace_StepResponses(t *testing.T) {
+	// Given a trace of two reference steps with known responses
+	var trace Trace
+	for i := range 200 {
+		s := Sample{Time: time.Duration(i) * 100 * time.Millisecond}
+		switch {
+		case i < 10:
+		case i < 100:
+			// A second-order response with 20% overshoot.
+			tt := float64(i-10) / 10
+			s.ReferenceSignal = 1
+			s.ActualSignal = 1 - math.Exp(-tt)*math.Cos(math.Pi*tt/2) + 0.2*math.Exp(-tt)*math.Sin(math.Pi*tt/2)
+		default:
+			// A first-order response with a steady-state error.
+			tt := float64(i-100) / 10
+			s.ReferenceSignal = -1
+			s.ActualSignal = 1 - 1.9*(1-math.Exp(-tt))
+		}
+		trace.Samples = append(trace.Samples, s)
+	}
+	// When computing the step responses
+	responses := trace.StepResponses(0.02)
+	// Then the metrics should be the expected
+	assert.Equal(t, 2, len(responses))
+	first := responses[0]
+	assert.Equal(t, time.Second, first.Time)
+	assert.Equal(t, 0.0, first.From)
+	assert.Equal(t, 1.0, first.To)
+	assert.Assert(t, first.RiseTime > 0 && first.RiseTime < 2*time.Second)
+	assert.Assert(t, first.Overshoot > 0.05 && first.Overshoot < 0.3, first.Overshoot)
+	assert.Assert(t, first.Settled)
+	assert.Assert(t, first.SettlingTime > 2*time.Second && first.SettlingTime < 6*time.Second, first.SettlingTime)
+	second := responses[1]
+	assert.Equal(t, 1.0, second.From)
+	assert.Equal(t, -1.0, second.To)
+	assert.Equal(t, 0.0, second.Overshoot)
+	assert.Assert(t, !second.Settled)
+	assert.Assert(t, math.Abs(second.SteadyStateError+0.1) < 1e-3)
+	// The first-order response rises from 10% to 90% in ln(9) time constants, but reaches 90% of the step only
+	// after the step size is reduced by the steady-state error.
+	assert.Assert(t, second.RiseTime > 2*time.Second)
+}
+
+func TestTrace_IntegralIndices(t *testing.T) {
+	// Given a trace with a constant control error of 2 during 3 seconds
+	trace := Trace{Samples: []Sample{
+		{Time: 0, ReferenceSignal: 2},
+		{Time: time.Second, ReferenceSignal: 2},
+		{Time: 2 * time.Second, ReferenceSignal: 2},
+		{Time: 3 * time.Second, ReferenceSignal: 2},
+	}}
+	// When computing the integral indices
+	indices := trace.IntegralIndices()
+	// Then the indices should be the expected
+	assert.Equal(t, IntegralIndices{IAE: 6, ISE: 12, ITAE: 6}, indices)
+}
@@ -0,0 +1,101 @@
+package sim
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"go.einride.tech/pid"
+	"go.einride.tech/pid/model"
+)
+
+// Config contains the parameters of a closed-loop simulation.
+type Config struct {
+	// Controller is the controller to simulate.
+	Controller Controller
+	// Plant is the process model to control.
+	Plant model.Model
+	// SamplingInterval is the sampling interval of the controller.
+	SamplingInterval time.Duration
+	// Duration of the simulation.
+	Duration time.Duration
+	// Reference holds the reference signal steps. The reference signal is zero before the first step.
+	Reference []Step
+	// Disturbance holds the steps of the load disturbance at the process input. The disturbance is zero before
+	// the first step.
+	Disturbance []Step
+	// FeedForward holds the feed-forward signal steps. The feed-forward signal is zero before the first step.
+	FeedForward []Step
+	// MeasurementNoise is the standard deviation of white Gaussian noise added to the measured actual signal.
+	MeasurementNoise float64
+	// Seed of the measurement noise generator.
+	Seed uint64
+}
+
+// Step is a step change of a piecewise constant signal.
+type Step struct {
+	// Time of the step.
+	Time time.Duration
+	// Value of the signal from the time of the step.
+	Value float64
+}
+
+// Simulate a closed loop of the controller and the plant, and return the trace with one sample per sampling
+// interval.
+//
+// At each sample, the controller is updated with the measured actual signal, and the control signal plus the
+// load disturbance is applied to the plant until the next sample.
+func Simulate(config Config) (Trace, error) {
+	if config.Controller == nil {
+		return Trace{}, fmt.Errorf("sim: simulate: missing controller")
+	}
+	if config.SamplingInterval <= 0 {
+		return Trace{}, fmt.Errorf("sim: simulate: non-positive sampling interval %v", config.SamplingInterval)
+	}
+	plant, err := model.NewSimulation(config.Plant)
+	if err != nil {
+		return Trace{}, fmt.Errorf("sim: simulate: %w", err)
+	}
+	noise := rand.New(rand.NewPCG(config.Seed, 0))
+	n := int(config.Duration / config.SamplingInterval)
+	trace := Trace{Samples: make([]Sample, 0, n+1)}
+	for k := 0; k <= n; k++ {
+		t := time.Duration(k) * config.SamplingInterval
+		sample := Sample{
+			Time:              t,
+			ReferenceSignal:   valueAt(config.Reference, t),
+			ActualSignal:      plant.Output(),
+			FeedForwardSignal: valueAt(config.FeedForward, t),
+			Disturbance:       valueAt(config.Disturbance, t),
+		}
+		if config.MeasurementNoise > 0 {
+			sample.ActualSignal += config.MeasurementNoise * noise.NormFloat64()
+		}
+		terms := config.Controller.Update(pid.AntiWindupControllerInput{
+			ReferenceSignal:   sample.ReferenceSignal,
+			ActualSignal:      sample.ActualSignal,
+			FeedForwardSignal: sample.FeedForwardSignal,
+			SamplingInterval:  config.SamplingInterval,
+		})
+		sample.ControlSignal = terms.ControlSignal
+		sample.UnsaturatedControlSignal = terms.UnsaturatedControlSignal
+		sample.ProportionalTerm = terms.ProportionalTerm
+		sample.IntegralTerm = terms.IntegralTerm
+		sample.DerivativeTerm = terms.DerivativeTerm
+		trace.Samples = append(trace.Samples, sample)
+		plant.Update(sample.ControlSignal+sample.Disturbance, config.SamplingInterval)
+	}
+	return trace, nil
+}
+
+// valueAt returns the value of the piecewise constant signal at time t.
+func valueAt(steps []Step, t time.Duration) float64 {
+	var result float64
+	var latest time.Duration
+	for _, step := range steps {
+		if step.Time <= t && step.Time >= latest {
+			result, latest = step.Value, step.Time
+		}
+	}
+	return result
+}
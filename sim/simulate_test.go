@@ -0,0 +1,99 @@
+package sim
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"go.einride.tech/pid"
+	"go.einride.tech/pid/model"
+	"gotest.tools/v3/assert"
+)
+
+func testConfig() Config {
+	return Config{
+		Controller: NewAntiWindupController(pid.AntiWindupControllerConfig{
+			ProportionalGain:              1.5,
+			IntegralGain:                  0.5,
+			AntiWindUpGain:                0.5,
+			IntegralDischargeTimeConstant: 10,
+			LowPassTimeConstant:           100 * time.Millisecond,
+			MaxOutput:                     10,
+			MinOutput:                     -10,
+		}),
+		Plant:            model.FOPDT{Gain: 2, TimeConstant: 3 * time.Second, DeadTime: 500 * time.Millisecond},
+		SamplingInterval: 50 * time.Millisecond,
+		Duration:         60 * time.Second,
+		Reference:        []Step{{Time: time.Second, Value: 1}},
+	}
+}
+
+func TestSimulate_ReferenceStep(t *testing.T) {
+	// Given a PI controller of a FOPDT plant
+	config := testConfig()
+	// When simulating a reference step
+	trace, err := Simulate(config)
+	assert.NilError(t, err)
+	// Then the trace should have one sample per sampling interval
+	assert.Equal(t, 1201, len(trace.Samples))
+	assert.Equal(t, 60*time.Second, trace.Duration())
+	// And the actual signal should settle at the reference
+	last := trace.Samples[len(trace.Samples)-1]
+	assert.Assert(t, math.Abs(last.ActualSignal-1) < 1e-3)
+	// And the static gain of the plant should give the steady-state control signal
+	assert.Assert(t, math.Abs(last.ControlSignal-0.5) < 1e-3)
+	assert.Assert(t, math.Abs(last.IntegralTerm-0.5) < 1e-2)
+}
+
+func TestSimulate_Disturbance(t *testing.T) {
+	// Given a simulation with a load disturbance
+	config := testConfig()
+	config.Disturbance = []Step{{Time: 30 * time.Second, Value: -0.25}}
+	trace, err := Simulate(config)
+	assert.NilError(t, err)
+	// Then the integral part should reject the disturbance
+	last := trace.Samples[len(trace.Samples)-1]
+	assert.Assert(t, math.Abs(last.ActualSignal-1) < 1e-2)
+	assert.Assert(t, math.Abs(last.ControlSignal-0.75) < 1e-2)
+	assert.Equal(t, -0.25, last.Disturbance)
+}
+
+func TestSimulate_NoiseIsDeterministic(t *testing.T) {
+	config := testConfig()
+	config.MeasurementNoise = 0.01
+	config.Seed = 42
+	config.Controller = NewAntiWindupController(pid.AntiWindupControllerConfig{
+		ProportionalGain: 1, LowPassTimeConstant: time.Second, MaxOutput: 10, MinOutput: -10,
+	})
+	first, err := Simulate(config)
+	assert.NilError(t, err)
+	config.Controller.Reset()
+	second, err := Simulate(config)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, first, second)
+}
+
+func TestSimulate_Controller(t *testing.T) {
+	config := testConfig()
+	config.Controller = NewController(pid.ControllerConfig{ProportionalGain: 1, IntegralGain: 0.5})
+	trace, err := Simulate(config)
+	assert.NilError(t, err)
+	last := trace.Samples[len(trace.Samples)-1]
+	assert.Assert(t, math.Abs(last.ActualSignal-1) < 1e-3)
+	assert.Equal(t, last.ControlSignal, last.UnsaturatedControlSignal)
+}
+
+func TestSimulate_InvalidConfig(t *testing.T) {
+	config := testConfig()
+	config.SamplingInterval = 0
+	_, err := Simulate(config)
+	assert.ErrorContains(t, err, "sampling interval")
+	config = testConfig()
+	config.Controller = nil
+	_, err = Simulate(config)
+	assert.ErrorContains(t, err, "missing controller")
+	config = testConfig()
+	config.Plant = model.TransferFunction{}
+	_, err = Simulate(config)
+	assert.ErrorContains(t, err, "zero denominator")
+}
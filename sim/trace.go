@@ -0,0 +1,157 @@
+package sim
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"time"
+)
+
+// Sample is a sample of a control loop.
+type Sample struct {
+	// Time of the sample, relative to the start of the trace.
+	Time time.Duration
+	// ReferenceSignal is the reference value for the signal to control.
+	ReferenceSignal float64
+	// ActualSignal is the measured value of the signal to control.
+	ActualSignal float64
+	// FeedForwardSignal is the feed-forward contribution to the control signal.
+	FeedForwardSignal float64
+	// ControlSignal is the control signal output of the controller.
+	ControlSignal float64
+	// UnsaturatedControlSignal is the control signal before saturation.
+	UnsaturatedControlSignal float64
+	// ProportionalTerm is the contribution of the P part to the control signal.
+	ProportionalTerm float64
+	// IntegralTerm is the contribution of the I part to the control signal.
+	IntegralTerm float64
+	// DerivativeTerm is the contribution of the D part to the control signal.
+	DerivativeTerm float64
+	// Disturbance is the load disturbance added to the control signal at the process input.
+	Disturbance float64
+}
+
+// Trace is a time series of samples of a control loop, in order of increasing time.
+type Trace struct {
+	// Samples of the trace.
+	Samples []Sample
+}
+
+// csvColumns are the CSV column names of the Sample fields, in declaration order.
+var csvColumns = []string{
+	"time",
+	"reference",
+	"actual",
+	"feed_forward",
+	"control",
+	"unsaturated_control",
+	"proportional",
+	"integral",
+	"derivative",
+	"disturbance",
+}
+
+func (s *Sample) fields() []*float64 {
+	return []*float64{
+		&s.ReferenceSignal,
+		&s.ActualSignal,
+		&s.FeedForwardSignal,
+		&s.ControlSignal,
+		&s.UnsaturatedControlSignal,
+		&s.ProportionalTerm,
+		&s.IntegralTerm,
+		&s.DerivativeTerm,
+		&s.Disturbance,
+	}
+}
+
+// Duration returns the time between the first and the last sample of the trace.
+func (t Trace) Duration() time.Duration {
+	if len(t.Samples) == 0 {
+		return 0
+	}
+	return t.Samples[len(t.Samples)-1].Time - t.Samples[0].Time
+}
+
+// WriteCSV writes the trace as CSV with a header row, with the time in seconds.
+func (t Trace) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(csvColumns); err != nil {
+		return fmt.Errorf("sim: write CSV: %w", err)
+	}
+	record := make([]string, len(csvColumns))
+	for _, sample := range t.Samples {
+		record[0] = strconv.FormatFloat(sample.Time.Seconds(), 'g', -1, 64)
+		for i, field := range sample.fields() {
+			record[i+1] = strconv.FormatFloat(*field, 'g', -1, 64)
+		}
+		if err := cw.Write(record); err != nil {
+			return fmt.Errorf("sim: write CSV: %w", err)
+		}
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return fmt.Errorf("sim: write CSV: %w", err)
+	}
+	return nil
+}
+
+// ReadCSV reads a trace from CSV with a header row, as written by Trace.WriteCSV.
+//
+// Only the time column is required. Missing columns are read as zero, and unknown columns are ignored, so that
+// logs from other sources can be read after renaming their columns.
+func ReadCSV(r io.Reader) (Trace, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	header, err := cr.Read()
+	if err != nil {
+		return Trace{}, fmt.Errorf("sim: read CSV: header: %w", err)
+	}
+	columns := make([]int, len(header))
+	var hasTime bool
+	for i, name := range header {
+		columns[i] = -1
+		for j, column := range csvColumns {
+			if name == column {
+				columns[i] = j
+				hasTime = hasTime || j == 0
+			}
+		}
+	}
+	if !hasTime {
+		return Trace{}, fmt.Errorf("sim: read CSV: missing time column")
+	}
+	var trace Trace
+	for line := 2; ; line++ {
+		record, err := cr.Read()
+		if errors.Is(err, io.EOF) {
+			return trace, nil
+		}
+		if err != nil {
+			return Trace{}, fmt.Errorf("sim: read CSV: %w", err)
+		}
+		var sample Sample
+		fields := sample.fields()
+		for i, value := range record {
+			if i >= len(columns) || columns[i] < 0 {
+				continue
+			}
+			x, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return Trace{}, fmt.Errorf("sim: read CSV: line %d: column %s: %w", line, header[i], err)
+			}
+			if columns[i] == 0 {
+				sample.Time = time.Duration(math.Round(x * float64(time.Second)))
+			} else {
+				*fields[columns[i]-1] = x
+			}
+		}
+		if n := len(trace.Samples); n > 0 && sample.Time < trace.Samples[n-1].Time {
+			return Trace{}, fmt.Errorf("sim: read CSV: line %d: time not increasing", line)
+		}
+		trace.Samples = append(trace.Samples, sample)
+	}
+}
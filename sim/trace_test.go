@@ -0,0 +1,66 @@
+package sim
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestTrace_CSVRoundTrip(t *testing.T) {
+	expected := Trace{
+		Samples: []Sample{
+			{Time: 0, ReferenceSignal: 1, ActualSignal: 0.5, ControlSignal: 2, UnsaturatedControlSignal: 3},
+			{
+				Time:                     10 * time.Millisecond,
+				ReferenceSignal:          1,
+				ActualSignal:             0.75,
+				FeedForwardSignal:        0.1,
+				ControlSignal:            1.5,
+				UnsaturatedControlSignal: 1.5,
+				ProportionalTerm:         0.25,
+				IntegralTerm:             1.125,
+				DerivativeTerm:           0.025,
+				Disturbance:              -0.5,
+			},
+		},
+	}
+	var b bytes.Buffer
+	assert.NilError(t, expected.WriteCSV(&b))
+	actual, err := ReadCSV(&b)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, expected, actual)
+}
+
+func TestReadCSV_PartialColumns(t *testing.T) {
+	// Given a log with a subset of the columns, in another order and with unknown columns
+	const data = "actual,vehicle,time,control\n1,a,0,2\n1.5,b,0.1,2.5\n"
+	// When reading the log
+	trace, err := ReadCSV(strings.NewReader(data))
+	// Then the known columns should be read
+	assert.NilError(t, err)
+	assert.DeepEqual(t, Trace{Samples: []Sample{
+		{Time: 0, ActualSignal: 1, ControlSignal: 2},
+		{Time: 100 * time.Millisecond, ActualSignal: 1.5, ControlSignal: 2.5},
+	}}, trace)
+}
+
+func TestReadCSV_Errors(t *testing.T) {
+	for _, tt := range []struct {
+		name          string
+		data          string
+		expectedError string
+	}{
+		{name: "empty", data: "", expectedError: "header"},
+		{name: "missing time", data: "actual\n1\n", expectedError: "missing time column"},
+		{name: "invalid value", data: "time,actual\n0,x\n", expectedError: "line 2: column actual"},
+		{name: "decreasing time", data: "time\n1\n0\n", expectedError: "line 3: time not increasing"},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ReadCSV(strings.NewReader(tt.data))
+			assert.ErrorContains(t, err, tt.expectedError)
+		})
+	}
+}
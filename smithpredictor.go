@@ -0,0 +1,163 @@
+package pid
+
+import (
+	"fmt"
+	"time"
+
+	"go.einride.tech/pid/model"
+)
+
+// SmithPredictor wraps a controller with an internal model of the process to compensate a long dead time of the
+// process.
+//
+// The wrapped controller is updated with the actual signal plus the difference between the outputs of the model
+// without and with dead time. With a perfect model, the controller thereby acts on a prediction of the actual
+// signal one dead time ahead, and the loop responds as the loop of the process without dead time delayed by the
+// dead time, while the measured actual signal still corrects for model errors and load disturbances.
+//
+// The model is driven by the control signal of the wrapped controller, or the applied control signal for a
+// TrackingController, held between updates. Updates of the wrapped controller should go through the
+// SmithPredictor, but its config may be changed directly.
+type SmithPredictor[Input any] struct {
+	// State of the SmithPredictor.
+	State SmithPredictorState
+
+	undelayed, delayed *model.Simulation
+	actualSignal       func(Input) (float64, time.Duration)
+	update             func(input Input, actualSignal float64) float64
+	reset              func()
+}
+
+// SmithPredictorState holds mutable state for a SmithPredictor.
+type SmithPredictorState struct {
+	// PredictedSignal is the output of the model without dead time.
+	PredictedSignal float64
+	// DelayedPredictedSignal is the output of the model with dead time, which predicts the actual signal.
+	DelayedPredictedSignal float64
+	// ModelError is the difference between the actual signal and the delayed predicted signal of the latest
+	// valid input, which is zero for a perfect model without disturbances.
+	ModelError float64
+	// ControlSignal is the control signal output of the wrapped controller, or the applied control signal for a
+	// TrackingController, which drives the model.
+	ControlSignal float64
+}
+
+// NewSmithPredictor wraps a Controller with a Smith predictor of the process model.
+func NewSmithPredictor(c *Controller, m model.Model) (*SmithPredictor[ControllerInput], error) {
+	return newSmithPredictor(
+		m,
+		func(input ControllerInput) (float64, time.Duration) {
+			return input.ActualSignal, input.SamplingInterval
+		},
+		func(input ControllerInput, actualSignal float64) float64 {
+			input.ActualSignal = actualSignal
+			c.Update(input)
+			return c.State.ControlSignal
+		},
+		c.Reset,
+	)
+}
+
+// NewAntiWindupSmithPredictor wraps an AntiWindupController with a Smith predictor of the process model.
+func NewAntiWindupSmithPredictor(
+	c *AntiWindupController, m model.Model,
+) (*SmithPredictor[AntiWindupControllerInput], error) {
+	return newSmithPredictor(
+		m,
+		func(input AntiWindupControllerInput) (float64, time.Duration) {
+			return input.ActualSignal, input.SamplingInterval
+		},
+		func(input AntiWindupControllerInput, actualSignal float64) float64 {
+			input.ActualSignal = actualSignal
+			c.Update(input)
+			return c.State.ControlSignal
+		},
+		c.Reset,
+	)
+}
+
+// NewTrackingSmithPredictor wraps a TrackingController with a Smith predictor of the process model.
+//
+// The model is driven by the applied control signal of the input of each update, which should be the control
+// signal applied to the process after the update.
+func NewTrackingSmithPredictor(
+	c *TrackingController, m model.Model,
+) (*SmithPredictor[TrackingControllerInput], error) {
+	return newSmithPredictor(
+		m,
+		func(input TrackingControllerInput) (float64, time.Duration) {
+			return input.ActualSignal, input.SamplingInterval
+		},
+		func(input TrackingControllerInput, actualSignal float64) float64 {
+			input.ActualSignal = actualSignal
+			c.Update(input)
+			return input.AppliedControlSignal
+		},
+		c.Reset,
+	)
+}
+
+// NewFractionalSmithPredictor wraps a FractionalController with a Smith predictor of the process model.
+func NewFractionalSmithPredictor(
+	c *FractionalController, m model.Model,
+) (*SmithPredictor[FractionalControllerInput], error) {
+	return newSmithPredictor(
+		m,
+		func(input FractionalControllerInput) (float64, time.Duration) {
+			return input.ActualSignal, input.SamplingInterval
+		},
+		func(input FractionalControllerInput, actualSignal float64) float64 {
+			input.ActualSignal = actualSignal
+			c.Update(input)
+			return c.State.ControlSignal
+		},
+		c.Reset,
+	)
+}
+
+func newSmithPredictor[Input any](
+	m model.Model,
+	actualSignal func(Input) (float64, time.Duration),
+	update func(Input, float64) float64,
+	reset func(),
+) (*SmithPredictor[Input], error) {
+	g := m.TransferFunction()
+	delayed, err := model.NewSimulation(g)
+	if err != nil {
+		return nil, fmt.Errorf("pid: new Smith predictor: %w", err)
+	}
+	g.DeadTime = 0
+	undelayed, err := model.NewSimulation(g)
+	if err != nil {
+		return nil, fmt.Errorf("pid: new Smith predictor: %w", err)
+	}
+	return &SmithPredictor[Input]{
+		undelayed:    undelayed,
+		delayed:      delayed,
+		actualSignal: actualSignal,
+		update:       update,
+		reset:        reset,
+	}, nil
+}
+
+// Reset the state of the Smith predictor, its model and the wrapped controller.
+func (p *SmithPredictor[Input]) Reset() {
+	p.reset()
+	p.undelayed.Reset()
+	p.delayed.Reset()
+	p.State = SmithPredictorState{}
+}
+
+// Update the model with the control signal of the previous update over the sampling interval, and update the
+// wrapped controller with the corrected actual signal.
+func (p *SmithPredictor[Input]) Update(input Input) {
+	actualSignal, samplingInterval := p.actualSignal(input)
+	if samplingInterval > 0 {
+		p.State.PredictedSignal = p.undelayed.Update(p.State.ControlSignal, samplingInterval)
+		p.State.DelayedPredictedSignal = p.delayed.Update(p.State.ControlSignal, samplingInterval)
+	}
+	if !isNaNOrInf(actualSignal) {
+		p.State.ModelError = actualSignal - p.State.DelayedPredictedSignal
+	}
+	p.State.ControlSignal = p.update(input, actualSignal+p.State.PredictedSignal-p.State.DelayedPredictedSignal)
+}
@@ -0,0 +1,154 @@
+package pid
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"go.einride.tech/pid/model"
+	"gotest.tools/v3/assert"
+)
+
+func TestSmithPredictor_Update(t *testing.T) {
+	// Given a dead-time dominant process, and a controller tuned for the process without dead time
+	const dt = 100 * time.Millisecond
+	const delay = 50 // samples of dead time
+	process := model.FOPDT{Gain: 1, TimeConstant: 2 * time.Second, DeadTime: delay * dt}
+	config := AntiWindupControllerConfig{
+		ProportionalGain:    2,
+		IntegralGain:        1,
+		LowPassTimeConstant: time.Second,
+		MinOutput:           -5,
+		MaxOutput:           5,
+	}
+	// When controlling the process with a Smith predictor with a perfect model
+	p, err := NewAntiWindupSmithPredictor(&AntiWindupController{Config: config}, process)
+	assert.NilError(t, err)
+	plant, err := model.NewSimulation(process)
+	assert.NilError(t, err)
+	var actual []float64
+	for range 400 {
+		actual = append(actual, plant.Output())
+		p.Update(AntiWindupControllerInput{ReferenceSignal: 1, ActualSignal: plant.Output(), SamplingInterval: dt})
+		plant.Update(p.State.ControlSignal, dt)
+		assert.Assert(t, math.Abs(p.State.ModelError) < 1e-12)
+	}
+	// And controlling the process without dead time with the controller alone
+	undelayedProcess := process
+	undelayedProcess.DeadTime = 0
+	c := &AntiWindupController{Config: config}
+	plant, err = model.NewSimulation(undelayedProcess)
+	assert.NilError(t, err)
+	var undelayed []float64
+	for range 400 {
+		undelayed = append(undelayed, plant.Output())
+		c.Update(AntiWindupControllerInput{ReferenceSignal: 1, ActualSignal: plant.Output(), SamplingInterval: dt})
+		plant.Update(c.State.ControlSignal, dt)
+	}
+	// Then the loop with the Smith predictor should respond as the loop without dead time, delayed
+	for k := delay; k < len(actual); k++ {
+		assert.Assert(t, math.Abs(actual[k]-undelayed[k-delay]) < 1e-9, k)
+	}
+	assert.Assert(t, math.Abs(actual[len(actual)-1]-1) < 1e-3)
+}
+
+func TestSmithPredictor_Update_ModelMismatch(t *testing.T) {
+	// Given a Smith predictor with a model of too low gain and too short dead time
+	const dt = 100 * time.Millisecond
+	m := model.FOPDT{Gain: 0.8, TimeConstant: 2 * time.Second, DeadTime: 4 * time.Second}
+	p, err := NewSmithPredictor(&Controller{Config: ControllerConfig{ProportionalGain: 1, IntegralGain: 0.5}}, m)
+	assert.NilError(t, err)
+	plant, err := model.NewSimulation(model.FOPDT{Gain: 1, TimeConstant: 2 * time.Second, DeadTime: 5 * time.Second})
+	assert.NilError(t, err)
+	var maxModelError float64
+	for range 1000 {
+		p.Update(ControllerInput{ReferenceSignal: 1, ActualSignal: plant.Output(), SamplingInterval: dt})
+		plant.Update(p.State.ControlSignal, dt)
+		maxModelError = math.Max(maxModelError, math.Abs(p.State.ModelError))
+	}
+	// Then the model error should be observed
+	assert.Assert(t, maxModelError > 0.1, maxModelError)
+	// And the integral action should still remove the control error
+	assert.Assert(t, math.Abs(plant.Output()-1) < 1e-3, plant.Output())
+}
+
+func TestSmithPredictor_Update_NaN(t *testing.T) {
+	// Given a Smith predictor with state
+	c := &TrackingController{Config: TrackingControllerConfig{
+		ProportionalGain:    1,
+		LowPassTimeConstant: time.Second,
+		MinOutput:           -1,
+		MaxOutput:           1,
+	}}
+	p, err := NewTrackingSmithPredictor(c, model.FOPDT{Gain: 1, TimeConstant: time.Second, DeadTime: time.Second})
+	assert.NilError(t, err)
+	input := TrackingControllerInput{
+		ReferenceSignal:      0.5,
+		AppliedControlSignal: 0.5,
+		SamplingInterval:     100 * time.Millisecond,
+	}
+	p.Update(input)
+	p.Update(input)
+	expected := p.State
+	// When updating with an invalid actual signal
+	input.ActualSignal = math.NaN()
+	p.Update(input)
+	// Then the model should still advance, while the model error and control signal are unchanged
+	assert.Assert(t, p.State.PredictedSignal > expected.PredictedSignal)
+	assert.Equal(t, expected.ModelError, p.State.ModelError)
+	assert.Equal(t, expected.ControlSignal, p.State.ControlSignal)
+}
+
+func TestTrackingSmithPredictor_AppliedControlSignal(t *testing.T) {
+	// Given a Smith predictor of a tracking controller in manual mode, with a constant applied control signal
+	c := &TrackingController{Config: TrackingControllerConfig{
+		ProportionalGain:    1,
+		IntegralGain:        1,
+		LowPassTimeConstant: time.Second,
+		MinOutput:           -1,
+		MaxOutput:           1,
+	}}
+	p, err := NewTrackingSmithPredictor(c, model.FOPDT{Gain: 2, TimeConstant: time.Second, DeadTime: time.Second})
+	assert.NilError(t, err)
+	// When updating
+	for range 200 {
+		p.Update(TrackingControllerInput{
+			ReferenceSignal:      1,
+			AppliedControlSignal: 0.25,
+			SamplingInterval:     100 * time.Millisecond,
+		})
+	}
+	// Then the model should be driven by the applied control signal
+	assert.Equal(t, 0.25, p.State.ControlSignal)
+	assert.Assert(t, math.Abs(p.State.DelayedPredictedSignal-0.5) < 1e-6, p.State.DelayedPredictedSignal)
+	assert.Assert(t, c.State.ControlSignal != 0.25)
+}
+
+func TestSmithPredictor_Reset(t *testing.T) {
+	// Given a Smith predictor with state
+	c := &FractionalController{Config: FractionalControllerConfig{
+		ProportionalGain: 1,
+		IntegralOrder:    1,
+		DerivativeOrder:  1,
+		MinOutput:        -1,
+		MaxOutput:        1,
+	}}
+	p, err := NewFractionalSmithPredictor(c, model.FOPDT{Gain: 1, TimeConstant: time.Second, DeadTime: time.Second})
+	assert.NilError(t, err)
+	for range 20 {
+		p.Update(FractionalControllerInput{ReferenceSignal: 1, SamplingInterval: 100 * time.Millisecond})
+	}
+	// When resetting the Smith predictor
+	p.Reset()
+	// Then the state of the predictor and the wrapped controller should be reset
+	assert.Equal(t, SmithPredictorState{}, p.State)
+	assert.Equal(t, 0.0, c.State.ControlSignal)
+	// And the model should restart at rest
+	p.Update(FractionalControllerInput{SamplingInterval: 100 * time.Millisecond})
+	assert.Equal(t, 0.0, p.State.PredictedSignal)
+}
+
+func TestNewSmithPredictor_InvalidModel(t *testing.T) {
+	_, err := NewSmithPredictor(&Controller{}, model.TransferFunction{Numerator: []float64{1}})
+	assert.ErrorContains(t, err, "pid: new Smith predictor: model: new simulation:")
+}
@@ -0,0 +1,234 @@
+package pid
+
+import (
+	"fmt"
+	"math"
+	"slices"
+)
+
+// StictionAnalysis holds the result of an actuator stiction and backlash analysis.
+//
+// The analysis assumes a loop where the actual signal responds quickly to the actuator position, such as a flow
+// loop, so that the actual signal can be used as a proxy for the actuator position.
+type StictionAnalysis struct {
+	// Oscillating is true when the control signal oscillates enough for the analysis to be meaningful.
+	// All other fields are zero when Oscillating is false.
+	Oscillating bool
+	// CrossCorrelationIndex is 1 - |r(0)|/max|r(τ)| of the cross-correlation r between the control signal and the
+	// actual signal. An odd cross-correlation, with an index close to 1, indicates stiction, while an even
+	// cross-correlation, with an index close to 0, indicates a linear cause of the oscillation.
+	//
+	// See Horch, A simple method for detection of stiction in control valves, 1999
+	// (https://doi.org/10.1016/S0967-0661(99)00111-8).
+	CrossCorrelationIndex float64
+	// ShapeIndex is the fraction of the total variation of the actual signal that occurs in abrupt jumps. The
+	// stick-slip motion of a sticky actuator gives a square-shaped actual signal with an index close to 1, while
+	// backlash and linear oscillations give smooth actual signals with an index close to 0.
+	ShapeIndex float64
+	// DeadBand is the median change of the control signal after each reversal before the actual signal starts
+	// to follow, relative to the peak-to-peak amplitude of the control signal.
+	DeadBand float64
+	// Stiction is the confidence in [0, 1] that the actuator exhibits stiction.
+	Stiction float64
+	// Backlash is the confidence in [0, 1] that the actuator exhibits backlash.
+	Backlash float64
+}
+
+const (
+	// stictionMinZeroCrossings is the number of zero crossings of the control signal needed for the analysis.
+	stictionMinZeroCrossings = 4
+	// stictionJumpRatio is the ratio between the rate of change of the actual signal and its mean rate of change
+	// above which the actual signal is considered to jump.
+	stictionJumpRatio = 4
+	// stictionMovingFraction is the fraction of the peak-to-peak amplitude above which a signal is moving.
+	stictionMovingFraction = 0.01
+	// stictionDeadBandSaturation is the relative dead band above which a dead band is certain.
+	stictionDeadBandSaturation = 0.1
+)
+
+// AnalyzeStiction analyses traces of the control signal and the actual signal of an oscillating loop, and
+// estimates whether the actuator exhibits stiction or backlash.
+//
+// The traces must be sampled at a fixed interval and cover several periods of the oscillation.
+func AnalyzeStiction(controlSignal, actualSignal []float64) (StictionAnalysis, error) {
+	if len(controlSignal) != len(actualSignal) {
+		return StictionAnalysis{}, fmt.Errorf(
+			"pid: analyze stiction: mismatched trace lengths %d and %d", len(controlSignal), len(actualSignal),
+		)
+	}
+	for i := range controlSignal {
+		if math.IsNaN(controlSignal[i]) || math.IsInf(controlSignal[i], 0) ||
+			math.IsNaN(actualSignal[i]) || math.IsInf(actualSignal[i], 0) {
+			return StictionAnalysis{}, fmt.Errorf("pid: analyze stiction: invalid sample %d", i)
+		}
+	}
+	u, y := removeMean(controlSignal), removeMean(actualSignal)
+	crossings := zeroCrossings(u)
+	uRange, yRange := peakToPeak(u), peakToPeak(y)
+	if len(crossings) < stictionMinZeroCrossings || uRange == 0 || yRange == 0 {
+		return StictionAnalysis{}, nil
+	}
+	result := StictionAnalysis{Oscillating: true}
+	// Compute cross-correlation up to a lag of one estimated oscillation period.
+	period := 2 * (crossings[len(crossings)-1] - crossings[0]) / (len(crossings) - 1)
+	r0 := crossCorrelation(u, y, 0)
+	var rMax float64
+	for lag := -period; lag <= period; lag++ {
+		rMax = math.Max(rMax, math.Abs(crossCorrelation(u, y, lag)))
+	}
+	if rMax > 0 {
+		result.CrossCorrelationIndex = 1 - math.Abs(r0)/rMax
+	}
+	// Compute the fraction of the total variation of the actual signal occurring in jumps, where the actual
+	// signal moves much faster than its mean rate of change.
+	var totalVariation, jumpVariation float64
+	for i := 1; i < len(y); i++ {
+		totalVariation += math.Abs(y[i] - y[i-1])
+	}
+	meanVariation := totalVariation / float64(len(y)-1)
+	for i := 1; i < len(y); i++ {
+		if dy := math.Abs(y[i] - y[i-1]); dy > stictionJumpRatio*meanVariation {
+			jumpVariation += dy
+		}
+	}
+	if totalVariation > 0 {
+		result.ShapeIndex = jumpVariation / totalVariation
+	}
+	result.DeadBand = deadBand(u, y, uRange, yRange) / uRange
+	deadBandScore := math.Min(1, result.DeadBand/stictionDeadBandSaturation)
+	// Both stiction and backlash give a dead band, which for stiction is followed by a jump.
+	result.Stiction = deadBandScore * (2*result.ShapeIndex + result.CrossCorrelationIndex) / 3
+	result.Backlash = deadBandScore * (1 - result.ShapeIndex)
+	return result, nil
+}
+
+// deadBand returns the median travel of u after each reversal of u before y starts to move in the same direction.
+func deadBand(u, y []float64, uRange, yRange float64) float64 {
+	var deadBands []float64
+	direction, extreme := 0.0, 0
+	for i := 1; i < len(u); i++ {
+		switch {
+		case direction == 0 && math.Abs(u[i]-u[0]) > stictionMovingFraction*uRange:
+			direction = math.Copysign(1, u[i]-u[0])
+			extreme = i
+		case direction != 0 && (u[i]-u[extreme])*direction > 0:
+			extreme = i
+		case direction != 0 && (u[extreme]-u[i])*direction > stictionMovingFraction*uRange:
+			// The control signal reversed at the extreme, measure its travel until the actual signal follows.
+			direction = -direction
+			for j := extreme; j < len(y); j++ {
+				if (y[j]-y[extreme])*direction > stictionMovingFraction*yRange {
+					deadBands = append(deadBands, math.Abs(u[j]-u[extreme]))
+					break
+				}
+			}
+			extreme = i
+		}
+	}
+	if len(deadBands) == 0 {
+		return 0
+	}
+	slices.Sort(deadBands)
+	return deadBands[len(deadBands)/2]
+}
+
+func removeMean(x []float64) []float64 {
+	var mean float64
+	for _, xi := range x {
+		mean += xi
+	}
+	mean /= float64(len(x))
+	result := make([]float64, len(x))
+	for i, xi := range x {
+		result[i] = xi - mean
+	}
+	return result
+}
+
+func zeroCrossings(x []float64) []int {
+	var result []int
+	var sign float64
+	for i, xi := range x {
+		if xi == 0 {
+			continue
+		}
+		if xi*sign < 0 {
+			result = append(result, i)
+		}
+		sign = xi
+	}
+	return result
+}
+
+func peakToPeak(x []float64) float64 {
+	if len(x) == 0 {
+		return 0
+	}
+	return slices.Max(x) - slices.Min(x)
+}
+
+func crossCorrelation(u, y []float64, lag int) float64 {
+	var result float64
+	for i := range u {
+		if j := i + lag; j >= 0 && j < len(y) {
+			result += u[i] * y[j]
+		}
+	}
+	return result / float64(len(u))
+}
+
+// StictionBuffer is a fixed-capacity ring buffer of recent control signal and actual signal samples, for live
+// stiction analysis of a running loop.
+//
+// The zero value is not usable, create a StictionBuffer with NewStictionBuffer.
+type StictionBuffer struct {
+	controlSignal []float64
+	actualSignal  []float64
+	next          int
+	full          bool
+}
+
+// NewStictionBuffer creates a new StictionBuffer holding up to capacity samples.
+func NewStictionBuffer(capacity int) *StictionBuffer {
+	return &StictionBuffer{
+		controlSignal: make([]float64, capacity),
+		actualSignal:  make([]float64, capacity),
+	}
+}
+
+// Add a sample to the buffer, overwriting the oldest sample when the buffer is full.
+//
+// The control signal is typically taken from the controller State after each update, and the actual signal
+// from the corresponding controller input.
+func (b *StictionBuffer) Add(controlSignal, actualSignal float64) {
+	if len(b.controlSignal) == 0 {
+		return
+	}
+	b.controlSignal[b.next] = controlSignal
+	b.actualSignal[b.next] = actualSignal
+	b.next++
+	if b.next == len(b.controlSignal) {
+		b.next = 0
+		b.full = true
+	}
+}
+
+// Len returns the number of samples in the buffer.
+func (b *StictionBuffer) Len() int {
+	if b.full {
+		return len(b.controlSignal)
+	}
+	return b.next
+}
+
+// Analyze the samples in the buffer, see AnalyzeStiction.
+func (b *StictionBuffer) Analyze() (StictionAnalysis, error) {
+	return AnalyzeStiction(b.ordered(b.controlSignal), b.ordered(b.actualSignal))
+}
+
+func (b *StictionBuffer) ordered(x []float64) []float64 {
+	if !b.full {
+		return slices.Clone(x[:b.next])
+	}
+	return append(slices.Clone(x[b.next:]), x[:b.next]...)
+}
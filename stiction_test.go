@@ -0,0 +1,130 @@
+package pid
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+// simulateStictionLoop simulates a PI-controlled flow loop where the actuator position is given by the provided
+// actuator model, and returns the control signal and actual signal traces.
+func simulateStictionLoop(actuator func(controlSignal float64) float64) (controlSignal, actualSignal []float64) {
+	c := &AntiWindupController{
+		Config: AntiWindupControllerConfig{
+			LowPassTimeConstant: 1 * time.Second,
+			ProportionalGain:    0.5,
+			IntegralGain:        2,
+			MinOutput:           -100,
+			MaxOutput:           100,
+		},
+	}
+	const timeConstant = 0.05
+	var y float64
+	for range 3000 {
+		c.Update(AntiWindupControllerInput{
+			ReferenceSignal:  1,
+			ActualSignal:     y,
+			SamplingInterval: dtTest,
+		})
+		y += (actuator(c.State.ControlSignal) - y) * dtTest.Seconds() / timeConstant
+		controlSignal = append(controlSignal, c.State.ControlSignal)
+		actualSignal = append(actualSignal, y)
+	}
+	return controlSignal, actualSignal
+}
+
+func TestAnalyzeStiction_Stiction(t *testing.T) {
+	// Given a loop with a sticky actuator which slips when the control signal has moved by more than 0.5
+	var position float64
+	u, y := simulateStictionLoop(func(controlSignal float64) float64 {
+		if math.Abs(controlSignal-position) > 0.5 {
+			position = controlSignal
+		}
+		return position
+	})
+	// When
+	result, err := AnalyzeStiction(u, y)
+	assert.NilError(t, err)
+	// Then
+	assert.Assert(t, result.Oscillating)
+	assert.Assert(t, result.Stiction > 0.6)
+	assert.Assert(t, result.Backlash < 0.4)
+}
+
+func TestAnalyzeStiction_Backlash(t *testing.T) {
+	// Given an actuator with a backlash of 0.4 driven by a sinusoidal control signal
+	var position float64
+	var u, y []float64
+	for i := range 3000 {
+		controlSignal := math.Sin(2 * math.Pi * float64(i) / 500)
+		if controlSignal-position > 0.2 {
+			position = controlSignal - 0.2
+		} else if controlSignal-position < -0.2 {
+			position = controlSignal + 0.2
+		}
+		u = append(u, controlSignal)
+		y = append(y, position)
+	}
+	// When
+	result, err := AnalyzeStiction(u, y)
+	assert.NilError(t, err)
+	// Then
+	assert.Assert(t, result.Oscillating)
+	assert.Assert(t, math.Abs(result.DeadBand-0.2) < 0.02)
+	assert.Assert(t, result.Backlash > 0.6)
+	assert.Assert(t, result.Stiction < 0.4)
+}
+
+func TestAnalyzeStiction_Linear(t *testing.T) {
+	// Given a linear actuator driven by a sinusoidal control signal
+	var u, y []float64
+	for i := range 3000 {
+		u = append(u, math.Sin(2*math.Pi*float64(i)/500))
+		y = append(y, 2*math.Sin(2*math.Pi*float64(i-5)/500))
+	}
+	// When
+	result, err := AnalyzeStiction(u, y)
+	assert.NilError(t, err)
+	// Then
+	assert.Assert(t, result.Oscillating)
+	assert.Assert(t, result.Stiction < 0.4)
+	assert.Assert(t, result.Backlash < 0.4)
+}
+
+func TestAnalyzeStiction_NotOscillating(t *testing.T) {
+	result, err := AnalyzeStiction([]float64{1, 2, 3}, []float64{1, 2, 3})
+	assert.NilError(t, err)
+	assert.Equal(t, StictionAnalysis{}, result)
+}
+
+func TestAnalyzeStiction_Errors(t *testing.T) {
+	_, err := AnalyzeStiction([]float64{1, 2}, []float64{1})
+	assert.ErrorContains(t, err, "mismatched trace lengths")
+	_, err = AnalyzeStiction([]float64{1, math.NaN()}, []float64{1, 2})
+	assert.ErrorContains(t, err, "invalid sample")
+}
+
+func TestStictionBuffer(t *testing.T) {
+	// Given a stiction buffer fed from a running loop with a sticky actuator
+	var position float64
+	u, y := simulateStictionLoop(func(controlSignal float64) float64 {
+		if math.Abs(controlSignal-position) > 0.5 {
+			position = controlSignal
+		}
+		return position
+	})
+	b := NewStictionBuffer(2000)
+	for i := range u {
+		b.Add(u[i], y[i])
+	}
+	assert.Equal(t, 2000, b.Len())
+	// When analyzing the buffer
+	actual, err := b.Analyze()
+	assert.NilError(t, err)
+	// Then the result should equal the analysis of the latest samples
+	expected, err := AnalyzeStiction(u[len(u)-2000:], y[len(y)-2000:])
+	assert.NilError(t, err)
+	assert.Equal(t, expected, actual)
+}
@@ -1,7 +1,6 @@
 package pid
 
 import (
-	"math"
 	"time"
 )
 
@@ -15,96 +14,122 @@ import (
 //
 // The ControlError, ControlErrorIntegrand, ControlErrorIntegral and ControlErrorDerivative are prevented
 // from reaching +/- inf by clamping them to [-math.MaxFloat64, math.MaxFloat64].
-type TrackingController struct {
+type TrackingController = TrackingControllerOf[float64]
+
+// TrackingControllerConfig contains configurable parameters for a TrackingController.
+type TrackingControllerConfig = TrackingControllerConfigOf[float64]
+
+// TrackingControllerState holds the mutable state a TrackingController.
+type TrackingControllerState = TrackingControllerStateOf[float64]
+
+// TrackingControllerInput holds the input parameters to a TrackingController.
+type TrackingControllerInput = TrackingControllerInputOf[float64]
+
+// TrackingControllerOf implements a TrackingController generic over the floating-point type T.
+//
+// The ControlError, ControlErrorIntegrand, ControlErrorIntegral and ControlErrorDerivative are prevented
+// from reaching +/- inf by clamping them to the largest finite value of T.
+type TrackingControllerOf[T Float] struct {
 	// Config for the TrackingController.
-	Config TrackingControllerConfig
+	Config TrackingControllerConfigOf[T]
 	// State of the TrackingController.
-	State TrackingControllerState
+	State TrackingControllerStateOf[T]
 }
 
-// TrackingControllerConfig contains configurable parameters for a TrackingController.
-type TrackingControllerConfig struct {
+// TrackingControllerConfigOf contains configurable parameters for a TrackingControllerOf.
+type TrackingControllerConfigOf[T Float] struct {
 	// ProportionalGain is the P part gain.
-	ProportionalGain float64
+	ProportionalGain T
 	// IntegralGain is the I part gain.
-	IntegralGain float64
+	IntegralGain T
 	// DerivativeGain is the D part gain.
-	DerivativeGain float64
+	DerivativeGain T
 	// AntiWindUpGain is the anti-windup tracking gain.
-	AntiWindUpGain float64
+	AntiWindUpGain T
 	// IntegralDischargeTimeConstant is the time constant to discharge the integral state of the PID controller (s)
-	IntegralDischargeTimeConstant float64
+	IntegralDischargeTimeConstant T
 	// LowPassTimeConstant is the D part low-pass filter time constant => cut-off frequency 1/LowPassTimeConstant.
 	LowPassTimeConstant time.Duration
 	// MaxOutput is the max output from the PID.
-	MaxOutput float64
+	MaxOutput T
 	// MinOutput is the min output from the PID.
-	MinOutput float64
+	MinOutput T
 }
 
-// TrackingControllerState holds the mutable state a TrackingController.
-type TrackingControllerState struct {
+// TrackingControllerStateOf holds the mutable state a TrackingControllerOf.
+type TrackingControllerStateOf[T Float] struct {
 	// ControlError is the difference between reference and current value.
-	ControlError float64
+	ControlError T
 	// ControlErrorIntegrand is the integrated control error over time.
-	ControlErrorIntegrand float64
+	ControlErrorIntegrand T
 	// ControlErrorIntegral is the control error integrand integrated over time.
-	ControlErrorIntegral float64
+	ControlErrorIntegral T
 	// ControlErrorDerivative is the low-pass filtered time-derivative of the control error.
-	ControlErrorDerivative float64
+	ControlErrorDerivative T
 	// ControlSignal is the current control signal output of the controller.
-	ControlSignal float64
+	ControlSignal T
 	// UnsaturatedControlSignal is the control signal before saturation used for tracking the
 	// actual control signal for bumpless transfer or compensation of un-modeled saturations.
-	UnsaturatedControlSignal float64
+	UnsaturatedControlSignal T
 }
 
-// TrackingControllerInput holds the input parameters to a TrackingController.
-type TrackingControllerInput struct {
+// TrackingControllerInputOf holds the input parameters to a TrackingControllerOf.
+type TrackingControllerInputOf[T Float] struct {
 	// ReferenceSignal is the reference value for the signal to control.
-	ReferenceSignal float64
+	ReferenceSignal T
 	// ActualSignal is the actual value of the signal to control.
-	ActualSignal float64
+	ActualSignal T
 	// FeedForwardSignal is the contribution of the feed-forward control loop in the controller output.
-	FeedForwardSignal float64
+	FeedForwardSignal T
 	// AppliedControlSignal is the actual control command applied by the actuator.
-	AppliedControlSignal float64
+	AppliedControlSignal T
 	// SamplingInterval is the time interval elapsed since the previous call of the controller Update method.
 	SamplingInterval time.Duration
 }
 
 // Reset the controller state.
-func (c *TrackingController) Reset() {
-	c.State = TrackingControllerState{}
+func (c *TrackingControllerOf[T]) Reset() {
+	c.State = TrackingControllerStateOf[T]{}
 }
 
 // Update the controller state.
-func (c *TrackingController) Update(input TrackingControllerInput) {
-	if math.IsNaN(input.ReferenceSignal) || math.IsNaN(input.ActualSignal) ||
-		math.IsInf(input.ReferenceSignal, 0) || math.IsInf(input.ActualSignal, 0) {
+func (c *TrackingControllerOf[T]) Update(input TrackingControllerInputOf[T]) {
+	if isNaNOrInf(input.ReferenceSignal) || isNaNOrInf(input.ActualSignal) {
 		return
 	}
+	dt := T(input.SamplingInterval.Seconds())
+	lowPassTimeConstant := T(c.Config.LowPassTimeConstant.Seconds())
 	e := input.ReferenceSignal - input.ActualSignal
-	controlErrorIntegral := c.State.ControlErrorIntegrand*input.SamplingInterval.Seconds() + c.State.ControlErrorIntegral
-	controlErrorDerivative := ((1/c.Config.LowPassTimeConstant.Seconds())*(e-c.State.ControlError) +
-		c.State.ControlErrorDerivative) / (input.SamplingInterval.Seconds()/c.Config.LowPassTimeConstant.Seconds() + 1)
+	controlErrorIntegral := c.State.ControlErrorIntegrand*dt + c.State.ControlErrorIntegral
+	controlErrorDerivative := ((1/lowPassTimeConstant)*(e-c.State.ControlError) +
+		c.State.ControlErrorDerivative) / (dt/lowPassTimeConstant + 1)
 	c.State.UnsaturatedControlSignal = e*c.Config.ProportionalGain + c.Config.IntegralGain*controlErrorIntegral +
 		c.Config.DerivativeGain*controlErrorDerivative + input.FeedForwardSignal
-	c.State.ControlSignal = math.Max(c.Config.MinOutput, math.Min(c.Config.MaxOutput, c.State.UnsaturatedControlSignal))
+	c.State.ControlSignal = clamp(c.State.UnsaturatedControlSignal, c.Config.MinOutput, c.Config.MaxOutput)
 	c.State.ControlErrorIntegrand = e + c.Config.AntiWindUpGain*(input.AppliedControlSignal-
 		c.State.UnsaturatedControlSignal)
-	c.State.ControlErrorIntegrand = math.Max(-math.MaxFloat64, math.Min(math.MaxFloat64, c.State.ControlErrorIntegrand))
-	c.State.ControlErrorIntegral = math.Max(-math.MaxFloat64, math.Min(math.MaxFloat64, controlErrorIntegral))
-	c.State.ControlErrorDerivative = math.Max(-math.MaxFloat64, math.Min(math.MaxFloat64, controlErrorDerivative))
-	c.State.ControlError = math.Max(-math.MaxFloat64, math.Min(math.MaxFloat64, e))
+	c.State.ControlErrorIntegrand = clampFinite(c.State.ControlErrorIntegrand)
+	c.State.ControlErrorIntegral = clampFinite(controlErrorIntegral)
+	c.State.ControlErrorDerivative = clampFinite(controlErrorDerivative)
+	c.State.ControlError = clampFinite(e)
 }
 
 // DischargeIntegral provides the ability to discharge the controller integral state
 // over a configurable period of time.
-func (c *TrackingController) DischargeIntegral(dt time.Duration) {
+func (c *TrackingControllerOf[T]) DischargeIntegral(dt time.Duration) {
 	c.State.ControlErrorIntegrand = 0.0
-	c.State.ControlErrorIntegral = math.Max(
-		0,
-		math.Min(1-dt.Seconds()/c.Config.IntegralDischargeTimeConstant, 1.0),
+	c.State.ControlErrorIntegral = clamp(
+		1-T(dt.Seconds())/c.Config.IntegralDischargeTimeConstant, 0, 1.0,
 	) * c.State.ControlErrorIntegral
 }
+
+// BumplessTransfer swaps the controller config without a bump in the control signal, by adjusting the integral
+// state in the same way as AntiWindupControllerOf.BumplessTransfer.
+func (c *TrackingControllerOf[T]) BumplessTransfer(config TrackingControllerConfigOf[T]) {
+	c.State.ControlErrorIntegral = bumplessIntegral(
+		c.Config.ProportionalGain, c.Config.IntegralGain, c.Config.DerivativeGain,
+		config.ProportionalGain, config.IntegralGain, config.DerivativeGain,
+		c.State.ControlError, c.State.ControlErrorIntegral, c.State.ControlErrorDerivative,
+	)
+	c.Config = config
+}
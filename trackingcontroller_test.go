@@ -179,3 +179,15 @@ func TestTrackingController_OffloadIntegralTerm(t *testing.T) {
 	}
 	assert.Equal(t, expected, c.State)
 }
+
+func TestTrackingController_BumplessTransfer(t *testing.T) {
+	// Given a PI controller with a charged integral
+	c := &TrackingController{
+		Config: TrackingControllerConfig{ProportionalGain: 1, IntegralGain: 1},
+		State:  TrackingControllerState{ControlError: 1, ControlErrorIntegral: 2},
+	}
+	// When doubling the gains with bumpless transfer
+	c.BumplessTransfer(TrackingControllerConfig{ProportionalGain: 2, IntegralGain: 2})
+	// Then the integral should compensate for the increased P and I terms
+	assert.Equal(t, 0.5, c.State.ControlErrorIntegral)
+}
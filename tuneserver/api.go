@@ -0,0 +1,161 @@
+package tuneserver
+
+import (
+	"math"
+	"time"
+
+	"go.einride.tech/pid"
+)
+
+// Controller is the JSON representation of a registered controller.
+type Controller struct {
+	// Name of the controller.
+	Name string `json:"name"`
+	// Type of the controller, "antiWindup" or "tracking".
+	Type string `json:"type"`
+	// Config of the controller.
+	Config Config `json:"config"`
+	// State of the controller.
+	State State `json:"state"`
+	// Bounds of gain changes.
+	Bounds Bounds `json:"bounds"`
+}
+
+// Gains are the tunable gains of a controller.
+type Gains struct {
+	// ProportionalGain is the P part gain.
+	ProportionalGain float64 `json:"proportionalGain"`
+	// IntegralGain is the I part gain.
+	IntegralGain float64 `json:"integralGain"`
+	// DerivativeGain is the D part gain.
+	DerivativeGain float64 `json:"derivativeGain"`
+	// AntiWindUpGain is the anti-windup tracking gain.
+	AntiWindUpGain float64 `json:"antiWindUpGain"`
+}
+
+// Config is the JSON representation of a controller config.
+type Config struct {
+	Gains
+	// IntegralDischargeTimeConstant is the time constant to discharge the integral state (s).
+	IntegralDischargeTimeConstant float64 `json:"integralDischargeTimeConstant"`
+	// LowPassTimeConstant is the D part low-pass filter time constant.
+	LowPassTimeConstant time.Duration `json:"lowPassTimeConstant"`
+	// MaxOutput is the max output, or null when the output is unbounded.
+	MaxOutput *float64 `json:"maxOutput"`
+	// MinOutput is the min output, or null when the output is unbounded.
+	MinOutput *float64 `json:"minOutput"`
+}
+
+// State is the JSON representation of a controller state.
+type State struct {
+	ControlError             float64 `json:"controlError"`
+	ControlErrorIntegrand    float64 `json:"controlErrorIntegrand"`
+	ControlErrorIntegral     float64 `json:"controlErrorIntegral"`
+	ControlErrorDerivative   float64 `json:"controlErrorDerivative"`
+	ControlSignal            float64 `json:"controlSignal"`
+	UnsaturatedControlSignal float64 `json:"unsaturatedControlSignal"`
+}
+
+// Sample is a state sample of a stream.
+type Sample struct {
+	// Time of the sample.
+	Time time.Time `json:"time"`
+	// State of the controller.
+	State State `json:"state"`
+}
+
+// GainChange is a request to change the gains of a controller. Gains that are not set are left unchanged.
+type GainChange struct {
+	// User is the identity of the requester, recorded in the audit log. Required.
+	User string `json:"user"`
+	// Reason for the change, recorded in the audit log.
+	Reason string `json:"reason,omitempty"`
+	// ProportionalGain is the new P part gain.
+	ProportionalGain *float64 `json:"proportionalGain,omitempty"`
+	// IntegralGain is the new I part gain.
+	IntegralGain *float64 `json:"integralGain,omitempty"`
+	// DerivativeGain is the new D part gain.
+	DerivativeGain *float64 `json:"derivativeGain,omitempty"`
+	// AntiWindUpGain is the new anti-windup tracking gain.
+	AntiWindUpGain *float64 `json:"antiWindUpGain,omitempty"`
+}
+
+// AuditEntry is an entry of the audit log, recording a change request.
+type AuditEntry struct {
+	// Time of the request.
+	Time time.Time `json:"time"`
+	// User that made the request.
+	User string `json:"user"`
+	// Reason given for the change.
+	Reason string `json:"reason,omitempty"`
+	// Controller is the name of the controller.
+	Controller string `json:"controller"`
+	// Old gains of the controller.
+	Old Gains `json:"old"`
+	// New gains requested.
+	New Gains `json:"new"`
+	// Applied is true when the change was applied.
+	Applied bool `json:"applied"`
+	// Error is the reason the change was rejected.
+	Error string `json:"error,omitempty"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+func gainsOf(c pid.AntiWindupControllerConfig) Gains {
+	return Gains{
+		ProportionalGain: c.ProportionalGain,
+		IntegralGain:     c.IntegralGain,
+		DerivativeGain:   c.DerivativeGain,
+		AntiWindUpGain:   c.AntiWindUpGain,
+	}
+}
+
+func configOf(c pid.AntiWindupControllerConfig) Config {
+	return Config{
+		Gains:                         gainsOf(c),
+		IntegralDischargeTimeConstant: c.IntegralDischargeTimeConstant,
+		LowPassTimeConstant:           c.LowPassTimeConstant,
+		MaxOutput:                     finite(c.MaxOutput),
+		MinOutput:                     finite(c.MinOutput),
+	}
+}
+
+func stateOf(s pid.AntiWindupControllerState) State {
+	return State{
+		ControlError:             s.ControlError,
+		ControlErrorIntegrand:    s.ControlErrorIntegrand,
+		ControlErrorIntegral:     s.ControlErrorIntegral,
+		ControlErrorDerivative:   s.ControlErrorDerivative,
+		ControlSignal:            s.ControlSignal,
+		UnsaturatedControlSignal: s.UnsaturatedControlSignal,
+	}
+}
+
+// apply the gains of the change to a config.
+func (c GainChange) apply(config pid.AntiWindupControllerConfig) pid.AntiWindupControllerConfig {
+	for _, gain := range []struct {
+		value  *float64
+		result *float64
+	}{
+		{c.ProportionalGain, &config.ProportionalGain},
+		{c.IntegralGain, &config.IntegralGain},
+		{c.DerivativeGain, &config.DerivativeGain},
+		{c.AntiWindUpGain, &config.AntiWindUpGain},
+	} {
+		if gain.value != nil {
+			*gain.result = *gain.value
+		}
+	}
+	return config
+}
+
+// finite returns nil for NaN and infinite values, which JSON cannot represent.
+func finite(x float64) *float64 {
+	if math.IsNaN(x) || math.IsInf(x, 0) {
+		return nil
+	}
+	return &x
+}
@@ -0,0 +1,72 @@
+package tuneserver
+
+import (
+	"fmt"
+	"math"
+)
+
+// Bounds are the safety bounds of the gains of a controller.
+//
+// Gain changes outside of the bounds are rejected. The zero value of a Range only allows a zero gain, so a gain
+// without configured bounds cannot be tuned.
+type Bounds struct {
+	// ProportionalGain is the range of the P part gain.
+	ProportionalGain Range `json:"proportionalGain"`
+	// IntegralGain is the range of the I part gain.
+	IntegralGain Range `json:"integralGain"`
+	// DerivativeGain is the range of the D part gain.
+	DerivativeGain Range `json:"derivativeGain"`
+	// AntiWindUpGain is the range of the anti-windup tracking gain.
+	AntiWindUpGain Range `json:"antiWindUpGain"`
+}
+
+// Range is a closed range of allowed values.
+type Range struct {
+	// Min is the smallest allowed value.
+	Min float64 `json:"min"`
+	// Max is the largest allowed value.
+	Max float64 `json:"max"`
+}
+
+// Contains returns true when x is within the range.
+func (r Range) Contains(x float64) bool {
+	return r.Min <= x && x <= r.Max
+}
+
+// Validate returns an error when a range of the bounds is not finite or empty.
+func (b Bounds) Validate() error {
+	for _, r := range b.ranges() {
+		if math.IsNaN(r.Min) || math.IsInf(r.Min, 0) || math.IsNaN(r.Max) || math.IsInf(r.Max, 0) {
+			return fmt.Errorf("tuneserver: invalid bounds: %s range [%v, %v] not finite", r.name, r.Min, r.Max)
+		}
+		if r.Min > r.Max {
+			return fmt.Errorf("tuneserver: invalid bounds: %s range [%v, %v] empty", r.name, r.Min, r.Max)
+		}
+	}
+	return nil
+}
+
+// Check returns an error when a gain is outside of the bounds.
+func (b Bounds) Check(g Gains) error {
+	values := [...]float64{g.ProportionalGain, g.IntegralGain, g.DerivativeGain, g.AntiWindUpGain}
+	for i, r := range b.ranges() {
+		if !r.Contains(values[i]) {
+			return fmt.Errorf("tuneserver: %s %v outside of bounds [%v, %v]", r.name, values[i], r.Min, r.Max)
+		}
+	}
+	return nil
+}
+
+type namedRange struct {
+	Range
+	name string
+}
+
+func (b Bounds) ranges() [4]namedRange {
+	return [...]namedRange{
+		{b.ProportionalGain, "proportional gain"},
+		{b.IntegralGain, "integral gain"},
+		{b.DerivativeGain, "derivative gain"},
+		{b.AntiWindUpGain, "anti-windup gain"},
+	}
+}
@@ -0,0 +1,31 @@
+package tuneserver
+
+import (
+	"math"
+	"testing"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestBounds_Validate(t *testing.T) {
+	assert.NilError(t, Bounds{ProportionalGain: Range{Min: 0, Max: 1}}.Validate())
+	assert.ErrorContains(t, Bounds{IntegralGain: Range{Min: 1, Max: 0}}.Validate(), "integral gain range [1, 0] empty")
+	assert.ErrorContains(
+		t, Bounds{DerivativeGain: Range{Max: math.Inf(1)}}.Validate(), "derivative gain range [0, +Inf] not finite",
+	)
+}
+
+func TestBounds_Check(t *testing.T) {
+	bounds := Bounds{
+		ProportionalGain: Range{Min: 1, Max: 2},
+		IntegralGain:     Range{Min: 0, Max: 1},
+	}
+	assert.NilError(t, bounds.Check(Gains{ProportionalGain: 1, IntegralGain: 1}))
+	assert.ErrorContains(
+		t, bounds.Check(Gains{ProportionalGain: 3}), "proportional gain 3 outside of bounds [1, 2]",
+	)
+	// A gain without configured bounds can only be zero.
+	assert.ErrorContains(
+		t, bounds.Check(Gains{ProportionalGain: 1, AntiWindUpGain: 0.1}), "anti-windup gain 0.1 outside of bounds",
+	)
+}
@@ -0,0 +1,16 @@
+// Package tuneserver provides an HTTP JSON API for live tuning of running controllers.
+//
+// Controllers are registered with safety bounds for their gains. The API exposes the config and state of each
+// controller, streams state samples, and applies gain changes within the bounds with bumpless transfer. Every
+// change request is recorded in an audit log, whether it is applied or rejected.
+//
+// The API has no authentication, and the server should only listen on a local or otherwise trusted interface.
+//
+// Endpoints:
+//
+//	GET /controllers                 list the registered controllers
+//	GET /controllers/{name}          get the config, state and bounds of a controller
+//	GET /controllers/{name}/stream   stream state samples as newline-delimited JSON
+//	PUT /controllers/{name}/gains    change the gains of a controller
+//	GET /audit                       get the audit log
+package tuneserver
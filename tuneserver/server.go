@@ -0,0 +1,353 @@
+package tuneserver
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.einride.tech/pid"
+)
+
+const (
+	defaultAuditLogSize   = 1000
+	defaultStreamInterval = 100 * time.Millisecond
+	minStreamInterval     = time.Millisecond
+	maxRequestBodySize    = 1 << 16
+)
+
+// Server serves the tuning API of the registered controllers. The zero value is ready to use.
+type Server struct {
+	// Clock is the time base of audit entries and streams. Defaults to pid.SystemClock when nil.
+	Clock pid.Clock
+	// AuditLogSize is the number of latest audit entries kept in memory. Defaults to 1000 when zero.
+	AuditLogSize int
+	// OnAudit is called with every audit entry when set, for example to persist the audit log.
+	OnAudit func(AuditEntry)
+
+	mu          sync.Mutex
+	controllers map[string]*controller
+	auditLog    []AuditEntry
+	muxOnce     sync.Once
+	mux         *http.ServeMux
+}
+
+var _ http.Handler = &Server{}
+
+// controller is a registered controller, with its config and state converted to those of an
+// AntiWindupController.
+type controller struct {
+	name   string
+	kind   string
+	bounds Bounds
+	// snapshot returns a consistent snapshot of the config and state.
+	snapshot func() (pid.AntiWindupControllerConfig, pid.AntiWindupControllerState)
+	// change calls f with exclusive access to the current config, and applies the config returned by f with
+	// bumpless transfer unless f returns an error.
+	change func(f func(pid.AntiWindupControllerConfig) (pid.AntiWindupControllerConfig, error)) error
+}
+
+// RegisterAntiWindupController registers an AntiWindupController for tuning.
+//
+// The controller is wrapped in a SafeController, which must be used for all further access to the controller.
+func (s *Server) RegisterAntiWindupController(
+	name string,
+	c *pid.AntiWindupController,
+	bounds Bounds,
+) (*pid.SafeController[pid.AntiWindupControllerConfig, pid.AntiWindupControllerState, pid.AntiWindupControllerInput],
+	error,
+) {
+	safe := pid.NewSafeAntiWindupController(c)
+	if err := s.register(&controller{
+		name:   name,
+		kind:   "antiWindup",
+		bounds: bounds,
+		snapshot: func() (config pid.AntiWindupControllerConfig, state pid.AntiWindupControllerState) {
+			safe.Do(func() {
+				config, state = c.Config, c.State
+			})
+			return config, state
+		},
+		change: func(f func(pid.AntiWindupControllerConfig) (pid.AntiWindupControllerConfig, error)) (err error) {
+			safe.Do(func() {
+				var config pid.AntiWindupControllerConfig
+				if config, err = f(c.Config); err == nil {
+					c.BumplessTransfer(config)
+				}
+			})
+			return err
+		},
+	}); err != nil {
+		return nil, err
+	}
+	return safe, nil
+}
+
+// RegisterTrackingController registers a TrackingController for tuning.
+//
+// The controller is wrapped in a SafeController, which must be used for all further access to the controller.
+func (s *Server) RegisterTrackingController(
+	name string,
+	c *pid.TrackingController,
+	bounds Bounds,
+) (*pid.SafeController[pid.TrackingControllerConfig, pid.TrackingControllerState, pid.TrackingControllerInput],
+	error,
+) {
+	safe := pid.NewSafeTrackingController(c)
+	if err := s.register(&controller{
+		name:   name,
+		kind:   "tracking",
+		bounds: bounds,
+		snapshot: func() (config pid.AntiWindupControllerConfig, state pid.AntiWindupControllerState) {
+			safe.Do(func() {
+				config, state = pid.AntiWindupControllerConfig(c.Config), pid.AntiWindupControllerState(c.State)
+			})
+			return config, state
+		},
+		change: func(f func(pid.AntiWindupControllerConfig) (pid.AntiWindupControllerConfig, error)) (err error) {
+			safe.Do(func() {
+				var config pid.AntiWindupControllerConfig
+				if config, err = f(pid.AntiWindupControllerConfig(c.Config)); err == nil {
+					c.BumplessTransfer(pid.TrackingControllerConfig(config))
+				}
+			})
+			return err
+		},
+	}); err != nil {
+		return nil, err
+	}
+	return safe, nil
+}
+
+func (s *Server) register(c *controller) error {
+	if c.name == "" || strings.Contains(c.name, "/") {
+		return fmt.Errorf("tuneserver: register %q: invalid name", c.name)
+	}
+	if err := c.bounds.Validate(); err != nil {
+		return fmt.Errorf("tuneserver: register %q: %w", c.name, err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.controllers[c.name]; ok {
+		return fmt.Errorf("tuneserver: register %q: already registered", c.name)
+	}
+	if s.controllers == nil {
+		s.controllers = map[string]*controller{}
+	}
+	s.controllers[c.name] = c
+	return nil
+}
+
+// AuditLog returns the latest entries of the audit log, oldest first.
+func (s *Server) AuditLog() []AuditEntry {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.auditLog)
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.muxOnce.Do(func() {
+		s.mux = http.NewServeMux()
+		s.mux.HandleFunc("GET /controllers", s.handleList)
+		s.mux.HandleFunc("GET /controllers/{name}", s.handleGet)
+		s.mux.HandleFunc("GET /controllers/{name}/stream", s.handleStream)
+		s.mux.HandleFunc("PUT /controllers/{name}/gains", s.handleChangeGains)
+		s.mux.HandleFunc("GET /audit", s.handleAuditLog)
+	})
+	s.mux.ServeHTTP(w, r)
+}
+
+func (s *Server) handleList(w http.ResponseWriter, _ *http.Request) {
+	s.mu.Lock()
+	controllers := make([]*controller, 0, len(s.controllers))
+	for _, c := range s.controllers {
+		controllers = append(controllers, c)
+	}
+	s.mu.Unlock()
+	slices.SortFunc(controllers, func(a, b *controller) int {
+		return strings.Compare(a.name, b.name)
+	})
+	result := make([]Controller, 0, len(controllers))
+	for _, c := range controllers {
+		result = append(result, c.describe())
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (s *Server) handleGet(w http.ResponseWriter, r *http.Request) {
+	c, ok := s.controller(w, r)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, c.describe())
+}
+
+func (s *Server) handleStream(w http.ResponseWriter, r *http.Request) {
+	c, ok := s.controller(w, r)
+	if !ok {
+		return
+	}
+	interval := defaultStreamInterval
+	if value := r.URL.Query().Get("interval"); value != "" {
+		parsed, err := time.ParseDuration(value)
+		if err != nil || parsed < minStreamInterval {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid interval %q", value))
+			return
+		}
+		interval = parsed
+	}
+	var samples int
+	if value := r.URL.Query().Get("samples"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed <= 0 {
+			writeError(w, http.StatusBadRequest, fmt.Errorf("invalid samples %q", value))
+			return
+		}
+		samples = parsed
+	}
+	clock := s.clock()
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.WriteHeader(http.StatusOK)
+	encoder := json.NewEncoder(w)
+	responseController := http.NewResponseController(w)
+	for i := 0; samples == 0 || i < samples; i++ {
+		if i > 0 {
+			select {
+			case <-r.Context().Done():
+				return
+			case <-clock.After(interval):
+			}
+		}
+		_, state := c.snapshot()
+		if err := encoder.Encode(Sample{Time: clock.Now(), State: stateOf(state)}); err != nil {
+			return
+		}
+		if err := responseController.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+func (s *Server) handleChangeGains(w http.ResponseWriter, r *http.Request) {
+	c, ok := s.controller(w, r)
+	if !ok {
+		return
+	}
+	var change GainChange
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBodySize))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&change); err != nil {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid gain change: %w", err))
+		return
+	}
+	if change.User == "" {
+		writeError(w, http.StatusBadRequest, fmt.Errorf("invalid gain change: user required"))
+		return
+	}
+	entry := AuditEntry{
+		Time:       s.clock().Now(),
+		User:       change.User,
+		Reason:     change.Reason,
+		Controller: c.name,
+	}
+	err := c.change(func(config pid.AntiWindupControllerConfig) (pid.AntiWindupControllerConfig, error) {
+		next := change.apply(config)
+		entry.Old, entry.New = gainsOf(config), gainsOf(next)
+		if err := c.bounds.Check(entry.New); err != nil {
+			return config, err
+		}
+		if err := next.Validate(); err != nil {
+			return config, err
+		}
+		return next, nil
+	})
+	entry.Applied = err == nil
+	if err != nil {
+		entry.Error = err.Error()
+	}
+	s.audit(entry)
+	if err != nil {
+		writeError(w, http.StatusUnprocessableEntity, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, c.describe())
+}
+
+func (s *Server) handleAuditLog(w http.ResponseWriter, r *http.Request) {
+	result := s.AuditLog()
+	if name := r.URL.Query().Get("controller"); name != "" {
+		result = slices.DeleteFunc(result, func(entry AuditEntry) bool {
+			return entry.Controller != name
+		})
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+// controller returns the controller named in the request path, or writes a not found error.
+func (s *Server) controller(w http.ResponseWriter, r *http.Request) (*controller, bool) {
+	name := r.PathValue("name")
+	s.mu.Lock()
+	c, ok := s.controllers[name]
+	s.mu.Unlock()
+	if !ok {
+		writeError(w, http.StatusNotFound, fmt.Errorf("controller %q not found", name))
+	}
+	return c, ok
+}
+
+func (s *Server) audit(entry AuditEntry) {
+	size := s.AuditLogSize
+	if size <= 0 {
+		size = defaultAuditLogSize
+	}
+	s.mu.Lock()
+	s.auditLog = append(s.auditLog, entry)
+	if len(s.auditLog) > size {
+		s.auditLog = slices.Delete(s.auditLog, 0, len(s.auditLog)-size)
+	}
+	s.mu.Unlock()
+	if s.OnAudit != nil {
+		s.OnAudit(entry)
+	}
+}
+
+func (s *Server) clock() pid.Clock {
+	if s.Clock == nil {
+		return pid.SystemClock{}
+	}
+	return s.Clock
+}
+
+func (c *controller) describe() Controller {
+	config, state := c.snapshot()
+	return Controller{
+		Name:   c.name,
+		Type:   c.kind,
+		Config: configOf(config),
+		State:  stateOf(state),
+		Bounds: c.bounds,
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = w.Write(append(data, '\n'))
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	data, _ := json.Marshal(errorResponse{Error: err.Error()})
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_, _ = w.Write(append(data, '\n'))
+}
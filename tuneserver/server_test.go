@@ -0,0 +1,260 @@
+package tuneserver
+
+import (
+	"bufio"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go.einride.tech/pid"
+	"gotest.tools/v3/assert"
+)
+
+func newTestServer(t *testing.T) (*Server, *pid.AntiWindupController, *httptest.Server) {
+	t.Helper()
+	s := &Server{Clock: pid.NewFakeClock(time.Unix(1000, 0).UTC())}
+	c := &pid.AntiWindupController{
+		Config: pid.AntiWindupControllerConfig{
+			ProportionalGain:    1,
+			IntegralGain:        1,
+			LowPassTimeConstant: time.Second,
+			MinOutput:           -10,
+			MaxOutput:           10,
+		},
+		State: pid.AntiWindupControllerState{ControlError: 1, ControlErrorIntegral: 2},
+	}
+	_, err := s.RegisterAntiWindupController("speed", c, Bounds{
+		ProportionalGain: Range{Min: 0, Max: 5},
+		IntegralGain:     Range{Min: 0.5, Max: 5},
+	})
+	assert.NilError(t, err)
+	server := httptest.NewServer(s)
+	t.Cleanup(server.Close)
+	return s, c, server
+}
+
+func do(t *testing.T, method, url, body string, result any) int {
+	t.Helper()
+	request, err := http.NewRequest(method, url, strings.NewReader(body))
+	assert.NilError(t, err)
+	response, err := http.DefaultClient.Do(request)
+	assert.NilError(t, err)
+	defer response.Body.Close()
+	assert.Equal(t, "application/json", response.Header.Get("Content-Type"))
+	assert.NilError(t, json.NewDecoder(response.Body).Decode(result))
+	return response.StatusCode
+}
+
+func TestServer_Get(t *testing.T) {
+	// Given a server with a registered controller
+	_, _, server := newTestServer(t)
+	// When getting the controller
+	var result Controller
+	status := do(t, http.MethodGet, server.URL+"/controllers/speed", "", &result)
+	// Then the config, state and bounds should be returned
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, "speed", result.Name)
+	assert.Equal(t, "antiWindup", result.Type)
+	assert.Equal(t, Gains{ProportionalGain: 1, IntegralGain: 1}, result.Config.Gains)
+	assert.Equal(t, time.Second, result.Config.LowPassTimeConstant)
+	assert.Equal(t, 10.0, *result.Config.MaxOutput)
+	assert.Equal(t, 2.0, result.State.ControlErrorIntegral)
+	assert.Equal(t, Range{Min: 0.5, Max: 5}, result.Bounds.IntegralGain)
+	// And listing should include the controller
+	var list []Controller
+	assert.Equal(t, http.StatusOK, do(t, http.MethodGet, server.URL+"/controllers", "", &list))
+	assert.DeepEqual(t, []Controller{result}, list)
+	// And unknown controllers should not be found
+	var errorResult errorResponse
+	assert.Equal(t, http.StatusNotFound, do(t, http.MethodGet, server.URL+"/controllers/foo", "", &errorResult))
+	assert.Equal(t, `controller "foo" not found`, errorResult.Error)
+}
+
+func TestServer_Get_NonFiniteOutputLimits(t *testing.T) {
+	// Given a server with a registered controller with a NaN and an infinite output limit
+	_, c, server := newTestServer(t)
+	c.Config.MaxOutput = math.NaN()
+	c.Config.MinOutput = math.Inf(-1)
+	// When getting the controller
+	var result Controller
+	status := do(t, http.MethodGet, server.URL+"/controllers/speed", "", &result)
+	// Then the output limits should be null
+	assert.Equal(t, http.StatusOK, status)
+	assert.Assert(t, result.Config.MaxOutput == nil)
+	assert.Assert(t, result.Config.MinOutput == nil)
+}
+func TestServer_ChangeGains(t *testing.T) {
+	// Given a server with a registered controller
+	s, c, server := newTestServer(t)
+	var audited []AuditEntry
+	s.OnAudit = func(entry AuditEntry) {
+		audited = append(audited, entry)
+	}
+	// When changing the gains within the bounds
+	var result Controller
+	status := do(
+		t,
+		http.MethodPut,
+		server.URL+"/controllers/speed/gains",
+		`{"user": "alice", "reason": "sluggish", "proportionalGain": 2, "integralGain": 2}`,
+		&result,
+	)
+	// Then the change should be applied
+	assert.Equal(t, http.StatusOK, status)
+	assert.Equal(t, Gains{ProportionalGain: 2, IntegralGain: 2}, result.Config.Gains)
+	assert.Equal(t, 2.0, c.Config.ProportionalGain)
+	// And with bumpless transfer
+	assert.Equal(t, 0.5, c.State.ControlErrorIntegral)
+	// And audited
+	expected := AuditEntry{
+		Time:       time.Unix(1000, 0).UTC(),
+		User:       "alice",
+		Reason:     "sluggish",
+		Controller: "speed",
+		Old:        Gains{ProportionalGain: 1, IntegralGain: 1},
+		New:        Gains{ProportionalGain: 2, IntegralGain: 2},
+		Applied:    true,
+	}
+	assert.DeepEqual(t, []AuditEntry{expected}, audited)
+	var auditLog []AuditEntry
+	assert.Equal(t, http.StatusOK, do(t, http.MethodGet, server.URL+"/audit?controller=speed", "", &auditLog))
+	assert.DeepEqual(t, []AuditEntry{expected}, auditLog)
+}
+
+func TestServer_ChangeGains_Rejected(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		body     string
+		status   int
+		expected string
+		audited  bool
+	}{
+		{
+			name:     "outside bounds",
+			body:     `{"user": "alice", "proportionalGain": 6}`,
+			status:   http.StatusUnprocessableEntity,
+			expected: "tuneserver: proportional gain 6 outside of bounds [0, 5]",
+			audited:  true,
+		},
+		{
+			name:     "gain without bounds",
+			body:     `{"user": "alice", "derivativeGain": 0.1}`,
+			status:   http.StatusUnprocessableEntity,
+			expected: "tuneserver: derivative gain 0.1 outside of bounds [0, 0]",
+			audited:  true,
+		},
+		{
+			name:     "missing user",
+			body:     `{"proportionalGain": 2}`,
+			status:   http.StatusBadRequest,
+			expected: "invalid gain change: user required",
+		},
+		{
+			name:     "unknown field",
+			body:     `{"user": "alice", "minOutput": -100}`,
+			status:   http.StatusBadRequest,
+			expected: `invalid gain change: json: unknown field "minOutput"`,
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			// Given a server with a registered controller
+			s, c, server := newTestServer(t)
+			initial := *c
+			// When requesting an invalid change
+			var result errorResponse
+			status := do(t, http.MethodPut, server.URL+"/controllers/speed/gains", tt.body, &result)
+			// Then the change should be rejected
+			assert.Equal(t, tt.status, status)
+			assert.Equal(t, tt.expected, result.Error)
+			assert.Equal(t, initial, *c)
+			// And audited when well-formed
+			auditLog := s.AuditLog()
+			if !tt.audited {
+				assert.Equal(t, 0, len(auditLog))
+				return
+			}
+			assert.Equal(t, 1, len(auditLog))
+			assert.Equal(t, false, auditLog[0].Applied)
+			assert.Equal(t, tt.expected, auditLog[0].Error)
+		})
+	}
+}
+
+func TestServer_ChangeGains_ValidateFails(t *testing.T) {
+	// Given a tracking controller with an invalid low-pass time constant
+	s := &Server{}
+	c := &pid.TrackingController{Config: pid.TrackingControllerConfig{ProportionalGain: 1}}
+	_, err := s.RegisterTrackingController("position", c, Bounds{ProportionalGain: Range{Max: 10}})
+	assert.NilError(t, err)
+	server := httptest.NewServer(s)
+	defer server.Close()
+	// When changing a gain within the bounds
+	var result errorResponse
+	status := do(t, http.MethodPut, server.URL+"/controllers/position/gains", `{"user": "bob", "proportionalGain": 2}`,
+		&result)
+	// Then the change should be rejected by validation
+	assert.Equal(t, http.StatusUnprocessableEntity, status)
+	assert.Equal(t, "pid: invalid config: non-positive low-pass time constant 0s", result.Error)
+	assert.Equal(t, 1.0, c.Config.ProportionalGain)
+}
+
+func TestServer_Stream(t *testing.T) {
+	// Given a server with a registered controller
+	s, _, server := newTestServer(t)
+	s.Clock = nil
+	// When streaming three samples
+	response, err := http.Get(server.URL + "/controllers/speed/stream?interval=1ms&samples=3")
+	assert.NilError(t, err)
+	defer response.Body.Close()
+	// Then three samples of newline-delimited JSON should be streamed
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, "application/x-ndjson", response.Header.Get("Content-Type"))
+	scanner := bufio.NewScanner(response.Body)
+	var n int
+	for scanner.Scan() {
+		var sample Sample
+		assert.NilError(t, json.Unmarshal(scanner.Bytes(), &sample))
+		assert.Equal(t, 2.0, sample.State.ControlErrorIntegral)
+		n++
+	}
+	assert.NilError(t, scanner.Err())
+	assert.Equal(t, 3, n)
+	// And invalid intervals should be rejected
+	var result errorResponse
+	assert.Equal(
+		t, http.StatusBadRequest, do(t, http.MethodGet, server.URL+"/controllers/speed/stream?interval=0s", "", &result),
+	)
+}
+
+func TestServer_Register(t *testing.T) {
+	var s Server
+	_, err := s.RegisterAntiWindupController("a", &pid.AntiWindupController{}, Bounds{})
+	assert.NilError(t, err)
+	_, err = s.RegisterAntiWindupController("a", &pid.AntiWindupController{}, Bounds{})
+	assert.ErrorContains(t, err, `tuneserver: register "a": already registered`)
+	_, err = s.RegisterTrackingController("a/b", &pid.TrackingController{}, Bounds{})
+	assert.ErrorContains(t, err, "invalid name")
+	_, err = s.RegisterTrackingController("b", &pid.TrackingController{}, Bounds{ProportionalGain: Range{Min: 1}})
+	assert.ErrorContains(t, err, "proportional gain range [1, 0] empty")
+}
+
+func TestServer_AuditLogSize(t *testing.T) {
+	// Given a server keeping two audit entries
+	s, _, server := newTestServer(t)
+	s.AuditLogSize = 2
+	// When making three changes
+	for _, user := range []string{"a", "b", "c"} {
+		var result Controller
+		assert.Equal(t, http.StatusOK, do(t, http.MethodPut, server.URL+"/controllers/speed/gains",
+			`{"user": "`+user+`"}`, &result))
+	}
+	// Then the two latest should be kept
+	auditLog := s.AuditLog()
+	assert.Equal(t, 2, len(auditLog))
+	assert.Equal(t, "b", auditLog[0].User)
+	assert.Equal(t, "c", auditLog[1].User)
+}
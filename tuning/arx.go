@@ -0,0 +1,360 @@
+package tuning
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"time"
+
+	"go.einride.tech/pid/model"
+)
+
+// DefaultInitialCovariance is the default initial covariance of the parameter estimates of an ARXEstimator.
+const DefaultInitialCovariance = 1000
+
+// maxStepResponseSamples is the max number of samples of the step response of an ARX model used to approximate
+// it with a FOPDT model.
+const maxStepResponseSamples = 100_000
+
+// ARX is a discrete-time autoregressive process model with exogenous input,
+//
+//	y[k] + a1 y[k-1] + ... + an y[k-n] = b1 u[k-d-1] + ... + bm u[k-d-m].
+type ARX struct {
+	// A are the coefficients a1, ..., an of the output.
+	A []float64
+	// B are the coefficients b1, ..., bm of the input.
+	B []float64
+	// Delay is the input delay d in samples.
+	Delay int
+	// SamplingInterval is the sampling interval of the model.
+	SamplingInterval time.Duration
+}
+
+// StaticGain returns the static gain of the model.
+func (m ARX) StaticGain() float64 {
+	a, b := 1.0, 0.0
+	for _, ai := range m.A {
+		a += ai
+	}
+	for _, bi := range m.B {
+		b += bi
+	}
+	return b / a
+}
+
+// StepResponse returns the first n samples of the response of the model to a unit step of the input at sample 0.
+func (m ARX) StepResponse(n int) []float64 {
+	y := make([]float64, n)
+	for k := range y {
+		y[k] = m.nextStepResponse(y[:k], k)
+	}
+	return y
+}
+
+// nextStepResponse returns sample k of the step response, given the previous samples.
+func (m ARX) nextStepResponse(previous []float64, k int) float64 {
+	var y float64
+	for i, ai := range m.A {
+		if j := k - i - 1; j >= 0 {
+			y -= ai * previous[j]
+		}
+	}
+	for i, bi := range m.B {
+		if k-m.Delay-i-1 >= 0 {
+			y += bi
+		}
+	}
+	return y
+}
+
+// FOPDT approximates the model with a FOPDT model.
+//
+// A first-order model with a pole p in (0, 1) is converted exactly, with the time constant -h/ln(p) for the
+// sampling interval h. Higher-order models are approximated with the two-point method of Smith from the times t28
+// and t63 when the step response reaches 28.3% and 63.2% of the static gain, with the time constant
+// 1.5 (t63 - t28) and the dead time t63 minus the time constant. The dead time includes half a sampling interval,
+// which approximates the delay of the sampling.
+func (m ARX) FOPDT() (model.FOPDT, error) {
+	h := m.SamplingInterval
+	if h <= 0 {
+		return model.FOPDT{}, fmt.Errorf("tuning: approximate FOPDT: non-positive sampling interval %v", h)
+	}
+	k := m.StaticGain()
+	if k == 0 || math.IsNaN(k) || math.IsInf(k, 0) {
+		return model.FOPDT{}, fmt.Errorf("tuning: approximate FOPDT: invalid static gain %v", k)
+	}
+	samplingDelay := time.Duration(m.Delay)*h + h/2
+	if len(m.A) == 1 && len(m.B) == 1 {
+		p := -m.A[0]
+		if p <= 0 || p >= 1 {
+			return model.FOPDT{}, fmt.Errorf("tuning: approximate FOPDT: pole %v outside of (0, 1)", p)
+		}
+		return model.FOPDT{
+			Gain:         k,
+			TimeConstant: time.Duration(-h.Seconds() / math.Log(p) * float64(time.Second)),
+			DeadTime:     samplingDelay,
+		}, nil
+	}
+	// The response is settled when it stays close to the static gain for as many samples as the model order.
+	settlingSamples := len(m.A) + len(m.B) + m.Delay + 1
+	var t28, t63 float64
+	var settled int
+	y := make([]float64, 0, 1024)
+	for i := range maxStepResponseSamples {
+		y = append(y, m.nextStepResponse(y, i))
+		normalized := y[i] / k
+		if i > 0 {
+			previous := y[i-1] / k
+			if t28 == 0 && normalized >= 0.283 {
+				t28 = float64(i-1) + (0.283-previous)/(normalized-previous)
+			}
+			if t63 == 0 && normalized >= 0.632 {
+				t63 = float64(i-1) + (0.632-previous)/(normalized-previous)
+			}
+		}
+		if math.Abs(normalized-1) < 1e-3 {
+			settled++
+		} else {
+			settled = 0
+		}
+		if settled >= settlingSamples && t63 > 0 {
+			timeConstant := 1.5 * (t63 - t28) * h.Seconds()
+			deadTime := math.Max(0, t63*h.Seconds()-timeConstant)
+			return model.FOPDT{
+				Gain:         k,
+				TimeConstant: time.Duration(timeConstant * float64(time.Second)),
+				DeadTime:     time.Duration(deadTime*float64(time.Second)) + h/2,
+			}, nil
+		}
+	}
+	return model.FOPDT{}, fmt.Errorf("tuning: approximate FOPDT: step response does not settle")
+}
+
+// ARXEstimator implements an online recursive least-squares estimator of an ARX process model, with a forgetting
+// factor that discounts old samples so that the estimate can track a slowly varying process.
+//
+// The model is estimated from the increments of the samples, which removes constant offsets such as the
+// operating point of the process. Samples with zero increments over the whole regressor carry no information, and
+// do not update the estimate, so that the covariance does not grow without bound in steady state. The sampling
+// interval is assumed to be constant.
+type ARXEstimator struct {
+	// Config for the ARXEstimator.
+	Config ARXEstimatorConfig
+	// State of the ARXEstimator.
+	State ARXEstimatorState
+
+	// regressor and gain are scratch space for the updates, reused across updates.
+	regressor, gain []float64
+}
+
+// ARXEstimatorConfig contains config parameters for an ARXEstimator.
+type ARXEstimatorConfig struct {
+	// OutputOrder is the number n of output coefficients.
+	OutputOrder int
+	// InputOrder is the number m of input coefficients.
+	InputOrder int
+	// InputDelay is the input delay d in samples.
+	InputDelay int
+	// ForgettingFactor is the weight in (0, 1] of the previous samples for each new sample, typically 0.95 to
+	// 0.999. The estimate has an effective memory of 1 / (1 - ForgettingFactor) samples.
+	ForgettingFactor float64
+	// InitialCovariance is the initial covariance of the parameter estimates. Defaults to
+	// DefaultInitialCovariance when zero.
+	InitialCovariance float64
+}
+
+// ARXEstimatorState holds mutable state for an ARXEstimator.
+type ARXEstimatorState struct {
+	// Parameters are the estimated coefficients a1, ..., an, b1, ..., bm.
+	Parameters []float64
+	// Covariance is the covariance of the parameter estimates, in row-major order.
+	Covariance []float64
+	// PredictionError is the prediction error of the output increment of the latest update of the parameters.
+	PredictionError float64
+	// Updates is the number of updates of the parameters.
+	Updates int
+	// SamplingInterval is the sampling interval of the latest sample.
+	SamplingInterval time.Duration
+	// Samples is the number of valid samples.
+	Samples int
+	// PreviousInput is the process input of the latest valid sample.
+	PreviousInput float64
+	// PreviousOutput is the process output of the latest valid sample.
+	PreviousOutput float64
+	// InputIncrements are the most recent increments of the process input, with the latest first.
+	InputIncrements []float64
+	// OutputIncrements are the most recent increments of the process output, with the latest first.
+	OutputIncrements []float64
+}
+
+// Clone returns a copy of the state that does not share memory with the state.
+//
+// The parameters, covariance and increments are updated in place, so a copy of the state by value changes with
+// updates of the estimator, and a state to restore later should be saved with Clone.
+func (s ARXEstimatorState) Clone() ARXEstimatorState {
+	s.Parameters = slices.Clone(s.Parameters)
+	s.Covariance = slices.Clone(s.Covariance)
+	s.InputIncrements = slices.Clone(s.InputIncrements)
+	s.OutputIncrements = slices.Clone(s.OutputIncrements)
+	return s
+}
+
+// ARXEstimatorInput holds the input parameters to an ARXEstimator.
+type ARXEstimatorInput struct {
+	// ProcessInput is the input of the process, typically the applied control signal.
+	ProcessInput float64
+	// ProcessOutput is the output of the process, typically the actual signal.
+	ProcessOutput float64
+	// SamplingInterval is the time interval elapsed since the previous call of the estimator Update method.
+	SamplingInterval time.Duration
+}
+
+// Validate returns an error when an order or the input delay is negative, when the input order is zero, when the
+// forgetting factor is outside of (0, 1], or when the initial covariance is negative.
+func (c ARXEstimatorConfig) Validate() error {
+	switch {
+	case c.OutputOrder < 0:
+		return fmt.Errorf("tuning: invalid ARX estimator config: negative output order %d", c.OutputOrder)
+	case c.InputOrder <= 0:
+		return fmt.Errorf("tuning: invalid ARX estimator config: non-positive input order %d", c.InputOrder)
+	case c.InputDelay < 0:
+		return fmt.Errorf("tuning: invalid ARX estimator config: negative input delay %d", c.InputDelay)
+	case !(c.ForgettingFactor > 0 && c.ForgettingFactor <= 1):
+		return fmt.Errorf("tuning: invalid ARX estimator config: forgetting factor %v outside of (0, 1]",
+			c.ForgettingFactor)
+	case !(c.InitialCovariance >= 0) || math.IsInf(c.InitialCovariance, 0):
+		return fmt.Errorf("tuning: invalid ARX estimator config: invalid initial covariance %v",
+			c.InitialCovariance)
+	}
+	return nil
+}
+
+// Reset the estimator state.
+func (e *ARXEstimator) Reset() {
+	e.State = ARXEstimatorState{}
+}
+
+// Update the estimate with a sample of the process input and output.
+//
+// The output of each sample is predicted from the inputs of the previous samples, so the input of a sample
+// should be the input applied after the output was sampled. Changing the orders or the input delay of the config
+// resets the estimate on the next update.
+func (e *ARXEstimator) Update(input ARXEstimatorInput) {
+	if isNaNOrInf(input.ProcessInput) || isNaNOrInf(input.ProcessOutput) {
+		return
+	}
+	na, nb, d := e.Config.OutputOrder, e.Config.InputOrder, e.Config.InputDelay
+	n := na + nb
+	if len(e.State.Parameters) != n || len(e.State.Covariance) != n*n ||
+		len(e.State.InputIncrements) != d+nb || len(e.State.OutputIncrements) != na {
+		e.initialize()
+	}
+	if len(e.regressor) != n {
+		e.regressor, e.gain = make([]float64, n), make([]float64, n)
+	}
+	e.State.SamplingInterval = input.SamplingInterval
+	e.State.Samples++
+	defer func() {
+		e.State.PreviousInput, e.State.PreviousOutput = input.ProcessInput, input.ProcessOutput
+	}()
+	if e.State.Samples == 1 {
+		return
+	}
+	du, dy := input.ProcessInput-e.State.PreviousInput, input.ProcessOutput-e.State.PreviousOutput
+	phi := e.regressor
+	for i := range na {
+		phi[i] = -e.State.OutputIncrements[i]
+	}
+	for i := range nb {
+		phi[na+i] = e.State.InputIncrements[d+i]
+	}
+	// The regressor is complete when the increments before the current one fill the history.
+	if e.State.Samples > max(na, d+nb)+1 && !isZero(phi) {
+		e.updateParameters(phi, dy)
+	}
+	shift(e.State.OutputIncrements, dy)
+	shift(e.State.InputIncrements, du)
+}
+
+// updateParameters updates the parameters and covariance with the regressor and output increment.
+//
+//	K = P φ / (λ + φ' P φ), θ = θ + K (y - φ' θ), P = (P - K φ' P) / λ.
+func (e *ARXEstimator) updateParameters(phi []float64, y float64) {
+	n := len(phi)
+	p, theta, k := e.State.Covariance, e.State.Parameters, e.gain
+	lambda := e.Config.ForgettingFactor
+	denominator := lambda
+	for i := range n {
+		k[i] = 0
+		for j := range n {
+			k[i] += p[i*n+j] * phi[j]
+		}
+		denominator += phi[i] * k[i]
+	}
+	prediction := 0.0
+	for i := range n {
+		prediction += phi[i] * theta[i]
+	}
+	e.State.PredictionError = y - prediction
+	// P is symmetric, so φ' P = (P φ)'.
+	for i := range n {
+		for j := range n {
+			p[i*n+j] = (p[i*n+j] - k[i]*k[j]/denominator) / lambda
+		}
+	}
+	for i := range n {
+		theta[i] += k[i] / denominator * e.State.PredictionError
+	}
+	e.State.Updates++
+}
+
+func (e *ARXEstimator) initialize() {
+	na, nb, d := e.Config.OutputOrder, e.Config.InputOrder, e.Config.InputDelay
+	n := na + nb
+	p0 := e.Config.InitialCovariance
+	if p0 == 0 {
+		p0 = DefaultInitialCovariance
+	}
+	e.State = ARXEstimatorState{
+		Parameters:       make([]float64, n),
+		Covariance:       make([]float64, n*n),
+		InputIncrements:  make([]float64, d+nb),
+		OutputIncrements: make([]float64, na),
+	}
+	for i := range n {
+		e.State.Covariance[i*n+i] = p0
+	}
+}
+
+// Model returns the estimated model.
+func (e *ARXEstimator) Model() ARX {
+	na := min(e.Config.OutputOrder, len(e.State.Parameters))
+	return ARX{
+		A:                append([]float64(nil), e.State.Parameters[:na]...),
+		B:                append([]float64(nil), e.State.Parameters[na:]...),
+		Delay:            e.Config.InputDelay,
+		SamplingInterval: e.State.SamplingInterval,
+	}
+}
+
+// shift the values one step and insert x first.
+func shift(values []float64, x float64) {
+	if len(values) == 0 {
+		return
+	}
+	copy(values[1:], values[:len(values)-1])
+	values[0] = x
+}
+
+func isZero(x []float64) bool {
+	for _, xi := range x {
+		if xi != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func isNaNOrInf(x float64) bool {
+	return math.IsNaN(x) || math.IsInf(x, 0)
+}
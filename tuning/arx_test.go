@@ -0,0 +1,167 @@
+package tuning
+
+import (
+	"math"
+	"math/rand/v2"
+	"testing"
+	"time"
+
+	"go.einride.tech/pid/model"
+	"gotest.tools/v3/assert"
+)
+
+func TestARX_StepResponse(t *testing.T) {
+	m := ARX{A: []float64{-0.5}, B: []float64{1}, Delay: 1, SamplingInterval: time.Second}
+	assert.DeepEqual(t, []float64{0, 0, 1, 1.5, 1.75}, m.StepResponse(5))
+	assert.Equal(t, 2.0, m.StaticGain())
+}
+
+func TestARX_FOPDT(t *testing.T) {
+	t.Run("first order", func(t *testing.T) {
+		// Given an exact discretization of a FOPDT model
+		p := math.Exp(-0.1 / 5)
+		m := ARX{A: []float64{-p}, B: []float64{2 * (1 - p)}, Delay: 10, SamplingInterval: 100 * time.Millisecond}
+		// When approximating the model with a FOPDT model
+		fopdt, err := m.FOPDT()
+		// Then the FOPDT model should be recovered, with half a sampling interval of extra dead time
+		assert.NilError(t, err)
+		assert.Assert(t, math.Abs(fopdt.Gain-2) < 1e-12, fopdt)
+		assert.Assert(t, (fopdt.TimeConstant-5*time.Second).Abs() < time.Microsecond, fopdt)
+		assert.Equal(t, 1050*time.Millisecond, fopdt.DeadTime)
+	})
+	t.Run("second order", func(t *testing.T) {
+		// Given a second-order model with a dominant time constant of 5 s and a minor time constant of 0.5 s
+		p1, p2 := math.Exp(-0.1/5), math.Exp(-0.1/0.5)
+		m := ARX{
+			A:                []float64{-(p1 + p2), p1 * p2},
+			B:                []float64{(1 - p1) * (1 - p2)},
+			SamplingInterval: 100 * time.Millisecond,
+		}
+		// When approximating the model with a FOPDT model
+		fopdt, err := m.FOPDT()
+		// Then the minor time constant should be approximated by dead time
+		assert.NilError(t, err)
+		assert.Assert(t, math.Abs(fopdt.Gain-1) < 1e-12, fopdt)
+		assert.Assert(t, (fopdt.TimeConstant-5*time.Second).Abs() < 500*time.Millisecond, fopdt)
+		assert.Assert(t, (fopdt.DeadTime-500*time.Millisecond).Abs() < 300*time.Millisecond, fopdt)
+	})
+}
+
+func TestARX_FOPDT_Errors(t *testing.T) {
+	_, err := ARX{A: []float64{-0.5}, B: []float64{1}}.FOPDT()
+	assert.ErrorContains(t, err, "non-positive sampling interval")
+	_, err = ARX{A: []float64{-0.5}, B: []float64{0}, SamplingInterval: time.Second}.FOPDT()
+	assert.ErrorContains(t, err, "invalid static gain 0")
+	_, err = ARX{A: []float64{0.5}, B: []float64{1}, SamplingInterval: time.Second}.FOPDT()
+	assert.ErrorContains(t, err, "pole -0.5 outside of (0, 1)")
+	_, err = ARX{A: []float64{-2.5, 1.5}, B: []float64{1}, SamplingInterval: time.Second}.FOPDT()
+	assert.ErrorContains(t, err, "invalid static gain")
+	_, err = ARX{A: []float64{-2.5, 1.2}, B: []float64{1}, SamplingInterval: time.Second}.FOPDT()
+	assert.ErrorContains(t, err, "step response does not settle")
+}
+
+func TestARXEstimator_Update(t *testing.T) {
+	// Given a FOPDT process around an operating point, excited by random input steps
+	process, err := model.NewSimulation(model.FOPDT{Gain: 2, TimeConstant: 5 * time.Second, DeadTime: time.Second})
+	assert.NilError(t, err)
+	e := &ARXEstimator{
+		Config: ARXEstimatorConfig{OutputOrder: 1, InputOrder: 1, InputDelay: 10, ForgettingFactor: 0.99},
+	}
+	assert.NilError(t, e.Config.Validate())
+	random := rand.New(rand.NewPCG(1, 2))
+	const dt = 100 * time.Millisecond
+	u := 0.0
+	for k := range 2000 {
+		if k%50 == 0 {
+			u = random.Float64()
+		}
+		// When updating the estimator with the input applied after sampling the output
+		e.Update(ARXEstimatorInput{ProcessInput: 10 + u, ProcessOutput: 20 + process.Output(), SamplingInterval: dt})
+		process.Update(u, dt)
+	}
+	// Then the estimated model should be close to the process
+	fopdt, err := e.Model().FOPDT()
+	assert.NilError(t, err)
+	assert.Assert(t, math.Abs(fopdt.Gain-2) < 0.01, fopdt)
+	assert.Assert(t, (fopdt.TimeConstant-5*time.Second).Abs() < 50*time.Millisecond, fopdt)
+	assert.Equal(t, 1050*time.Millisecond, fopdt.DeadTime)
+	assert.Assert(t, math.Abs(e.State.PredictionError) < 1e-3)
+	// When updating with constant samples in steady state
+	steadyState := ARXEstimatorInput{ProcessInput: 10 + u, ProcessOutput: 20 + 2*u, SamplingInterval: dt}
+	for range 20 {
+		e.Update(steadyState)
+	}
+	updates := e.State.Updates
+	for range 100 {
+		e.Update(steadyState)
+	}
+	// Then the estimate should not be updated
+	assert.Equal(t, updates, e.State.Updates)
+	// When updating with an invalid sample
+	state := e.State.Clone()
+	e.Update(ARXEstimatorInput{ProcessInput: math.NaN(), ProcessOutput: 0, SamplingInterval: dt})
+	// Then the state should be unchanged
+	assert.DeepEqual(t, state, e.State)
+	// When resetting the estimator
+	e.Reset()
+	// Then the model should be empty
+	assert.DeepEqual(t, ARX{Delay: 10}, e.Model())
+}
+
+func TestARXEstimator_RestoreState(t *testing.T) {
+	// Given an estimator with a saved state
+	e := &ARXEstimator{
+		Config: ARXEstimatorConfig{OutputOrder: 2, InputOrder: 2, InputDelay: 1, ForgettingFactor: 0.98},
+	}
+	input := func(k int) ARXEstimatorInput {
+		return ARXEstimatorInput{
+			ProcessInput:     math.Sin(float64(k) / 3),
+			ProcessOutput:    math.Cos(float64(k) / 7),
+			SamplingInterval: 100 * time.Millisecond,
+		}
+	}
+	for k := range 20 {
+		e.Update(input(k))
+	}
+	saved := e.State.Clone()
+	// When updating further, and restoring the saved state in a new estimator
+	for k := 20; k < 40; k++ {
+		e.Update(input(k))
+	}
+	restored := &ARXEstimator{Config: e.Config, State: saved}
+	for k := 20; k < 40; k++ {
+		restored.Update(input(k))
+	}
+	// Then the restored estimator should continue from the saved state
+	assert.DeepEqual(t, e.State, restored.State)
+}
+
+func TestARXEstimatorConfig_Validate(t *testing.T) {
+	valid := ARXEstimatorConfig{OutputOrder: 1, InputOrder: 1, ForgettingFactor: 1}
+	assert.NilError(t, valid.Validate())
+	for _, tt := range []struct {
+		name     string
+		modify   func(*ARXEstimatorConfig)
+		expected string
+	}{
+		{name: "negative output order", modify: func(c *ARXEstimatorConfig) { c.OutputOrder = -1 }},
+		{name: "non-positive input order", modify: func(c *ARXEstimatorConfig) { c.InputOrder = 0 }},
+		{name: "negative input delay", modify: func(c *ARXEstimatorConfig) { c.InputDelay = -1 }},
+		{
+			name:     "forgetting factor",
+			modify:   func(c *ARXEstimatorConfig) { c.ForgettingFactor = 0 },
+			expected: "forgetting factor 0 outside of (0, 1]",
+		},
+		{name: "invalid initial covariance", modify: func(c *ARXEstimatorConfig) { c.InitialCovariance = -1 }},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			config := valid
+			tt.modify(&config)
+			expected := tt.expected
+			if expected == "" {
+				expected = tt.name
+			}
+			assert.ErrorContains(t, config.Validate(), "tuning: invalid ARX estimator config: "+expected)
+		})
+	}
+}
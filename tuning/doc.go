@@ -0,0 +1,8 @@
+// Package tuning provides process model identification, PID tuning rules and robustness analysis of control
+// loops.
+//
+// A typical workflow identifies a FOPDT model from logged data with IdentifyFOPDT, tunes the controller gains
+// with Tune, and analyses the robustness of the resulting loop with ComputeMargins. For online tuning, an
+// ARXEstimator estimates the process model recursively, and a SelfTuningController periodically retunes a
+// controller from the estimate.
+package tuning
@@ -0,0 +1,116 @@
+package tuning
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"go.einride.tech/pid/model"
+)
+
+// FOPDTFit is the result of a FOPDT model identification.
+type FOPDTFit struct {
+	// Model is the identified model.
+	Model model.FOPDT
+	// InputOffset is the input around which the model was identified.
+	InputOffset float64
+	// OutputOffset is the output around which the model was identified.
+	OutputOffset float64
+	// Fit is the normalized fit of the simulated model output to the measured output in percent, where 100 is a
+	// perfect fit and 0 is no better than a constant output.
+	Fit float64
+}
+
+// IdentifyFOPDT identifies a FOPDT model from an input and output signal sampled at a fixed sampling interval.
+//
+// The model is estimated with least squares for each dead time up to maxDeadTime, and the dead time with the
+// best fit of the simulated model output is selected. The data should start in steady state and contain at
+// least one step-like change of the input.
+func IdentifyFOPDT(input, output []float64, samplingInterval, maxDeadTime time.Duration) (FOPDTFit, error) {
+	if len(input) != len(output) {
+		return FOPDTFit{}, fmt.Errorf(
+			"tuning: identify FOPDT: mismatched signal lengths %d and %d", len(input), len(output),
+		)
+	}
+	if samplingInterval <= 0 {
+		return FOPDTFit{}, fmt.Errorf("tuning: identify FOPDT: non-positive sampling interval %v", samplingInterval)
+	}
+	for i := range input {
+		if math.IsNaN(input[i]) || math.IsInf(input[i], 0) || math.IsNaN(output[i]) || math.IsInf(output[i], 0) {
+			return FOPDTFit{}, fmt.Errorf("tuning: identify FOPDT: invalid sample %d", i)
+		}
+	}
+	maxDelay := int(maxDeadTime / samplingInterval)
+	if len(input) < maxDelay+3 {
+		return FOPDTFit{}, fmt.Errorf("tuning: identify FOPDT: too few samples %d", len(input))
+	}
+	// Identify around the initial steady state.
+	u0, y0 := input[0], output[0]
+	u, y := make([]float64, len(input)), make([]float64, len(output))
+	for i := range input {
+		u[i], y[i] = input[i]-u0, output[i]-y0
+	}
+	var outputVariation float64
+	mean := meanOf(y)
+	for _, yi := range y {
+		outputVariation += (yi - mean) * (yi - mean)
+	}
+	if outputVariation == 0 {
+		return FOPDTFit{}, fmt.Errorf("tuning: identify FOPDT: constant output")
+	}
+	best := FOPDTFit{Fit: math.Inf(-1)}
+	for delay := 0; delay <= maxDelay; delay++ {
+		// Fit y[k+1] = a y[k] + b u[k-delay] with least squares.
+		var syy, syu, suu, sy1y, sy1u float64
+		for k := delay; k+1 < len(y); k++ {
+			yk, uk := y[k], u[k-delay]
+			syy += yk * yk
+			syu += yk * uk
+			suu += uk * uk
+			sy1y += y[k+1] * yk
+			sy1u += y[k+1] * uk
+		}
+		det := syy*suu - syu*syu
+		if det == 0 {
+			continue
+		}
+		a := (sy1y*suu - sy1u*syu) / det
+		b := (syy*sy1u - syu*sy1y) / det
+		if a <= 0 || a >= 1 {
+			continue
+		}
+		// Evaluate the fit of the simulated model output.
+		var residual, state float64
+		for k := range y {
+			residual += (y[k] - state) * (y[k] - state)
+			if k >= delay {
+				state = a*state + b*u[k-delay]
+			}
+		}
+		fit := 100 * (1 - math.Sqrt(residual/outputVariation))
+		if fit > best.Fit {
+			best = FOPDTFit{
+				Model: model.FOPDT{
+					Gain:         b / (1 - a),
+					TimeConstant: time.Duration(-float64(samplingInterval) / math.Log(a)),
+					DeadTime:     time.Duration(delay) * samplingInterval,
+				},
+				InputOffset:  u0,
+				OutputOffset: y0,
+				Fit:          fit,
+			}
+		}
+	}
+	if math.IsInf(best.Fit, -1) {
+		return FOPDTFit{}, fmt.Errorf("tuning: identify FOPDT: no stable first-order model fits the data")
+	}
+	return best, nil
+}
+
+func meanOf(x []float64) float64 {
+	var result float64
+	for _, xi := range x {
+		result += xi
+	}
+	return result / float64(len(x))
+}
@@ -0,0 +1,56 @@
+package tuning
+
+import (
+	"math"
+	"math/rand/v2"
+	"testing"
+	"time"
+
+	"go.einride.tech/pid/model"
+	"gotest.tools/v3/assert"
+)
+
+func TestIdentifyFOPDT(t *testing.T) {
+	// Given step response data of a FOPDT process around an operating point, with measurement noise
+	expected := model.FOPDT{Gain: 2, TimeConstant: 5 * time.Second, DeadTime: 1500 * time.Millisecond}
+	s, err := model.NewSimulation(expected)
+	assert.NilError(t, err)
+	noise := rand.New(rand.NewPCG(1, 2))
+	const dt = 100 * time.Millisecond
+	var input, output []float64
+	for k := range 600 {
+		u := 0.0
+		if k >= 50 {
+			u = 1
+		}
+		if k >= 300 {
+			u = -0.5
+		}
+		input = append(input, 10+u)
+		output = append(output, 20+s.Output()+0.01*noise.NormFloat64())
+		s.Update(u, dt)
+	}
+	// When identifying a FOPDT model
+	fit, err := IdentifyFOPDT(input, output, dt, 5*time.Second)
+	// Then the identified model should be close to the process
+	assert.NilError(t, err)
+	assert.Assert(t, math.Abs(fit.Model.Gain-2) < 0.05, fit.Model)
+	assert.Assert(t, (fit.Model.TimeConstant-5*time.Second).Abs() < 300*time.Millisecond, fit.Model)
+	assert.Assert(t, (fit.Model.DeadTime-1500*time.Millisecond).Abs() <= 200*time.Millisecond, fit.Model)
+	assert.Equal(t, 10.0, fit.InputOffset)
+	assert.Assert(t, math.Abs(fit.OutputOffset-20) < 0.05)
+	assert.Assert(t, fit.Fit > 95, fit.Fit)
+}
+
+func TestIdentifyFOPDT_Errors(t *testing.T) {
+	_, err := IdentifyFOPDT([]float64{1}, []float64{1, 2}, time.Second, 0)
+	assert.ErrorContains(t, err, "mismatched signal lengths")
+	_, err = IdentifyFOPDT([]float64{1, 1, 1}, []float64{1, 1, 1}, 0, 0)
+	assert.ErrorContains(t, err, "sampling interval")
+	_, err = IdentifyFOPDT([]float64{0, 1, 1}, []float64{1, 1, 1}, time.Second, 0)
+	assert.ErrorContains(t, err, "constant output")
+	_, err = IdentifyFOPDT([]float64{0, math.NaN(), 1}, []float64{1, 1, 1}, time.Second, 0)
+	assert.ErrorContains(t, err, "invalid sample 1")
+	_, err = IdentifyFOPDT([]float64{0, 1}, []float64{1, 2}, time.Second, 10*time.Second)
+	assert.ErrorContains(t, err, "too few samples")
+}
@@ -0,0 +1,91 @@
+package tuning
+
+import (
+	"math"
+	"math/cmplx"
+)
+
+// FrequencyResponder is a linear system with a frequency response, such as a model.TransferFunction,
+// a model.FOPDT or controller Gains.
+type FrequencyResponder interface {
+	// FrequencyResponse returns the frequency response for the angular frequency ω (rad/s).
+	FrequencyResponse(omega float64) complex128
+}
+
+// Margins holds robustness margins of a control loop.
+type Margins struct {
+	// GainMargin is the factor by which the loop gain can increase before the loop becomes unstable. +Inf if the
+	// phase of the loop never reaches -180°.
+	GainMargin float64
+	// PhaseCrossoverFrequency is the angular frequency (rad/s) at which the phase of the loop is -180°.
+	PhaseCrossoverFrequency float64
+	// PhaseMargin is the additional phase lag (degrees) at the gain crossover frequency that makes the loop
+	// unstable. +Inf if the loop gain never crosses 1.
+	PhaseMargin float64
+	// GainCrossoverFrequency is the angular frequency (rad/s) at which the loop gain is 1.
+	GainCrossoverFrequency float64
+	// MaxSensitivity is the peak of the sensitivity function |1/(1+L)|, which is the inverse of the shortest
+	// distance from the loop frequency response to the critical point -1. Values below 2 are typically robust.
+	MaxSensitivity float64
+}
+
+const (
+	marginsMinFrequency = 1e-4
+	marginsMaxFrequency = 1e4
+	marginsSamples      = 4000
+)
+
+// ComputeMargins computes the robustness margins of the loop of the controller and the process, by a
+// logarithmic sweep of the loop frequency response L(jω) = C(jω)P(jω) from 1e-4 to 1e4 rad/s.
+func ComputeMargins(controller, process FrequencyResponder) Margins {
+	loop := func(omega float64) complex128 {
+		return controller.FrequencyResponse(omega) * process.FrequencyResponse(omega)
+	}
+	result := Margins{GainMargin: math.Inf(1), PhaseMargin: math.Inf(1)}
+	var gainCrossed, phaseCrossed bool
+	var previousOmega, previousPhase, previousGain float64
+	for i := range marginsSamples {
+		omega := marginsMinFrequency * math.Pow(marginsMaxFrequency/marginsMinFrequency, float64(i)/(marginsSamples-1))
+		l := loop(omega)
+		gain := cmplx.Abs(l)
+		// Unwrap the phase, which is continuous for the rational and dead time loops of interest.
+		phase := cmplx.Phase(l)
+		if i > 0 {
+			phase += 2 * math.Pi * math.Round((previousPhase-phase)/(2*math.Pi))
+		}
+		result.MaxSensitivity = math.Max(result.MaxSensitivity, 1/cmplx.Abs(1+l))
+		if i > 0 {
+			if !gainCrossed && previousGain >= 1 && gain < 1 {
+				gainCrossed = true
+				result.GainCrossoverFrequency = interpolateLog(previousOmega, omega, previousGain-1, gain-1)
+				crossoverPhase := unwrappedPhaseNear(loop(result.GainCrossoverFrequency), phase)
+				result.PhaseMargin = 180 + crossoverPhase*180/math.Pi
+			}
+			if !phaseCrossed && previousPhase > -math.Pi && phase <= -math.Pi {
+				phaseCrossed = true
+				result.PhaseCrossoverFrequency = interpolateLog(
+					previousOmega, omega, previousPhase+math.Pi, phase+math.Pi,
+				)
+				result.GainMargin = 1 / cmplx.Abs(loop(result.PhaseCrossoverFrequency))
+			}
+		}
+		previousOmega, previousPhase, previousGain = omega, phase, gain
+	}
+	return result
+}
+
+// interpolateLog returns the frequency between omega0 and omega1 where the linearly interpolated function with
+// values f0 and f1 crosses zero, interpolating in log frequency.
+func interpolateLog(omega0, omega1, f0, f1 float64) float64 {
+	if f0 == f1 {
+		return omega0
+	}
+	x := f0 / (f0 - f1)
+	return math.Exp(math.Log(omega0) + x*(math.Log(omega1)-math.Log(omega0)))
+}
+
+// unwrappedPhaseNear returns the phase of l unwrapped to the branch closest to the reference phase.
+func unwrappedPhaseNear(l complex128, reference float64) float64 {
+	phase := cmplx.Phase(l)
+	return phase + 2*math.Pi*math.Round((reference-phase)/(2*math.Pi))
+}
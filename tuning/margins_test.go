@@ -0,0 +1,40 @@
+package tuning
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"go.einride.tech/pid/model"
+	"gotest.tools/v3/assert"
+)
+
+func TestComputeMargins_IntegratorWithDeadTime(t *testing.T) {
+	// Given the loop L(s) = e^(-s)/s
+	controller := Gains{IntegralGain: 1}
+	process := model.TransferFunction{Numerator: []float64{1}, Denominator: []float64{1}, DeadTime: time.Second}
+	// When computing the margins
+	margins := ComputeMargins(controller, process)
+	// Then the gain crossover should be at 1 rad/s with a phase margin of 90° - 1 rad
+	assert.Assert(t, math.Abs(margins.GainCrossoverFrequency-1) < 1e-3, margins.GainCrossoverFrequency)
+	assert.Assert(t, math.Abs(margins.PhaseMargin-(90-180/math.Pi)) < 0.1, margins.PhaseMargin)
+	// And the phase crossover should be at π/2 rad/s with a gain margin of π/2
+	assert.Assert(t, math.Abs(margins.PhaseCrossoverFrequency-math.Pi/2) < 1e-3, margins.PhaseCrossoverFrequency)
+	assert.Assert(t, math.Abs(margins.GainMargin-math.Pi/2) < 1e-3, margins.GainMargin)
+	assert.Assert(t, margins.MaxSensitivity > 1)
+}
+
+func TestComputeMargins_FirstOrder(t *testing.T) {
+	// Given a P controller of a first-order process without dead time
+	controller := Gains{ProportionalGain: 4}
+	process := model.FOPDT{Gain: 1, TimeConstant: time.Second}
+	// When computing the margins
+	margins := ComputeMargins(controller, process)
+	// Then the phase never reaches -180° and the gain margin is infinite
+	assert.Assert(t, math.IsInf(margins.GainMargin, 1))
+	// And the gain crossover is at sqrt(15) rad/s
+	assert.Assert(t, math.Abs(margins.GainCrossoverFrequency-math.Sqrt(15)) < 1e-2)
+	assert.Assert(t, math.Abs(margins.PhaseMargin-(180-math.Atan(math.Sqrt(15))*180/math.Pi)) < 0.1)
+	// And the sensitivity peaks at 1 at high frequencies
+	assert.Assert(t, math.Abs(margins.MaxSensitivity-1) < 1e-3)
+}
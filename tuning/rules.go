@@ -0,0 +1,203 @@
+package tuning
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"go.einride.tech/pid"
+	"go.einride.tech/pid/model"
+)
+
+// Rule is a tuning rule for a FOPDT process model.
+type Rule int
+
+const (
+	// RuleZieglerNicholsPI is the Ziegler-Nichols step response rule for a PI controller.
+	RuleZieglerNicholsPI Rule = iota + 1
+	// RuleZieglerNicholsPID is the Ziegler-Nichols step response rule for a PID controller.
+	RuleZieglerNicholsPID
+	// RuleCohenCoonPI is the Cohen-Coon rule for a PI controller.
+	RuleCohenCoonPI
+	// RuleCohenCoonPID is the Cohen-Coon rule for a PID controller.
+	RuleCohenCoonPID
+	// RuleSIMCPI is the Skogestad SIMC rule for a PI controller, with the closed-loop time constant equal to the
+	// dead time.
+	RuleSIMCPI
+	// RuleAMIGOPI is the Åström-Hägglund AMIGO rule for a PI controller.
+	RuleAMIGOPI
+	// RuleAMIGOPID is the Åström-Hägglund AMIGO rule for a PID controller.
+	RuleAMIGOPID
+	// RuleLambdaPI is the lambda rule for a PI controller, with the closed-loop time constant equal to the
+	// process time constant.
+	RuleLambdaPI
+)
+
+var ruleNames = map[Rule]string{
+	RuleZieglerNicholsPI:  "ziegler-nichols-pi",
+	RuleZieglerNicholsPID: "ziegler-nichols-pid",
+	RuleCohenCoonPI:       "cohen-coon-pi",
+	RuleCohenCoonPID:      "cohen-coon-pid",
+	RuleSIMCPI:            "simc-pi",
+	RuleAMIGOPI:           "amigo-pi",
+	RuleAMIGOPID:          "amigo-pid",
+	RuleLambdaPI:          "lambda-pi",
+}
+
+// Rules returns all tuning rules.
+func Rules() []Rule {
+	return []Rule{
+		RuleZieglerNicholsPI,
+		RuleZieglerNicholsPID,
+		RuleCohenCoonPI,
+		RuleCohenCoonPID,
+		RuleSIMCPI,
+		RuleAMIGOPI,
+		RuleAMIGOPID,
+		RuleLambdaPI,
+	}
+}
+
+// String implements fmt.Stringer.
+func (r Rule) String() string {
+	if name, ok := ruleNames[r]; ok {
+		return name
+	}
+	return fmt.Sprintf("Rule(%d)", int(r))
+}
+
+// ParseRule parses a tuning rule from its name, as returned by Rule.String.
+func ParseRule(name string) (Rule, error) {
+	for rule, ruleName := range ruleNames {
+		if name == ruleName {
+			return rule, nil
+		}
+	}
+	return 0, fmt.Errorf("tuning: parse rule: unknown rule %q", name)
+}
+
+// Gains holds the gains of a PID controller in parallel form,
+//
+//	C(s) = Kp + Ki/s + Kd s/(Tf s + 1).
+type Gains struct {
+	// ProportionalGain is the P part gain Kp.
+	ProportionalGain float64
+	// IntegralGain is the I part gain Ki.
+	IntegralGain float64
+	// DerivativeGain is the D part gain Kd.
+	DerivativeGain float64
+	// LowPassTimeConstant is the D part low-pass filter time constant Tf.
+	LowPassTimeConstant time.Duration
+}
+
+// derivativeFilterRatio is the ratio between the derivative time and the derivative filter time constant.
+const derivativeFilterRatio = 10
+
+// Tune returns the controller gains given by the tuning rule for the FOPDT model.
+//
+// For rules without a D part, the derivative filter time constant is a tenth of the process time constant, so
+// that the gains give a valid AntiWindupControllerConfig.
+func Tune(m model.FOPDT, rule Rule) (Gains, error) {
+	k, t, l := m.Gain, m.TimeConstant.Seconds(), m.DeadTime.Seconds()
+	if k == 0 || math.IsNaN(k) || math.IsInf(k, 0) {
+		return Gains{}, fmt.Errorf("tuning: tune %v: invalid process gain %v", rule, k)
+	}
+	if t <= 0 {
+		return Gains{}, fmt.Errorf("tuning: tune %v: non-positive time constant %v", rule, m.TimeConstant)
+	}
+	if l <= 0 && rule != RuleLambdaPI {
+		return Gains{}, fmt.Errorf("tuning: tune %v: non-positive dead time %v", rule, m.DeadTime)
+	}
+	var kp, ti, td float64
+	switch rule {
+	case RuleZieglerNicholsPI:
+		kp, ti = 0.9*t/(k*l), 3*l
+	case RuleZieglerNicholsPID:
+		kp, ti, td = 1.2*t/(k*l), 2*l, l/2
+	case RuleCohenCoonPI:
+		kp = t / (k * l) * (0.9 + l/(12*t))
+		ti = l * (30 + 3*l/t) / (9 + 20*l/t)
+	case RuleCohenCoonPID:
+		kp = t / (k * l) * (4.0/3 + l/(4*t))
+		ti = l * (32 + 6*l/t) / (13 + 8*l/t)
+		td = 4 * l / (11 + 2*l/t)
+	case RuleSIMCPI:
+		closedLoopTimeConstant := l
+		kp = t / (k * (closedLoopTimeConstant + l))
+		ti = math.Min(t, 4*(closedLoopTimeConstant+l))
+	case RuleAMIGOPI:
+		kp = 0.15/k + (0.35-l*t/((l+t)*(l+t)))*t/(k*l)
+		ti = 0.35*l + 13*l*t*t/(t*t+12*l*t+7*l*l)
+	case RuleAMIGOPID:
+		kp = (0.2 + 0.45*t/l) / k
+		ti = (0.4*l + 0.8*t) / (l + 0.1*t) * l
+		td = 0.5 * l * t / (0.3*l + t)
+	case RuleLambdaPI:
+		closedLoopTimeConstant := t
+		kp, ti = t/(k*(closedLoopTimeConstant+l)), t
+	default:
+		return Gains{}, fmt.Errorf("tuning: tune: unknown rule %v", rule)
+	}
+	lowPassTimeConstant := td / derivativeFilterRatio
+	if td == 0 {
+		lowPassTimeConstant = t / derivativeFilterRatio
+	}
+	return Gains{
+		ProportionalGain:    kp,
+		IntegralGain:        kp / ti,
+		DerivativeGain:      kp * td,
+		LowPassTimeConstant: time.Duration(lowPassTimeConstant * float64(time.Second)),
+	}, nil
+}
+
+// FrequencyResponse returns the controller frequency response C(jω) for the angular frequency ω (rad/s).
+func (g Gains) FrequencyResponse(omega float64) complex128 {
+	s := complex(0, omega)
+	return complex(g.ProportionalGain, 0) + complex(g.IntegralGain, 0)/s +
+		complex(g.DerivativeGain, 0)*s/(complex(g.LowPassTimeConstant.Seconds(), 0)*s+1)
+}
+
+// ControllerConfig returns a pid.ControllerConfig with the gains.
+func (g Gains) ControllerConfig() pid.ControllerConfig {
+	return pid.ControllerConfig{
+		ProportionalGain: g.ProportionalGain,
+		IntegralGain:     g.IntegralGain,
+		DerivativeGain:   g.DerivativeGain,
+	}
+}
+
+// AntiWindupControllerConfig returns a pid.AntiWindupControllerConfig with the gains and output limits.
+//
+// The anti-windup gain gives a tracking time constant of sqrt(Ti Td) for PID controllers and Ti for PI
+// controllers, as recommended by Åström and Hägglund, and the integral discharge time constant is Ti.
+func (g Gains) AntiWindupControllerConfig(minOutput, maxOutput float64) pid.AntiWindupControllerConfig {
+	config := pid.AntiWindupControllerConfig{
+		ProportionalGain:    g.ProportionalGain,
+		IntegralGain:        g.IntegralGain,
+		DerivativeGain:      g.DerivativeGain,
+		LowPassTimeConstant: g.LowPassTimeConstant,
+		MinOutput:           minOutput,
+		MaxOutput:           maxOutput,
+	}
+	if ti := g.ProportionalGain / g.IntegralGain; g.IntegralGain != 0 && ti > 0 {
+		td := g.DerivativeGain / g.ProportionalGain
+		trackingTimeConstant := ti
+		if td > 0 {
+			trackingTimeConstant = math.Sqrt(ti * td)
+		}
+		// The integrand is scaled by the integral gain, so 1/Tt = Ki Kaw.
+		config.AntiWindUpGain = 1 / (g.IntegralGain * trackingTimeConstant)
+		config.IntegralDischargeTimeConstant = ti
+	}
+	return config
+}
+
+// GainsOfAntiWindupControllerConfig returns the gains of a pid.AntiWindupControllerConfig.
+func GainsOfAntiWindupControllerConfig(config pid.AntiWindupControllerConfig) Gains {
+	return Gains{
+		ProportionalGain:    config.ProportionalGain,
+		IntegralGain:        config.IntegralGain,
+		DerivativeGain:      config.DerivativeGain,
+		LowPassTimeConstant: config.LowPassTimeConstant,
+	}
+}
@@ -0,0 +1,104 @@
+package tuning
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"go.einride.tech/pid"
+	"go.einride.tech/pid/model"
+	"go.einride.tech/pid/sim"
+	"gotest.tools/v3/assert"
+)
+
+func TestTune(t *testing.T) {
+	m := model.FOPDT{Gain: 2, TimeConstant: 10 * time.Second, DeadTime: 2 * time.Second}
+	for _, tt := range []struct {
+		rule   Rule
+		kp, ti float64
+		td     float64
+	}{
+		{rule: RuleZieglerNicholsPI, kp: 2.25, ti: 6},
+		{rule: RuleZieglerNicholsPID, kp: 3, ti: 4, td: 1},
+		{rule: RuleSIMCPI, kp: 1.25, ti: 10},
+		{rule: RuleLambdaPI, kp: 10.0 / 24, ti: 10},
+		{rule: RuleAMIGOPID, kp: 1.225, ti: 8.8 / 3 * 2, td: 10.0 / 10.6},
+	} {
+		t.Run(tt.rule.String(), func(t *testing.T) {
+			gains, err := Tune(m, tt.rule)
+			assert.NilError(t, err)
+			assert.Assert(t, math.Abs(gains.ProportionalGain-tt.kp) < 1e-9, gains.ProportionalGain)
+			assert.Assert(t, math.Abs(gains.ProportionalGain/gains.IntegralGain-tt.ti) < 1e-9)
+			assert.Assert(t, math.Abs(gains.DerivativeGain/gains.ProportionalGain-tt.td) < 1e-9)
+			assert.Assert(t, gains.LowPassTimeConstant > 0)
+		})
+	}
+}
+
+func TestTune_AllRulesGiveStableLoops(t *testing.T) {
+	m := model.FOPDT{Gain: 2, TimeConstant: 10 * time.Second, DeadTime: 2 * time.Second}
+	for _, rule := range Rules() {
+		t.Run(rule.String(), func(t *testing.T) {
+			// Given the gains of the tuning rule
+			gains, err := Tune(m, rule)
+			assert.NilError(t, err)
+			// When simulating a reference step
+			trace, err := sim.Simulate(sim.Config{
+				Controller:       sim.NewAntiWindupController(gains.AntiWindupControllerConfig(-100, 100)),
+				Plant:            m,
+				SamplingInterval: 10 * time.Millisecond,
+				Duration:         200 * time.Second,
+				Reference:        []sim.Step{{Time: 0, Value: 1}},
+			})
+			assert.NilError(t, err)
+			// Then the loop should be stable and settle at the reference
+			assert.Assert(t, math.Abs(trace.Samples[len(trace.Samples)-1].ActualSignal-1) < 1e-2)
+			// And the margins should indicate a stable loop
+			margins := ComputeMargins(gains, m)
+			assert.Assert(t, margins.GainMargin > 1 && margins.PhaseMargin > 0, "%+v", margins)
+		})
+	}
+}
+
+func TestTune_Errors(t *testing.T) {
+	_, err := Tune(model.FOPDT{TimeConstant: time.Second, DeadTime: time.Second}, RuleSIMCPI)
+	assert.ErrorContains(t, err, "invalid process gain")
+	_, err = Tune(model.FOPDT{Gain: 1, DeadTime: time.Second}, RuleSIMCPI)
+	assert.ErrorContains(t, err, "non-positive time constant")
+	_, err = Tune(model.FOPDT{Gain: 1, TimeConstant: time.Second}, RuleAMIGOPI)
+	assert.ErrorContains(t, err, "non-positive dead time")
+	_, err = Tune(model.FOPDT{Gain: 1, TimeConstant: time.Second, DeadTime: time.Second}, Rule(100))
+	assert.ErrorContains(t, err, "unknown rule")
+}
+
+func TestParseRule(t *testing.T) {
+	for _, rule := range Rules() {
+		parsed, err := ParseRule(rule.String())
+		assert.NilError(t, err)
+		assert.Equal(t, rule, parsed)
+	}
+	_, err := ParseRule("magic")
+	assert.ErrorContains(t, err, `unknown rule "magic"`)
+}
+
+func TestGains_AntiWindupControllerConfig(t *testing.T) {
+	// Given PID gains with Ti = 4 s and Td = 1 s
+	gains := Gains{ProportionalGain: 2, IntegralGain: 0.5, DerivativeGain: 2, LowPassTimeConstant: 100 * time.Millisecond}
+	// When converting to an AntiWindupControllerConfig
+	config := gains.AntiWindupControllerConfig(-1, 1)
+	// Then the tracking time constant should be sqrt(Ti Td) = 2 s
+	assert.Equal(t, pid.AntiWindupControllerConfig{
+		ProportionalGain:              2,
+		IntegralGain:                  0.5,
+		DerivativeGain:                2,
+		AntiWindUpGain:                1,
+		IntegralDischargeTimeConstant: 4,
+		LowPassTimeConstant:           100 * time.Millisecond,
+		MinOutput:                     -1,
+		MaxOutput:                     1,
+	}, config)
+	assert.Equal(t, gains, GainsOfAntiWindupControllerConfig(config))
+	assert.Equal(
+		t, pid.ControllerConfig{ProportionalGain: 2, IntegralGain: 0.5, DerivativeGain: 2}, gains.ControllerConfig(),
+	)
+}
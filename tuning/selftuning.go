@@ -0,0 +1,205 @@
+package tuning
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"go.einride.tech/pid"
+	"go.einride.tech/pid/model"
+)
+
+// SelfTuningController implements a self-tuning regulator, which estimates an ARX model of the process online and
+// periodically recomputes the gains of a wrapped pid.AntiWindupController from a FOPDT approximation of the
+// estimated model with a tuning rule.
+//
+// The control signal of each update is used as the process input of the estimator, so the controller assumes
+// that the control signal is applied to the process. The gains are only recomputed at the end of a tuning
+// interval with enough excitation of the process input, and the change of each gain per tuning interval is
+// limited. New gains are applied with a bumpless transfer.
+type SelfTuningController struct {
+	// Config for the SelfTuningController.
+	Config SelfTuningControllerConfig
+	// State of the SelfTuningController.
+	State SelfTuningControllerState
+
+	// estimator holds the scratch space of the ARX model estimator across updates.
+	estimator ARXEstimator
+}
+
+// SelfTuningControllerConfig contains config parameters for a SelfTuningController.
+type SelfTuningControllerConfig struct {
+	// Controller is the config of the wrapped controller, with the initial gains. Only the gains are recomputed,
+	// and the other parameters of the config are kept.
+	Controller pid.AntiWindupControllerConfig
+	// Estimator is the config of the ARX model estimator.
+	Estimator ARXEstimatorConfig
+	// Rule is the tuning rule.
+	Rule Rule
+	// TuningInterval is the interval between recomputations of the gains.
+	TuningInterval time.Duration
+	// MinExcitation is the min standard deviation of the process input over a tuning interval for the gains to be
+	// recomputed.
+	MinExcitation float64
+	// MaxGainChange is the max change of each gain per tuning interval, relative to the magnitude of the current
+	// gain. A current gain of zero may change to any value. Zero disables the limit.
+	MaxGainChange float64
+}
+
+// SelfTuningControllerState holds mutable state for a SelfTuningController.
+type SelfTuningControllerState struct {
+	// Controller is the state of the wrapped controller.
+	Controller pid.AntiWindupControllerState
+	// Estimator is the state of the ARX model estimator.
+	Estimator ARXEstimatorState
+	// Model is the FOPDT approximation of the estimated model of the latest tuning.
+	Model model.FOPDT
+	// Tunings is the number of recomputations of the gains.
+	Tunings int
+	// SkippedTunings is the number of tuning intervals without a recomputation of the gains, due to too low
+	// excitation or an estimated model that could not be tuned.
+	SkippedTunings int
+	// ControllerConfig is the config of the wrapped controller with the gains of the latest tuning. It is only
+	// valid when Tunings is positive.
+	ControllerConfig pid.AntiWindupControllerConfig
+	// Elapsed is the elapsed time of the current tuning interval.
+	Elapsed time.Duration
+	// Inputs is the number of process inputs of the current tuning interval.
+	Inputs int
+	// InputSum is the sum of the process inputs of the current tuning interval.
+	InputSum float64
+	// InputSquareSum is the sum of squares of the process inputs of the current tuning interval.
+	InputSquareSum float64
+}
+
+// Clone returns a copy of the state that does not share memory with the state, see ARXEstimatorState.Clone.
+func (s SelfTuningControllerState) Clone() SelfTuningControllerState {
+	s.Estimator = s.Estimator.Clone()
+	return s
+}
+
+// Validate returns an error when the config of the wrapped controller or the estimator is invalid, when the rule
+// is unknown, when the tuning interval is not positive, or when the excitation or gain change limit is negative.
+func (c SelfTuningControllerConfig) Validate() error {
+	if err := c.Controller.Validate(); err != nil {
+		return fmt.Errorf("tuning: invalid self-tuning controller config: %w", err)
+	}
+	if err := c.Estimator.Validate(); err != nil {
+		return err
+	}
+	if _, ok := ruleNames[c.Rule]; !ok {
+		return fmt.Errorf("tuning: invalid self-tuning controller config: unknown rule %v", c.Rule)
+	}
+	switch {
+	case c.TuningInterval <= 0:
+		return fmt.Errorf("tuning: invalid self-tuning controller config: non-positive tuning interval %v",
+			c.TuningInterval)
+	case !(c.MinExcitation >= 0) || math.IsInf(c.MinExcitation, 0):
+		return fmt.Errorf("tuning: invalid self-tuning controller config: invalid min excitation %v",
+			c.MinExcitation)
+	case !(c.MaxGainChange >= 0) || math.IsInf(c.MaxGainChange, 0):
+		return fmt.Errorf("tuning: invalid self-tuning controller config: invalid max gain change %v",
+			c.MaxGainChange)
+	}
+	return nil
+}
+
+// Reset the controller state, which restores the initial gains and discards the estimated model.
+func (c *SelfTuningController) Reset() {
+	c.State = SelfTuningControllerState{}
+}
+
+// Update the controller state.
+func (c *SelfTuningController) Update(input pid.AntiWindupControllerInput) {
+	if isNaNOrInf(input.ReferenceSignal) || isNaNOrInf(input.ActualSignal) {
+		return
+	}
+	controller := c.controller()
+	controller.Update(input)
+	c.State.Controller = controller.State
+	estimator := &c.estimator
+	estimator.Config, estimator.State = c.Config.Estimator, c.State.Estimator
+	estimator.Update(ARXEstimatorInput{
+		ProcessInput:     controller.State.ControlSignal,
+		ProcessOutput:    input.ActualSignal,
+		SamplingInterval: input.SamplingInterval,
+	})
+	c.State.Estimator = estimator.State
+	c.State.Inputs++
+	c.State.InputSum += controller.State.ControlSignal
+	c.State.InputSquareSum += controller.State.ControlSignal * controller.State.ControlSignal
+	c.State.Elapsed += input.SamplingInterval
+	if c.State.Elapsed < c.Config.TuningInterval {
+		return
+	}
+	if c.tune(estimator.Model()) {
+		c.State.Tunings++
+	} else {
+		c.State.SkippedTunings++
+	}
+	c.State.Elapsed = 0
+	c.State.Inputs, c.State.InputSum, c.State.InputSquareSum = 0, 0, 0
+}
+
+// tune recomputes the gains from the model when the excitation is high enough, and reports if it did.
+func (c *SelfTuningController) tune(arx ARX) bool {
+	n := float64(c.State.Inputs)
+	variance := c.State.InputSquareSum/n - (c.State.InputSum/n)*(c.State.InputSum/n)
+	if math.Sqrt(math.Max(0, variance)) < c.Config.MinExcitation {
+		return false
+	}
+	fopdt, err := arx.FOPDT()
+	if err != nil {
+		return false
+	}
+	gains, err := Tune(fopdt, c.Config.Rule)
+	if err != nil {
+		return false
+	}
+	controller := c.controller()
+	gains.ProportionalGain = limitGainChange(
+		controller.Config.ProportionalGain, gains.ProportionalGain, c.Config.MaxGainChange,
+	)
+	gains.IntegralGain = limitGainChange(controller.Config.IntegralGain, gains.IntegralGain, c.Config.MaxGainChange)
+	gains.DerivativeGain = limitGainChange(
+		controller.Config.DerivativeGain, gains.DerivativeGain, c.Config.MaxGainChange,
+	)
+	config := controller.Config
+	config.ProportionalGain, config.IntegralGain, config.DerivativeGain =
+		gains.ProportionalGain, gains.IntegralGain, gains.DerivativeGain
+	if config.Validate() != nil {
+		return false
+	}
+	controller.BumplessTransfer(config)
+	c.State.Controller = controller.State
+	c.State.ControllerConfig = config
+	c.State.Model = fopdt
+	return true
+}
+
+// DischargeIntegral provides the ability to discharge the controller integral state
+// over a configurable period of time.
+func (c *SelfTuningController) DischargeIntegral(dt time.Duration) {
+	controller := c.controller()
+	controller.DischargeIntegral(dt)
+	c.State.Controller = controller.State
+}
+
+// controller returns the wrapped controller with the current gains.
+func (c *SelfTuningController) controller() pid.AntiWindupController {
+	config := c.Config.Controller
+	if c.State.Tunings > 0 {
+		config = c.State.ControllerConfig
+	}
+	return pid.AntiWindupController{Config: config, State: c.State.Controller}
+}
+
+// limitGainChange limits the change from the current to the target gain to maxChange times the magnitude of the
+// current gain.
+func limitGainChange(current, target, maxChange float64) float64 {
+	if maxChange == 0 || current == 0 {
+		return target
+	}
+	limit := maxChange * math.Abs(current)
+	return math.Min(math.Max(target, current-limit), current+limit)
+}
@@ -0,0 +1,229 @@
+package tuning
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"go.einride.tech/pid"
+	"go.einride.tech/pid/model"
+	"gotest.tools/v3/assert"
+)
+
+// runSelfTuningController runs the controller in closed loop with a FOPDT process, and a reference signal.
+func runSelfTuningController(
+	t *testing.T, c *SelfTuningController, duration time.Duration, reference func(time.Duration) float64,
+) {
+	t.Helper()
+	process, err := model.NewSimulation(
+		model.FOPDT{Gain: 2, TimeConstant: 5 * time.Second, DeadTime: 500 * time.Millisecond},
+	)
+	assert.NilError(t, err)
+	const dt = 100 * time.Millisecond
+	for now := time.Duration(0); now < duration; now += dt {
+		c.Update(pid.AntiWindupControllerInput{
+			ReferenceSignal:  reference(now),
+			ActualSignal:     process.Output(),
+			SamplingInterval: dt,
+		})
+		process.Update(c.State.Controller.ControlSignal, dt)
+	}
+}
+
+func TestSelfTuningController_Update(t *testing.T) {
+	// Given a poorly tuned self-tuning controller of a FOPDT process
+	c := &SelfTuningController{
+		Config: SelfTuningControllerConfig{
+			Controller: pid.AntiWindupControllerConfig{
+				ProportionalGain:              0.2,
+				IntegralGain:                  0.05,
+				AntiWindUpGain:                0.5,
+				IntegralDischargeTimeConstant: 20,
+				LowPassTimeConstant:           500 * time.Millisecond,
+				MinOutput:                     -10,
+				MaxOutput:                     10,
+			},
+			Estimator:      ARXEstimatorConfig{OutputOrder: 1, InputOrder: 1, InputDelay: 5, ForgettingFactor: 0.995},
+			Rule:           RuleSIMCPI,
+			TuningInterval: 10 * time.Second,
+			MinExcitation:  0.01,
+			MaxGainChange:  0.5,
+		},
+	}
+	assert.NilError(t, c.Config.Validate())
+	// When controlling the process with a square wave reference signal
+	runSelfTuningController(t, c, 200*time.Second, func(now time.Duration) float64 {
+		return math.Copysign(1, math.Sin(2*math.Pi*now.Seconds()/20))
+	})
+	// Then the process model should be estimated
+	assert.Equal(t, 20, c.State.Tunings)
+	assert.Equal(t, 0, c.State.SkippedTunings)
+	assert.Assert(t, math.Abs(c.State.Model.Gain-2) < 0.01, c.State.Model)
+	assert.Assert(t, (c.State.Model.TimeConstant-5*time.Second).Abs() < 50*time.Millisecond, c.State.Model)
+	assert.Equal(t, 550*time.Millisecond, c.State.Model.DeadTime)
+	// And the gains should be given by the tuning rule for the process
+	expected, err := Tune(c.State.Model, RuleSIMCPI)
+	assert.NilError(t, err)
+	assert.Equal(t, expected.ProportionalGain, c.State.ControllerConfig.ProportionalGain)
+	assert.Equal(t, expected.IntegralGain, c.State.ControllerConfig.IntegralGain)
+	// And the other parameters of the config should be kept
+	expectedConfig := c.Config.Controller
+	expectedConfig.ProportionalGain = expected.ProportionalGain
+	expectedConfig.IntegralGain = expected.IntegralGain
+	assert.Equal(t, expectedConfig, c.State.ControllerConfig)
+	// When resetting the controller
+	c.Reset()
+	// Then the initial gains should be restored
+	assert.Equal(t, 0, c.State.Tunings)
+	assert.Equal(t, c.Config.Controller, c.controller().Config)
+}
+
+func TestSelfTuningController_Update_MinExcitation(t *testing.T) {
+	// Given a self-tuning controller
+	c := &SelfTuningController{
+		Config: SelfTuningControllerConfig{
+			Controller: pid.AntiWindupControllerConfig{
+				ProportionalGain:    0.2,
+				IntegralGain:        0.05,
+				LowPassTimeConstant: 500 * time.Millisecond,
+				MinOutput:           -10,
+				MaxOutput:           10,
+			},
+			Estimator:      ARXEstimatorConfig{OutputOrder: 1, InputOrder: 1, InputDelay: 5, ForgettingFactor: 0.995},
+			Rule:           RuleSIMCPI,
+			TuningInterval: 10 * time.Second,
+			MinExcitation:  0.01,
+			MaxGainChange:  0.5,
+		},
+	}
+	// When controlling the process with a constant reference signal
+	runSelfTuningController(t, c, 100*time.Second, func(time.Duration) float64 {
+		return 0
+	})
+	// Then the gains should not be recomputed without excitation
+	assert.Equal(t, 0, c.State.Tunings)
+	assert.Equal(t, 10, c.State.SkippedTunings)
+	assert.Equal(t, c.Config.Controller, c.controller().Config)
+}
+
+func TestSelfTuningController_Update_MaxGainChange(t *testing.T) {
+	// Given a self-tuning controller with a limited gain change
+	c := &SelfTuningController{
+		Config: SelfTuningControllerConfig{
+			Controller: pid.AntiWindupControllerConfig{
+				ProportionalGain:    0.2,
+				IntegralGain:        0.05,
+				LowPassTimeConstant: 500 * time.Millisecond,
+				MinOutput:           -10,
+				MaxOutput:           10,
+			},
+			Estimator:      ARXEstimatorConfig{OutputOrder: 1, InputOrder: 1, InputDelay: 5, ForgettingFactor: 0.995},
+			Rule:           RuleSIMCPI,
+			TuningInterval: 10 * time.Second,
+			MinExcitation:  0.01,
+			MaxGainChange:  0.1,
+		},
+	}
+	// When controlling the process for a single tuning interval
+	runSelfTuningController(t, c, 10*time.Second, func(now time.Duration) float64 {
+		return math.Copysign(1, math.Sin(2*math.Pi*now.Seconds()/5))
+	})
+	// Then the gains should change by at most the limit
+	assert.Equal(t, 1, c.State.Tunings)
+	assert.Assert(t, math.Abs(c.State.ControllerConfig.ProportionalGain-0.22) < 1e-12)
+	assert.Assert(t, math.Abs(c.State.ControllerConfig.IntegralGain-0.055) < 1e-12)
+}
+
+func TestSelfTuningController_Update_NaN(t *testing.T) {
+	// Given a self-tuning controller with state
+	c := &SelfTuningController{
+		Config: SelfTuningControllerConfig{
+			Controller: pid.AntiWindupControllerConfig{
+				ProportionalGain:    0.2,
+				IntegralGain:        0.05,
+				LowPassTimeConstant: 500 * time.Millisecond,
+				MinOutput:           -10,
+				MaxOutput:           10,
+			},
+			Estimator:      ARXEstimatorConfig{OutputOrder: 1, InputOrder: 1, InputDelay: 5, ForgettingFactor: 0.995},
+			Rule:           RuleSIMCPI,
+			TuningInterval: 10 * time.Second,
+			MinExcitation:  0.01,
+			MaxGainChange:  0.5,
+		},
+	}
+	c.Update(pid.AntiWindupControllerInput{ReferenceSignal: 1, SamplingInterval: 100 * time.Millisecond})
+	expected := c.State.Clone()
+	// When updating with an invalid input
+	c.Update(pid.AntiWindupControllerInput{ActualSignal: math.Inf(1), SamplingInterval: 100 * time.Millisecond})
+	// Then the state should be unchanged
+	assert.DeepEqual(t, expected, c.State)
+}
+
+func TestSelfTuningController_DischargeIntegral(t *testing.T) {
+	c := &SelfTuningController{
+		Config: SelfTuningControllerConfig{Controller: pid.AntiWindupControllerConfig{IntegralDischargeTimeConstant: 1}},
+	}
+	c.State.Controller.ControlErrorIntegral = 10
+	c.DischargeIntegral(500 * time.Millisecond)
+	assert.Equal(t, 5.0, c.State.Controller.ControlErrorIntegral)
+}
+
+func TestSelfTuningControllerConfig_Validate(t *testing.T) {
+	valid := SelfTuningControllerConfig{
+		Controller: pid.AntiWindupControllerConfig{
+			ProportionalGain:    0.2,
+			IntegralGain:        0.05,
+			LowPassTimeConstant: 500 * time.Millisecond,
+			MinOutput:           -10,
+			MaxOutput:           10,
+		},
+		Estimator:      ARXEstimatorConfig{OutputOrder: 1, InputOrder: 1, InputDelay: 5, ForgettingFactor: 0.995},
+		Rule:           RuleSIMCPI,
+		TuningInterval: 10 * time.Second,
+		MinExcitation:  0.01,
+		MaxGainChange:  0.5,
+	}
+	for _, tt := range []struct {
+		name     string
+		modify   func(*SelfTuningControllerConfig)
+		expected string
+	}{
+		{
+			name:     "controller",
+			modify:   func(c *SelfTuningControllerConfig) { c.Controller.MinOutput = 20 },
+			expected: "tuning: invalid self-tuning controller config: pid: invalid config",
+		},
+		{
+			name:     "estimator",
+			modify:   func(c *SelfTuningControllerConfig) { c.Estimator.ForgettingFactor = 2 },
+			expected: "tuning: invalid ARX estimator config",
+		},
+		{
+			name:     "rule",
+			modify:   func(c *SelfTuningControllerConfig) { c.Rule = 0 },
+			expected: "unknown rule Rule(0)",
+		},
+		{
+			name:     "tuning interval",
+			modify:   func(c *SelfTuningControllerConfig) { c.TuningInterval = 0 },
+			expected: "non-positive tuning interval 0s",
+		},
+		{
+			name:     "min excitation",
+			modify:   func(c *SelfTuningControllerConfig) { c.MinExcitation = math.NaN() },
+			expected: "invalid min excitation NaN",
+		},
+		{
+			name:     "max gain change",
+			modify:   func(c *SelfTuningControllerConfig) { c.MaxGainChange = -1 },
+			expected: "invalid max gain change -1",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			config := valid
+			tt.modify(&config)
+			assert.ErrorContains(t, config.Validate(), tt.expected)
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package tuning
+
+import (
+	"math"
+	"math/cmplx"
+
+	"go.einride.tech/pid/model"
+)
+
+// SmithPredictorController is the equivalent controller of a pid.SmithPredictor, which wraps the controller
+// C(s) with an internal process model G(s) with dead time and G0(s) without dead time,
+//
+//	C'(s) = C(s) / (1 + C(s) (G0(s) - G(s))).
+//
+// The loop of the equivalent controller and a process can be analysed with ComputeMargins.
+type SmithPredictorController struct {
+	// Controller is the wrapped controller.
+	Controller FrequencyResponder
+	// Model is the internal process model.
+	Model model.Model
+}
+
+var _ FrequencyResponder = SmithPredictorController{}
+
+// FrequencyResponse implements FrequencyResponder.
+func (s SmithPredictorController) FrequencyResponse(omega float64) complex128 {
+	g := s.Model.TransferFunction()
+	delayed := g.FrequencyResponse(omega)
+	g.DeadTime = 0
+	undelayed := g.FrequencyResponse(omega)
+	c := s.Controller.FrequencyResponse(omega)
+	return c / (1 + c*(undelayed-delayed))
+}
+
+// MismatchMargin is the robustness of a Smith predictor loop to a mismatch between its model and the process.
+type MismatchMargin struct {
+	// Margin is the min over frequency of 1 / |T0(jω) Δ(jω)|, where T0 = C G0 / (1 + C G0) is the complementary
+	// sensitivity of the nominal loop without dead time and Δ = (P - G) / G is the relative model error of the
+	// process P. The loop is stable for margins above 1, given a nominal loop that is stable. +Inf for a perfect
+	// model.
+	Margin float64
+	// Frequency is the angular frequency (rad/s) of the margin.
+	Frequency float64
+	// MaxRelativeModelError is the peak of the relative model error |Δ(jω)|.
+	MaxRelativeModelError float64
+}
+
+// ComputeMismatchMargin computes the robustness of the loop of a pid.SmithPredictor with the controller and
+// model to the mismatch between the model and the process, by a logarithmic sweep of the frequency responses
+// from 1e-4 to 1e4 rad/s.
+//
+// The margin is a small-gain condition, as analysed in Palmor, Time-delay compensation – Smith predictor and its
+// modifications, 1996: it is sufficient but not necessary for stability, and conservative for mismatches in
+// phase only, such as a dead time error.
+func ComputeMismatchMargin(controller FrequencyResponder, m model.Model, process FrequencyResponder) MismatchMargin {
+	g := m.TransferFunction()
+	undelayedModel := g
+	undelayedModel.DeadTime = 0
+	result := MismatchMargin{Margin: math.Inf(1)}
+	for i := range marginsSamples {
+		omega := marginsMinFrequency * math.Pow(marginsMaxFrequency/marginsMinFrequency, float64(i)/(marginsSamples-1))
+		loop := controller.FrequencyResponse(omega) * undelayedModel.FrequencyResponse(omega)
+		complementarySensitivity := cmplx.Abs(loop / (1 + loop))
+		modelResponse := g.FrequencyResponse(omega)
+		relativeModelError := cmplx.Abs((process.FrequencyResponse(omega) - modelResponse) / modelResponse)
+		result.MaxRelativeModelError = math.Max(result.MaxRelativeModelError, relativeModelError)
+		if margin := 1 / (complementarySensitivity * relativeModelError); margin < result.Margin {
+			result.Margin = margin
+			result.Frequency = omega
+		}
+	}
+	return result
+}
@@ -0,0 +1,60 @@
+package tuning
+
+import (
+	"math"
+	"math/cmplx"
+	"testing"
+	"time"
+
+	"go.einride.tech/pid/model"
+	"gotest.tools/v3/assert"
+)
+
+func TestSmithPredictorController_FrequencyResponse(t *testing.T) {
+	// Given a Smith predictor with a perfect model of a dead-time dominant process
+	process := model.FOPDT{Gain: 1, TimeConstant: 2 * time.Second, DeadTime: 5 * time.Second}
+	undelayedProcess := process.TransferFunction()
+	undelayedProcess.DeadTime = 0
+	gains := Gains{ProportionalGain: 2, IntegralGain: 1}
+	s := SmithPredictorController{Controller: gains, Model: process}
+	for _, omega := range []float64{0.01, 0.1, 1, 10} {
+		// When computing the closed-loop frequency response of the equivalent controller
+		loop := s.FrequencyResponse(omega) * process.FrequencyResponse(omega)
+		actual := loop / (1 + loop)
+		// Then it should equal the closed loop of the process without dead time, delayed by the dead time
+		undelayedLoop := gains.FrequencyResponse(omega) * undelayedProcess.FrequencyResponse(omega)
+		expected := undelayedLoop / (1 + undelayedLoop) * cmplx.Exp(complex(0, -omega*process.DeadTime.Seconds()))
+		assert.Assert(t, cmplx.Abs(actual-expected) < 1e-9, omega)
+	}
+}
+
+func TestComputeMismatchMargin(t *testing.T) {
+	m := model.FOPDT{Gain: 1, TimeConstant: 2 * time.Second, DeadTime: 5 * time.Second}
+	gains := Gains{ProportionalGain: 2, IntegralGain: 1}
+	t.Run("perfect model", func(t *testing.T) {
+		margin := ComputeMismatchMargin(gains, m, m)
+		assert.Assert(t, math.IsInf(margin.Margin, 1))
+		assert.Equal(t, 0.0, margin.MaxRelativeModelError)
+	})
+	t.Run("gain mismatch", func(t *testing.T) {
+		// Given a process with a 25% higher gain than the model
+		process := m
+		process.Gain = 1.25
+		// When computing the mismatch margin
+		margin := ComputeMismatchMargin(gains, m, process)
+		// Then the relative model error should be the gain error, within the margin of the loop
+		assert.Assert(t, math.Abs(margin.MaxRelativeModelError-0.25) < 1e-9, margin)
+		assert.Assert(t, margin.Margin > 1 && !math.IsInf(margin.Margin, 1), margin)
+	})
+	t.Run("dead time mismatch", func(t *testing.T) {
+		// Given a process with twice the dead time of the model
+		process := m
+		process.DeadTime = 10 * time.Second
+		// When computing the mismatch margin
+		margin := ComputeMismatchMargin(gains, m, process)
+		// Then the relative model error should reach its peak of 2, and robust stability is not guaranteed
+		assert.Assert(t, math.Abs(margin.MaxRelativeModelError-2) < 1e-3, margin)
+		assert.Assert(t, margin.Margin < 1, margin)
+		assert.Assert(t, margin.Frequency > 0, margin)
+	})
+}
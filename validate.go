@@ -0,0 +1,63 @@
+package pid
+
+import (
+	"fmt"
+	"math"
+)
+
+// Validate returns an error when a gain of the config is NaN or infinite.
+func (c ControllerConfigOf[T]) Validate() error {
+	return validateFinite(
+		finiteParameter[T]{"proportional gain", c.ProportionalGain},
+		finiteParameter[T]{"integral gain", c.IntegralGain},
+		finiteParameter[T]{"derivative gain", c.DerivativeGain},
+	)
+}
+
+// Validate returns an error when a parameter of the config is NaN or infinite, when a time constant is
+// negative, when the low-pass time constant is zero, or when MinOutput is larger than MaxOutput.
+//
+// The output limits may be infinite to leave the output unsaturated.
+func (c AntiWindupControllerConfigOf[T]) Validate() error {
+	if err := validateFinite(
+		finiteParameter[T]{"proportional gain", c.ProportionalGain},
+		finiteParameter[T]{"integral gain", c.IntegralGain},
+		finiteParameter[T]{"derivative gain", c.DerivativeGain},
+		finiteParameter[T]{"anti-windup gain", c.AntiWindUpGain},
+		finiteParameter[T]{"integral discharge time constant", c.IntegralDischargeTimeConstant},
+	); err != nil {
+		return err
+	}
+	switch {
+	case c.IntegralDischargeTimeConstant < 0:
+		return fmt.Errorf("pid: invalid config: negative integral discharge time constant %v",
+			c.IntegralDischargeTimeConstant)
+	case c.LowPassTimeConstant <= 0:
+		return fmt.Errorf("pid: invalid config: non-positive low-pass time constant %v", c.LowPassTimeConstant)
+	case math.IsNaN(float64(c.MinOutput)) || math.IsNaN(float64(c.MaxOutput)):
+		return fmt.Errorf("pid: invalid config: NaN output limit")
+	case c.MinOutput > c.MaxOutput:
+		return fmt.Errorf("pid: invalid config: min output %v larger than max output %v", c.MinOutput, c.MaxOutput)
+	}
+	return nil
+}
+
+// Validate returns an error when the config is invalid, with the same rules as
+// AntiWindupControllerConfigOf.Validate.
+func (c TrackingControllerConfigOf[T]) Validate() error {
+	return AntiWindupControllerConfigOf[T](c).Validate()
+}
+
+type finiteParameter[T Float] struct {
+	name  string
+	value T
+}
+
+func validateFinite[T Float](parameters ...finiteParameter[T]) error {
+	for _, parameter := range parameters {
+		if isNaNOrInf(parameter.value) {
+			return fmt.Errorf("pid: invalid config: %s %v not finite", parameter.name, parameter.value)
+		}
+	}
+	return nil
+}
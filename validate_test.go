@@ -0,0 +1,80 @@
+package pid
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestControllerConfig_Validate(t *testing.T) {
+	assert.NilError(t, ControllerConfig{ProportionalGain: 1, IntegralGain: 2, DerivativeGain: 3}.Validate())
+	assert.ErrorContains(t, ControllerConfig{IntegralGain: math.NaN()}.Validate(), "integral gain NaN not finite")
+}
+
+func TestAntiWindupControllerConfig_Validate(t *testing.T) {
+	valid := AntiWindupControllerConfig{
+		ProportionalGain:              1,
+		IntegralGain:                  1,
+		DerivativeGain:                1,
+		AntiWindUpGain:                1,
+		IntegralDischargeTimeConstant: 1,
+		LowPassTimeConstant:           time.Second,
+		MinOutput:                     -1,
+		MaxOutput:                     1,
+	}
+	assert.NilError(t, valid.Validate())
+	for _, tt := range []struct {
+		name     string
+		modify   func(*AntiWindupControllerConfig)
+		expected string
+	}{
+		{
+			name:     "infinite gain",
+			modify:   func(c *AntiWindupControllerConfig) { c.ProportionalGain = math.Inf(1) },
+			expected: "proportional gain +Inf not finite",
+		},
+		{
+			name:     "NaN anti-windup gain",
+			modify:   func(c *AntiWindupControllerConfig) { c.AntiWindUpGain = math.NaN() },
+			expected: "anti-windup gain NaN not finite",
+		},
+		{
+			name:     "negative discharge time constant",
+			modify:   func(c *AntiWindupControllerConfig) { c.IntegralDischargeTimeConstant = -1 },
+			expected: "negative integral discharge time constant",
+		},
+		{
+			name:     "zero low-pass time constant",
+			modify:   func(c *AntiWindupControllerConfig) { c.LowPassTimeConstant = 0 },
+			expected: "non-positive low-pass time constant",
+		},
+		{
+			name:     "NaN output limit",
+			modify:   func(c *AntiWindupControllerConfig) { c.MaxOutput = math.NaN() },
+			expected: "NaN output limit",
+		},
+		{
+			name:     "inverted output limits",
+			modify:   func(c *AntiWindupControllerConfig) { c.MinOutput, c.MaxOutput = 1, -1 },
+			expected: "min output 1 larger than max output -1",
+		},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			config := valid
+			tt.modify(&config)
+			assert.ErrorContains(t, config.Validate(), tt.expected)
+			assert.ErrorContains(t, TrackingControllerConfig(config).Validate(), tt.expected)
+		})
+	}
+}
+
+func TestAntiWindupControllerConfig_Validate_InfiniteOutputLimits(t *testing.T) {
+	config := AntiWindupControllerConfig{
+		LowPassTimeConstant: time.Second,
+		MinOutput:           math.Inf(-1),
+		MaxOutput:           math.Inf(1),
+	}
+	assert.NilError(t, config.Validate())
+}
@@ -0,0 +1,228 @@
+package pid
+
+import (
+	"math"
+	"strings"
+	"time"
+)
+
+// WatchdogController implements an AntiWindupController guarded by a measurement watchdog.
+//
+// The watchdog detects stuck measurements that keep an identical value for too long, stale measurements with
+// timestamps that are too old, implausible jumps in the measurement, and invalid NaN or Inf measurements. On a
+// fault, the controller transitions to a configurable failsafe mode until the measurements have been healthy for
+// the recovery time. On recovery, the guarded controller resumes from the failsafe control signal without a bump,
+// with its integral state initialized from the control signal and its control error derivative reset.
+type WatchdogController struct {
+	// Config for the WatchdogController.
+	Config WatchdogControllerConfig
+	// State of the WatchdogController.
+	State WatchdogControllerState
+}
+
+// WatchdogControllerConfig contains configurable parameters for a WatchdogController.
+type WatchdogControllerConfig struct {
+	// Controller is the config of the guarded controller.
+	Controller AntiWindupControllerConfig
+	// StuckTimeout is the max duration of identical measurements. Zero disables stuck detection.
+	StuckTimeout time.Duration
+	// StaleTimeout is the max age of a measurement. Zero disables stale detection.
+	StaleTimeout time.Duration
+	// MaxRate is the max plausible rate of change of the measurement (1/s). Zero disables jump detection.
+	MaxRate float64
+	// RecoveryTime is the duration of healthy measurements needed to leave the failsafe mode.
+	RecoveryTime time.Duration
+	// Failsafe is the failsafe mode to transition to on a fault.
+	Failsafe FailsafeMode
+	// SafeOutput is the control signal to ramp to in FailsafeRampToSafeOutput mode.
+	SafeOutput float64
+	// RampRate is the rate of change of the control signal (1/s) in FailsafeRampToSafeOutput mode.
+	RampRate float64
+}
+
+// FailsafeMode is the failsafe behavior of a WatchdogController.
+type FailsafeMode int
+
+const (
+	// FailsafeHoldOutput freezes the controller and holds the latest healthy control signal.
+	FailsafeHoldOutput FailsafeMode = iota
+	// FailsafeRampToSafeOutput freezes the controller and ramps the control signal to the safe output.
+	FailsafeRampToSafeOutput
+	// FailsafeDischargeIntegral discharges the controller integral state, and outputs the remaining integral and
+	// feed forward contributions.
+	FailsafeDischargeIntegral
+)
+
+// WatchdogFault is a set of faults detected by a WatchdogController.
+type WatchdogFault uint8
+
+const (
+	// WatchdogFaultStuck is set when the measurement has been identical for longer than the stuck timeout.
+	WatchdogFaultStuck WatchdogFault = 1 << iota
+	// WatchdogFaultStale is set when the measurement is older than the stale timeout.
+	WatchdogFaultStale
+	// WatchdogFaultJump is set when the measurement changed faster than the max rate.
+	WatchdogFaultJump
+	// WatchdogFaultInvalid is set when the measurement or reference is NaN or Inf.
+	WatchdogFaultInvalid
+)
+
+// String returns the names of the faults in the set separated by "|", or "none" for the empty set.
+func (f WatchdogFault) String() string {
+	var names []string
+	for _, fault := range []struct {
+		fault WatchdogFault
+		name  string
+	}{
+		{WatchdogFaultStuck, "stuck"},
+		{WatchdogFaultStale, "stale"},
+		{WatchdogFaultJump, "jump"},
+		{WatchdogFaultInvalid, "invalid"},
+	} {
+		if f&fault.fault != 0 {
+			names = append(names, fault.name)
+		}
+	}
+	if len(names) == 0 {
+		return "none"
+	}
+	return strings.Join(names, "|")
+}
+
+// WatchdogControllerState holds mutable state for a WatchdogController.
+type WatchdogControllerState struct {
+	// Controller is the state of the guarded controller.
+	Controller AntiWindupControllerState
+	// Fault is the set of faults detected in the latest update.
+	Fault WatchdogFault
+	// Failsafe is true when the controller is in failsafe mode.
+	Failsafe bool
+	// ControlSignal is the current control signal output, which differs from the guarded controller control
+	// signal in failsafe mode.
+	ControlSignal float64
+	// ActualSignal is the latest valid measurement.
+	ActualSignal float64
+	// StuckDuration is the duration for which the measurement has been identical.
+	StuckDuration time.Duration
+	// HealthyDuration is the duration for which the measurements have been healthy.
+	HealthyDuration time.Duration
+	// Initialized is true when a valid measurement has been received.
+	Initialized bool
+}
+
+// WatchdogControllerInput holds the input parameters to a WatchdogController.
+type WatchdogControllerInput struct {
+	// ReferenceSignal is the reference value for the signal to control.
+	ReferenceSignal float64
+	// ActualSignal is the actual value of the signal to control.
+	ActualSignal float64
+	// FeedForwardSignal is the contribution of the feed-forward control loop in the controller output.
+	FeedForwardSignal float64
+	// SamplingInterval is the time interval elapsed since the previous call of the controller Update method.
+	SamplingInterval time.Duration
+	// MeasurementTime is the time the actual signal was measured. Zero disables stale detection.
+	MeasurementTime time.Time
+	// Time is the current time, used to compute the age of the measurement. It is required when MeasurementTime is
+	// set, and a measurement with a zero Time is reported as stale, since its age is unknown.
+	Time time.Time
+}
+
+// Reset the controller state.
+func (c *WatchdogController) Reset() {
+	c.State = WatchdogControllerState{}
+}
+
+// Update the controller state.
+func (c *WatchdogController) Update(input WatchdogControllerInput) {
+	c.State.Fault = c.detectFaults(input)
+	if c.State.Fault == 0 {
+		c.State.HealthyDuration += input.SamplingInterval
+	} else {
+		c.State.HealthyDuration = 0
+	}
+	if c.State.Fault&WatchdogFaultInvalid == 0 {
+		c.State.ActualSignal = input.ActualSignal
+		c.State.Initialized = true
+	}
+	var recovered bool
+	switch {
+	case c.State.Fault != 0:
+		c.State.Failsafe = true
+	case c.State.Failsafe && c.State.HealthyDuration >= c.Config.RecoveryTime:
+		c.State.Failsafe, recovered = false, true
+	}
+	controller := AntiWindupController{Config: c.Config.Controller, State: c.State.Controller}
+	if recovered {
+		// Resume from the failsafe control signal, with the integral absorbing the proportional contribution of
+		// the current control error, instead of from the stale state of the guarded controller.
+		e := input.ReferenceSignal - input.ActualSignal
+		controller.State = AntiWindupControllerState{
+			ControlError: e,
+			ControlErrorIntegral: bumplessIntegral(
+				0, 1, 0,
+				c.Config.Controller.ProportionalGain, c.Config.Controller.IntegralGain, c.Config.Controller.DerivativeGain,
+				e, c.State.ControlSignal-input.FeedForwardSignal, 0,
+			),
+			ControlSignal:            c.State.ControlSignal,
+			UnsaturatedControlSignal: c.State.ControlSignal,
+		}
+	}
+	if !c.State.Failsafe {
+		controller.Update(AntiWindupControllerInput{
+			ReferenceSignal:   input.ReferenceSignal,
+			ActualSignal:      input.ActualSignal,
+			FeedForwardSignal: input.FeedForwardSignal,
+			SamplingInterval:  input.SamplingInterval,
+		})
+		c.State.Controller = controller.State
+		c.State.ControlSignal = controller.State.ControlSignal
+		return
+	}
+	switch c.Config.Failsafe {
+	case FailsafeHoldOutput:
+	case FailsafeRampToSafeOutput:
+		step := c.Config.RampRate * input.SamplingInterval.Seconds()
+		if math.Abs(c.State.ControlSignal-c.Config.SafeOutput) < step {
+			c.State.ControlSignal = c.Config.SafeOutput
+		} else if c.State.ControlSignal > c.Config.SafeOutput {
+			c.State.ControlSignal -= step
+		} else {
+			c.State.ControlSignal += step
+		}
+	case FailsafeDischargeIntegral:
+		controller.DischargeIntegral(input.SamplingInterval)
+		c.State.Controller = controller.State
+		c.State.ControlSignal = math.Max(
+			c.Config.Controller.MinOutput,
+			math.Min(
+				c.Config.Controller.MaxOutput,
+				c.Config.Controller.IntegralGain*controller.State.ControlErrorIntegral+input.FeedForwardSignal,
+			),
+		)
+	}
+}
+
+func (c *WatchdogController) detectFaults(input WatchdogControllerInput) WatchdogFault {
+	if math.IsNaN(input.ReferenceSignal) || math.IsNaN(input.ActualSignal) ||
+		math.IsInf(input.ReferenceSignal, 0) || math.IsInf(input.ActualSignal, 0) {
+		return WatchdogFaultInvalid
+	}
+	var fault WatchdogFault
+	if c.State.Initialized && input.ActualSignal == c.State.ActualSignal {
+		c.State.StuckDuration += input.SamplingInterval
+	} else {
+		c.State.StuckDuration = 0
+	}
+	if c.Config.StuckTimeout > 0 && c.State.StuckDuration > c.Config.StuckTimeout {
+		fault |= WatchdogFaultStuck
+	}
+	if c.Config.StaleTimeout > 0 && !input.MeasurementTime.IsZero() &&
+		(input.Time.IsZero() || input.Time.Sub(input.MeasurementTime) > c.Config.StaleTimeout) {
+		fault |= WatchdogFaultStale
+	}
+	if c.Config.MaxRate > 0 && c.State.Initialized &&
+		math.Abs(input.ActualSignal-c.State.ActualSignal) > c.Config.MaxRate*input.SamplingInterval.Seconds() {
+		fault |= WatchdogFaultJump
+	}
+	return fault
+}
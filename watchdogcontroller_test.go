@@ -0,0 +1,264 @@
+package pid
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+)
+
+func TestWatchdogController_Healthy(t *testing.T) {
+	// Given a watchdog controller and a plain controller with the same config
+	c := &WatchdogController{
+		Config: WatchdogControllerConfig{
+			Controller: AntiWindupControllerConfig{
+				LowPassTimeConstant:           1 * time.Second,
+				ProportionalGain:              1,
+				IntegralGain:                  10,
+				IntegralDischargeTimeConstant: 1,
+				MinOutput:                     -10,
+				MaxOutput:                     10,
+			},
+			StuckTimeout: 100 * time.Millisecond,
+			StaleTimeout: 50 * time.Millisecond,
+			MaxRate:      100,
+			RecoveryTime: 30 * time.Millisecond,
+		},
+	}
+	plain := &AntiWindupController{Config: c.Config.Controller}
+	// When updating with healthy measurements
+	for i := range 100 {
+		actual := math.Sin(float64(i) / 10)
+		c.Update(WatchdogControllerInput{ReferenceSignal: 1, ActualSignal: actual, SamplingInterval: dtTest})
+		plain.Update(AntiWindupControllerInput{ReferenceSignal: 1, ActualSignal: actual, SamplingInterval: dtTest})
+	}
+	// Then the watchdog should be transparent
+	assert.Equal(t, WatchdogFault(0), c.State.Fault)
+	assert.Assert(t, !c.State.Failsafe)
+	assert.Equal(t, plain.State, c.State.Controller)
+	assert.Equal(t, plain.State.ControlSignal, c.State.ControlSignal)
+}
+
+func TestWatchdogController_Stuck(t *testing.T) {
+	// Given a watchdog controller holding its output on faults
+	c := &WatchdogController{
+		Config: WatchdogControllerConfig{
+			Controller: AntiWindupControllerConfig{
+				LowPassTimeConstant:           1 * time.Second,
+				ProportionalGain:              1,
+				IntegralGain:                  10,
+				IntegralDischargeTimeConstant: 1,
+				MinOutput:                     -10,
+				MaxOutput:                     10,
+			},
+			StuckTimeout: 100 * time.Millisecond,
+			RecoveryTime: 30 * time.Millisecond,
+			Failsafe:     FailsafeHoldOutput,
+		},
+	}
+	c.Update(WatchdogControllerInput{ReferenceSignal: 1, ActualSignal: 0.5, SamplingInterval: dtTest})
+	// When the measurement is identical for longer than the stuck timeout
+	for range 10 {
+		c.Update(WatchdogControllerInput{ReferenceSignal: 1, ActualSignal: 0.5, SamplingInterval: dtTest})
+		assert.Assert(t, !c.State.Failsafe)
+	}
+	held := c.State.ControlSignal
+	heldState := c.State.Controller
+	c.Update(WatchdogControllerInput{ReferenceSignal: 1, ActualSignal: 0.5, SamplingInterval: dtTest})
+	// Then the controller should hold its output and state
+	assert.Equal(t, WatchdogFaultStuck, c.State.Fault)
+	assert.Assert(t, c.State.Failsafe)
+	assert.Equal(t, held, c.State.ControlSignal)
+	assert.Equal(t, heldState, c.State.Controller)
+	// And recover after the recovery time of healthy measurements
+	for i := range 3 {
+		c.Update(WatchdogControllerInput{
+			ReferenceSignal:  1,
+			ActualSignal:     0.5 + float64(i+1)*0.01,
+			SamplingInterval: dtTest,
+		})
+	}
+	assert.Assert(t, !c.State.Failsafe)
+}
+
+func TestWatchdogController_Stale(t *testing.T) {
+	// Given a watchdog controller ramping to a safe output on faults
+	c := &WatchdogController{
+		Config: WatchdogControllerConfig{
+			Controller: AntiWindupControllerConfig{
+				LowPassTimeConstant:           1 * time.Second,
+				ProportionalGain:              1,
+				IntegralGain:                  10,
+				IntegralDischargeTimeConstant: 1,
+				MinOutput:                     -10,
+				MaxOutput:                     10,
+			},
+			StaleTimeout: 50 * time.Millisecond,
+			Failsafe:     FailsafeRampToSafeOutput,
+			SafeOutput:   0,
+			RampRate:     25,
+		},
+	}
+	now := time.Unix(100, 0)
+	for i := range 10 {
+		c.Update(WatchdogControllerInput{
+			ReferenceSignal:  10,
+			ActualSignal:     float64(i) / 100,
+			SamplingInterval: dtTest,
+			MeasurementTime:  now,
+			Time:             now,
+		})
+		now = now.Add(dtTest)
+	}
+	assert.Equal(t, 10.0, c.State.ControlSignal)
+	// When the measurements are older than the stale timeout
+	for i := range 20 {
+		c.Update(WatchdogControllerInput{
+			ReferenceSignal:  10,
+			ActualSignal:     float64(i) / 100,
+			SamplingInterval: dtTest,
+			MeasurementTime:  now.Add(-60 * time.Millisecond),
+			Time:             now,
+		})
+		now = now.Add(dtTest)
+	}
+	// Then the control signal should ramp towards the safe output
+	assert.Equal(t, WatchdogFaultStale, c.State.Fault)
+	assert.Assert(t, c.State.Failsafe)
+	assert.Assert(t, math.Abs(c.State.ControlSignal-5) < 1e-9)
+	// And stay at the safe output once reached
+	for i := range 30 {
+		c.Update(WatchdogControllerInput{
+			ReferenceSignal:  10,
+			ActualSignal:     float64(i) / 100,
+			SamplingInterval: dtTest,
+			MeasurementTime:  now.Add(-60 * time.Millisecond),
+			Time:             now,
+		})
+		now = now.Add(dtTest)
+	}
+	assert.Equal(t, 0.0, c.State.ControlSignal)
+}
+
+func TestWatchdogController_Stale_ZeroTime(t *testing.T) {
+	// Given a watchdog controller with stale detection
+	c := &WatchdogController{
+		Config: WatchdogControllerConfig{
+			Controller:   AntiWindupControllerConfig{ProportionalGain: 1, MinOutput: -10, MaxOutput: 10},
+			StaleTimeout: 50 * time.Millisecond,
+		},
+	}
+	// When updating with a measurement time but without the current time
+	c.Update(WatchdogControllerInput{
+		ReferenceSignal:  1,
+		SamplingInterval: dtTest,
+		MeasurementTime:  time.Unix(100, 0),
+	})
+	// Then the measurement should be reported as stale
+	assert.Equal(t, WatchdogFaultStale, c.State.Fault)
+	assert.Assert(t, c.State.Failsafe)
+}
+
+func TestWatchdogController_Jump(t *testing.T) {
+	// Given a watchdog controller discharging its integral on faults
+	c := &WatchdogController{
+		Config: WatchdogControllerConfig{
+			Controller: AntiWindupControllerConfig{
+				LowPassTimeConstant:           1 * time.Second,
+				ProportionalGain:              1,
+				IntegralGain:                  10,
+				IntegralDischargeTimeConstant: 1,
+				MinOutput:                     -10,
+				MaxOutput:                     10,
+			},
+			MaxRate:  100,
+			Failsafe: FailsafeDischargeIntegral,
+		},
+	}
+	for i := range 100 {
+		c.Update(WatchdogControllerInput{
+			ReferenceSignal:  1,
+			ActualSignal:     float64(i) / 1000,
+			SamplingInterval: dtTest,
+		})
+	}
+	integral := c.State.Controller.ControlErrorIntegral
+	assert.Assert(t, integral > 0)
+	// When the measurement jumps faster than the max rate
+	c.Update(WatchdogControllerInput{ReferenceSignal: 1, ActualSignal: 5, SamplingInterval: dtTest})
+	// Then the integral should be discharged and output without the P and D contributions
+	assert.Equal(t, WatchdogFaultJump, c.State.Fault)
+	assert.Assert(t, c.State.Failsafe)
+	assert.Equal(t, integral*0.99, c.State.Controller.ControlErrorIntegral)
+	assert.Equal(t, c.Config.Controller.IntegralGain*integral*0.99, c.State.ControlSignal)
+}
+
+func TestWatchdogController_BumplessRecovery(t *testing.T) {
+	// Given a watchdog controller that has ramped to the safe output on a stuck measurement
+	c := &WatchdogController{
+		Config: WatchdogControllerConfig{
+			Controller: AntiWindupControllerConfig{
+				LowPassTimeConstant: 1 * time.Second,
+				ProportionalGain:    1,
+				IntegralGain:        10,
+				DerivativeGain:      0.1,
+				MinOutput:           -10,
+				MaxOutput:           10,
+			},
+			StuckTimeout: 100 * time.Millisecond,
+			RecoveryTime: 30 * time.Millisecond,
+			Failsafe:     FailsafeRampToSafeOutput,
+			SafeOutput:   2,
+			RampRate:     100,
+		},
+	}
+	for range 100 {
+		c.Update(WatchdogControllerInput{ReferenceSignal: 1, ActualSignal: 0.5, SamplingInterval: dtTest})
+	}
+	assert.Assert(t, c.State.Failsafe)
+	assert.Equal(t, 2.0, c.State.ControlSignal)
+	// When the measurements have been healthy for the recovery time
+	actual := 0.5
+	for c.State.Failsafe {
+		actual += 0.001
+		c.Update(WatchdogControllerInput{ReferenceSignal: 1, ActualSignal: actual, SamplingInterval: dtTest})
+	}
+	// Then the controller should resume from the safe output without a bump
+	assert.Assert(t, math.Abs(c.State.ControlSignal-2) < 1e-9, c.State.ControlSignal)
+	assert.Equal(t, 0.0, c.State.Controller.ControlErrorDerivative)
+	// And continue smoothly from there
+	previous := c.State.ControlSignal
+	for range 10 {
+		actual += 0.001
+		c.Update(WatchdogControllerInput{ReferenceSignal: 1, ActualSignal: actual, SamplingInterval: dtTest})
+		assert.Assert(t, math.Abs(c.State.ControlSignal-previous) < 0.1, c.State.ControlSignal)
+		previous = c.State.ControlSignal
+	}
+}
+
+func TestWatchdogController_Invalid(t *testing.T) {
+	c := &WatchdogController{
+		Config: WatchdogControllerConfig{
+			Controller: AntiWindupControllerConfig{ProportionalGain: 1, MinOutput: -10, MaxOutput: 10},
+		},
+	}
+	c.Update(WatchdogControllerInput{ReferenceSignal: 1, ActualSignal: 0, SamplingInterval: dtTest})
+	c.Update(WatchdogControllerInput{ReferenceSignal: 1, ActualSignal: math.NaN(), SamplingInterval: dtTest})
+	assert.Equal(t, WatchdogFaultInvalid, c.State.Fault)
+	assert.Assert(t, c.State.Failsafe)
+	assert.Equal(t, 0.0, c.State.ActualSignal)
+}
+
+func TestWatchdogController_Reset(t *testing.T) {
+	c := &WatchdogController{}
+	c.State = WatchdogControllerState{Failsafe: true, ControlSignal: 1, Fault: WatchdogFaultStale}
+	c.Reset()
+	assert.Equal(t, WatchdogControllerState{}, c.State)
+}
+
+func TestWatchdogFault_String(t *testing.T) {
+	assert.Equal(t, "none", WatchdogFault(0).String())
+	assert.Equal(t, "stale", WatchdogFaultStale.String())
+	assert.Equal(t, "stuck|jump", (WatchdogFaultStuck | WatchdogFaultJump).String())
+}